@@ -0,0 +1,95 @@
+// Package analysis derives fight statistics from parsed Elite Insights logs.
+// Everything here is pure computation over parser types so it can be shared by the
+// TUI cards, exports and the HTTP API.
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// Well-known GW2 buff IDs used by the analyses.
+const (
+	BuffStability = 1122
+	BuffStun      = 872
+	BuffDaze      = 833
+	BuffFear      = 791
+	BuffTaunt     = 27705
+	BuffImmobile  = 727
+)
+
+// controlBuffs are hard and soft CC effects EI tracks as buffs on the victim.
+var controlBuffs = []int64{BuffStun, BuffDaze, BuffFear, BuffTaunt, BuffImmobile}
+
+// SquadPlayers returns the players in the recording player's squad.
+func SquadPlayers(log *parser.ParsedLog) []parser.Player {
+	var players []parser.Player
+	for _, p := range log.Players {
+		if !p.NotInSquad {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// replayTimes extracts the start time in milliseconds of each [start, end] pair.
+func replayTimes(pairs [][]interface{}) []int {
+	var times []int
+	for _, pair := range pairs {
+		if len(pair) == 0 {
+			continue
+		}
+		if t, ok := pair[0].(float64); ok {
+			times = append(times, int(t))
+		}
+	}
+	sort.Ints(times)
+	return times
+}
+
+// DownTimes returns when a player went down, in milliseconds from fight start.
+func DownTimes(p parser.Player) []int {
+	return replayTimes(p.CombatReplayData.Down)
+}
+
+// replayIntervals extracts [start, end] pairs in milliseconds.
+func replayIntervals(pairs [][]interface{}) [][2]int {
+	var out [][2]int
+	for _, pair := range pairs {
+		if len(pair) < 2 {
+			continue
+		}
+		start, ok1 := pair[0].(float64)
+		end, ok2 := pair[1].(float64)
+		if ok1 && ok2 {
+			out = append(out, [2]int{int(start), int(end)})
+		}
+	}
+	return out
+}
+
+// DeathTimes returns when a player died, in milliseconds from fight start.
+func DeathTimes(p parser.Player) []int {
+	return replayTimes(p.CombatReplayData.Dead)
+}
+
+// buff returns the uptime timeline of a buff on a player, if present.
+func buff(p parser.Player, id int64) (parser.BuffUptime, bool) {
+	for _, b := range p.BuffUptimes {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return parser.BuffUptime{}, false
+}
+
+// cumulativeAt reads a cumulative per-second series at a second, clamped to its bounds.
+func cumulativeAt(series []int, second int) int {
+	if len(series) == 0 || second < 0 {
+		return 0
+	}
+	if second >= len(series) {
+		return series[len(series)-1]
+	}
+	return series[second]
+}
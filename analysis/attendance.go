@@ -0,0 +1,115 @@
+package analysis
+
+import "sort"
+
+// PlayerTotals sums one account's squad stats over the fights of a run.
+type PlayerTotals struct {
+	Account    string
+	Name       string // Character name in the most recent fight
+	Profession string
+	Fights     int
+	Damage     int
+	DpsSum     int // Sum of per-fight DPS, see AvgDps
+	Downs      int
+	Deaths     int
+}
+
+// AvgDps is the player's mean DPS over the fights they were in.
+func (t PlayerTotals) AvgDps() int {
+	if t.Fights == 0 {
+		return 0
+	}
+	return t.DpsSum / t.Fights
+}
+
+// SummaryTotals returns per-account totals for the squad over a run's fight
+// summaries, in account order. Fights are expected in fight order.
+func SummaryTotals(fights []FightSummary) []PlayerTotals {
+	totals := make(map[string]*PlayerTotals)
+	for _, f := range fights {
+		for _, p := range f.Players {
+			t := totals[p.Account]
+			if t == nil {
+				t = &PlayerTotals{Account: p.Account}
+				totals[p.Account] = t
+			}
+			t.Name, t.Profession = p.Name, p.Profession
+			t.Fights++
+			t.Damage += p.Damage
+			t.DpsSum += p.Dps
+			t.Downs += p.Downs
+			t.Deaths += p.Deaths
+		}
+	}
+	out := make([]PlayerTotals, 0, len(totals))
+	for _, t := range totals {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Account < out[j].Account })
+	return out
+}
+
+// Presence is which of a run's fights one account was in the squad for.
+type Presence struct {
+	Account string
+	Name    string // Character name in their last fight
+	In      []bool // Per fight, in fight order
+}
+
+// Fights is how many fights the account was in.
+func (p Presence) Fights() int {
+	n := 0
+	for _, in := range p.In {
+		if in {
+			n++
+		}
+	}
+	return n
+}
+
+// Stints are the stretches of consecutive fights the account was in, as first and
+// last fight index.
+func (p Presence) Stints() [][2]int {
+	var stints [][2]int
+	for i, in := range p.In {
+		switch {
+		case !in:
+		case i > 0 && p.In[i-1]:
+			stints[len(stints)-1][1] = i
+		default:
+			stints = append(stints, [2]int{i, i})
+		}
+	}
+	return stints
+}
+
+// RunPresence returns who was in the squad for which of a run's fights, ordered by
+// the fight they joined in, then by account. Fights are expected in fight order.
+func RunPresence(fights []FightSummary) []Presence {
+	byAccount := make(map[string]*Presence)
+	var order []*Presence
+	for i, f := range fights {
+		for _, p := range f.Players {
+			pr := byAccount[p.Account]
+			if pr == nil {
+				pr = &Presence{Account: p.Account, In: make([]bool, len(fights))}
+				byAccount[p.Account] = pr
+				order = append(order, pr)
+			}
+			pr.Name = p.Name
+			pr.In[i] = true
+		}
+	}
+	out := make([]Presence, len(order))
+	for i, pr := range order {
+		out[i] = *pr
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		fi, fj := out[i].Stints()[0][0], out[j].Stints()[0][0]
+		if fi != fj {
+			return fi < fj
+		}
+		return out[i].Account < out[j].Account
+	})
+	return out
+}
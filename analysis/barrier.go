@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// BarrierProvider compares the barrier a squad member applied with how much of it
+// actually absorbed damage.
+type BarrierProvider struct {
+	Name     string
+	Applied  int
+	Bps      int
+	Absorbed int  // Estimated, see BarrierEfficiency
+	Tracked  bool // The log has per-ally barrier data, so Absorbed is meaningful
+}
+
+// WasteRate is the share of applied barrier that never absorbed damage.
+func (b BarrierProvider) WasteRate() float64 {
+	if b.Applied == 0 {
+		return 0
+	}
+	waste := 1 - float64(b.Absorbed)/float64(b.Applied)
+	if waste < 0 {
+		return 0
+	}
+	return waste
+}
+
+// BarrierEfficiency reports full-fight outgoing barrier per squad member, highest first.
+// EI does not say whose barrier absorbed a hit, so each receiver's absorbed/received
+// ratio is credited to providers in proportion to the barrier they gave that receiver.
+func BarrierEfficiency(log *parser.ParsedLog) []BarrierProvider {
+	// Share of received barrier that absorbed damage, per receiving player
+	absorbRate := make([]float64, len(log.Players))
+	for i, p := range log.Players {
+		if len(p.ExtBarrierStats.IncomingBarrier) == 0 || len(p.Defenses) == 0 {
+			continue
+		}
+		received := p.ExtBarrierStats.IncomingBarrier[0].Barrier
+		if received > 0 {
+			absorbRate[i] = float64(p.Defenses[0].DamageBarrier) / float64(received)
+			if absorbRate[i] > 1 {
+				absorbRate[i] = 1
+			}
+		}
+	}
+
+	var out []BarrierProvider
+	for _, p := range log.Players {
+		if p.NotInSquad {
+			continue
+		}
+		b := BarrierProvider{Name: p.Name}
+		if len(p.ExtBarrierStats.OutgoingBarrier) > 0 {
+			b.Applied = p.ExtBarrierStats.OutgoingBarrier[0].Barrier
+			b.Bps = p.ExtBarrierStats.OutgoingBarrier[0].Bps
+		}
+		b.Tracked = len(p.ExtBarrierStats.OutgoingBarrierAllies) > 0
+		var absorbed float64
+		for target, phases := range p.ExtBarrierStats.OutgoingBarrierAllies {
+			if len(phases) > 0 && target < len(absorbRate) {
+				absorbed += float64(phases[0].Barrier) * absorbRate[target]
+			}
+		}
+		b.Absorbed = int(absorbed)
+		out = append(out, b)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Applied > out[j].Applied
+	})
+	return out
+}
@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// BurstWindowSeconds is the length of the push windows AnalyzeBursts looks for.
+const BurstWindowSeconds = 10
+
+// BurstWindow is the stretch of a fight with the most damage.
+type BurstWindow struct {
+	StartMs int
+	EndMs   int
+	Damage  int
+	Players []PlayerBurst // Squad members who dealt or took most of it, highest first
+}
+
+// Dps is the damage per second over the window.
+func (w BurstWindow) Dps() int {
+	if secs := (w.EndMs - w.StartMs) / 1000; secs > 0 {
+		return w.Damage / secs
+	}
+	return w.Damage
+}
+
+// PlayerBurst is one squad member's damage within a burst window.
+type PlayerBurst struct {
+	Name   string
+	Damage int
+}
+
+// Bursts holds the best windows of both sides.
+type Bursts struct {
+	Squad BurstWindow // Our damage dealt
+	Enemy BurstWindow // Damage we took
+	Found bool        // False if the log has no per-second damage timelines
+}
+
+// AnalyzeBursts finds the squad's best 10-second damage window and the enemy's best
+// window against us, from the per-second damage timelines of the full fight.
+func AnalyzeBursts(log *parser.ParsedLog) Bursts {
+	players := SquadPlayers(log)
+	dealt := func(p parser.Player) []int { return firstPhase(p.Damage1S) }
+	taken := func(p parser.Player) []int { return firstPhase(p.DamageTaken1S) }
+	b := Bursts{
+		Squad: bestWindow(players, dealt),
+		Enemy: bestWindow(players, taken),
+	}
+	b.Found = b.Squad.Damage > 0 || b.Enemy.Damage > 0
+	return b
+}
+
+func firstPhase(series [][]int) []int {
+	if len(series) == 0 {
+		return nil
+	}
+	return series[0]
+}
+
+// bestWindow sums the players' cumulative per-second series and returns the window of
+// BurstWindowSeconds with the largest increase. Fights shorter than that are one window.
+func bestWindow(players []parser.Player, series func(parser.Player) []int) BurstWindow {
+	length := 0
+	for _, p := range players {
+		length = max(length, len(series(p)))
+	}
+	if length == 0 {
+		return BurstWindow{}
+	}
+	total := make([]int, length)
+	for _, p := range players {
+		s := series(p)
+		for i := range total {
+			total[i] += cumulativeAt(s, i)
+		}
+	}
+	width := min(BurstWindowSeconds, length-1)
+	best, bestStart := -1, 0
+	for start := 0; start+width < length; start++ {
+		if d := total[start+width] - total[start]; d > best {
+			best, bestStart = d, start
+		}
+	}
+	w := BurstWindow{StartMs: bestStart * 1000, EndMs: (bestStart + width) * 1000, Damage: max(best, 0)}
+	for _, p := range players {
+		s := series(p)
+		if d := cumulativeAt(s, bestStart+width) - cumulativeAt(s, bestStart); d > 0 {
+			w.Players = append(w.Players, PlayerBurst{Name: p.Name, Damage: d})
+		}
+	}
+	sort.SliceStable(w.Players, func(i, j int) bool { return w.Players[i].Damage > w.Players[j].Damage })
+	return w
+}
@@ -0,0 +1,44 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// PlayerDamage is a squad member's target damage over the whole fight.
+type PlayerDamage struct {
+	Name       string
+	Account    string
+	Profession string
+	Damage     int
+	Dps        int
+	ActiveDps  int // Damage per second the player was alive and present, 0 if unknown
+}
+
+// SquadDamage returns target damage per squad member, highest first.
+func SquadDamage(log *parser.ParsedLog) []PlayerDamage {
+	var out []PlayerDamage
+	for _, p := range SquadPlayers(log) {
+		d := PlayerDamage{Name: p.Name, Account: p.Account, Profession: p.Profession}
+		for _, dpsT := range p.DpsTargets {
+			for _, dpsTarget := range dpsT {
+				d.Damage += dpsTarget.Damage
+				d.Dps += dpsTarget.Dps
+			}
+		}
+		d.ActiveDps = ActiveDps(p, d.Damage)
+		out = append(out, d)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Damage > out[j].Damage })
+	return out
+}
+
+// ActiveDps is damage per second of the player's active time, the part of the fight
+// they were alive and in the instance, so late arrivals and early deaths aren't
+// ranked by the whole fight's length. It is 0 when the log has no active times.
+func ActiveDps(p parser.Player, damage int) int {
+	if len(p.ActiveTimes) == 0 || p.ActiveTimes[0] <= 0 {
+		return 0
+	}
+	return int(float64(damage) * 1000 / float64(p.ActiveTimes[0]))
+}
@@ -0,0 +1,56 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"strings"
+)
+
+// HealingCoverage is how much of the squad's healing and barrier the log holds. They
+// come from the arcdps healing stats addon, and only for players who run it too.
+type HealingCoverage struct {
+	Addon   bool // The recorder ran the healing addon
+	Running int  // Squad members whose healing is in the log
+	Squad   int
+}
+
+// Complete reports whether every squad member's healing is in the log.
+func (c HealingCoverage) Complete() bool {
+	return c.Addon && c.Running >= c.Squad
+}
+
+// HealingStats reports whether the log has any healing addon data.
+func HealingStats(log *parser.ParsedLog) bool {
+	for _, p := range SquadPlayers(log) {
+		if len(p.ExtHealingStats.OutgoingHealingAllies) > 0 || len(p.ExtBarrierStats.OutgoingBarrierAllies) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Healing returns the healing addon's coverage of the squad. EI lists who ran the
+// addon; logs that don't say are taken as covering everyone.
+func Healing(log *parser.ParsedLog) HealingCoverage {
+	squad := SquadPlayers(log)
+	c := HealingCoverage{Addon: HealingStats(log), Squad: len(squad)}
+	if !c.Addon {
+		return c
+	}
+	running := make(map[string]bool)
+	listed := false
+	for _, ext := range log.UsedExtensions {
+		if !strings.Contains(strings.ToLower(ext.Name), "heal") {
+			continue
+		}
+		for _, name := range ext.RunningExtension {
+			running[name] = true
+			listed = true
+		}
+	}
+	for _, p := range squad {
+		if !listed || running[p.Name] || running[p.Account] {
+			c.Running++
+		}
+	}
+	return c
+}
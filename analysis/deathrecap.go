@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+const (
+	recapWindowMs = 10000 // How far back a death recap looks
+	recapHits     = 3     // Hardest seconds listed per death
+)
+
+// ccNames are the short names of the CC effects in controlBuffs.
+var ccNames = map[int64]string{
+	BuffStun:     "stun",
+	BuffDaze:     "daze",
+	BuffFear:     "fear",
+	BuffTaunt:    "taunt",
+	BuffImmobile: "immobile",
+}
+
+// DamageSecond is the damage a player took in one second of a fight.
+type DamageSecond struct {
+	Second int // Of the fight
+	Damage int
+}
+
+// DeathRecap is what happened to a squad member in the ten seconds before one of their
+// deaths.
+type DeathRecap struct {
+	Player     string
+	Profession string
+	TimeMs     int
+	Damage     int            // Taken in the window
+	Hits       []DamageSecond // Hardest seconds of the window, hardest first
+	CC         []string       // CC effects they were under, see ccNames
+	Stability  bool           // Had stability at some point in the window
+	StabAtEnd  bool           // Still had it when they died
+	// Pos and TagPos are the combat replay positions of the player and the commander
+	// at the death, if the replay has both.
+	Pos, TagPos [2]float64
+	HasPos      bool
+}
+
+// DeathRecaps reconstructs the last seconds before every squad death in a fight, in
+// time order. tag is the commander to measure distance from, nil if none.
+// EI exports incoming damage per second rather than per hit, so the hardest seconds
+// stand in for the biggest hits.
+func DeathRecaps(log *parser.ParsedLog, tag *parser.Player) []DeathRecap {
+	rate := log.CombatReplayMetaData.PollingRate
+	var out []DeathRecap
+	for _, p := range SquadPlayers(log) {
+		for _, t := range DeathTimes(p) {
+			r := DeathRecap{Player: p.Name, Profession: p.Profession, TimeMs: t}
+			from := max(t-recapWindowMs, 0)
+			if len(p.DamageTaken1S) > 0 {
+				series := p.DamageTaken1S[0]
+				for s := from / 1000; s < t/1000; s++ {
+					dmg := cumulativeAt(series, s+1) - cumulativeAt(series, s)
+					r.Damage += dmg
+					if dmg > 0 {
+						r.Hits = append(r.Hits, DamageSecond{Second: s, Damage: dmg})
+					}
+				}
+				sort.SliceStable(r.Hits, func(i, j int) bool { return r.Hits[i].Damage > r.Hits[j].Damage })
+				if len(r.Hits) > recapHits {
+					r.Hits = r.Hits[:recapHits]
+				}
+			}
+			for _, id := range controlBuffs {
+				if b, ok := buff(p, id); ok && hadStacksIn(b, from, t) {
+					r.CC = append(r.CC, ccNames[id])
+				}
+			}
+			if stab, ok := buff(p, BuffStability); ok {
+				r.Stability = hadStacksIn(stab, from, t)
+				r.StabAtEnd = stab.StacksAt(t) > 0
+			}
+			if tag != nil && rate > 0 {
+				pos, ok1 := positionAt(p, rate, t)
+				tagPos, ok2 := positionAt(*tag, rate, t)
+				r.Pos, r.TagPos, r.HasPos = pos, tagPos, ok1 && ok2
+			}
+			out = append(out, r)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].TimeMs < out[j].TimeMs })
+	return out
+}
+
+// positionAt is a player's combat replay position at a fight time.
+func positionAt(p parser.Player, pollingRate, timeMs int) ([2]float64, bool) {
+	if timeMs < p.CombatReplayData.Start {
+		return [2]float64{}, false
+	}
+	i := (timeMs - p.CombatReplayData.Start) / pollingRate
+	if i >= len(p.CombatReplayData.Positions) || len(p.CombatReplayData.Positions[i]) < 2 {
+		return [2]float64{}, false
+	}
+	pos := p.CombatReplayData.Positions[i]
+	return [2]float64{pos[0], pos[1]}, true
+}
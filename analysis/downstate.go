@@ -0,0 +1,83 @@
+package analysis
+
+import "gw2-cmd-watch/parser"
+
+// deathAfterDownToleranceMs is how close a death must follow the end of a down to
+// count as the down converting into a death.
+const deathAfterDownToleranceMs = 1000
+
+// DownstateEfficiency measures how well downs turn into kills, both ways.
+type DownstateEfficiency struct {
+	EnemyDowns   int
+	EnemyKills   int
+	EnemyPickups int // Enemy downs that did not become kills
+
+	SquadDowns     int
+	SquadDeaths    int // Squad deaths that followed a down
+	SquadRecovered int // Squad downs that were rallied or ressed
+	FromReplay     bool
+}
+
+// CleanupRate is the share of enemy downs converted into kills.
+func (d DownstateEfficiency) CleanupRate() float64 {
+	if d.EnemyDowns == 0 {
+		return 0
+	}
+	return float64(d.EnemyKills) / float64(d.EnemyDowns)
+}
+
+// RecoveryRate is the share of our downs that were picked back up.
+func (d DownstateEfficiency) RecoveryRate() float64 {
+	if d.SquadDowns == 0 {
+		return 0
+	}
+	return float64(d.SquadRecovered) / float64(d.SquadDowns)
+}
+
+// AnalyzeDownstate computes cleanup efficiency from StatsTargets and recovery from
+// combat replay down/dead intervals, falling back to Defenses counts without replay data.
+func AnalyzeDownstate(log *parser.ParsedLog) DownstateEfficiency {
+	var d DownstateEfficiency
+	for _, p := range SquadPlayers(log) {
+		for _, targets := range p.StatsTargets {
+			for _, st := range targets {
+				d.EnemyDowns += st.Downed
+				d.EnemyKills += st.Killed
+			}
+		}
+
+		downs := replayIntervals(p.CombatReplayData.Down)
+		if len(downs) > 0 {
+			d.FromReplay = true
+			deaths := DeathTimes(p)
+			for _, down := range downs {
+				d.SquadDowns++
+				died := false
+				for _, t := range deaths {
+					if t >= down[1]-deathAfterDownToleranceMs && t <= down[1]+deathAfterDownToleranceMs {
+						died = true
+						break
+					}
+				}
+				if died {
+					d.SquadDeaths++
+				} else {
+					d.SquadRecovered++
+				}
+			}
+		} else if len(p.Defenses) > 0 {
+			d.SquadDowns += p.Defenses[0].DownCount
+			died := p.Defenses[0].DeadCount
+			if died > p.Defenses[0].DownCount {
+				died = p.Defenses[0].DownCount
+			}
+			d.SquadDeaths += died
+			d.SquadRecovered += p.Defenses[0].DownCount - died
+		}
+	}
+	d.EnemyPickups = d.EnemyDowns - d.EnemyKills
+	if d.EnemyPickups < 0 {
+		d.EnemyPickups = 0
+	}
+	return d
+}
@@ -0,0 +1,69 @@
+package analysis
+
+// Drive is a stretch of consecutive fights of a run led by the same commander.
+type Drive struct {
+	Commander   string // Account, empty if nobody was tagged all run
+	First, Last int    // Fight indexes
+}
+
+// Fights is how many fights the drive took.
+func (d Drive) Fights() int {
+	return d.Last - d.First + 1
+}
+
+// Drives splits a run's fights by who had the commander tag, so a guild that rotates
+// drivers sees each one's share. A fight where nobody was tagged stays with the
+// commander before it, or the first one tagged at the start of the run. Fights are
+// expected in fight order.
+func Drives(fights []FightSummary) []Drive {
+	var drives []Drive
+	for i, f := range fights {
+		switch {
+		case len(drives) == 0:
+			drives = append(drives, Drive{Commander: f.Commander, First: i, Last: i})
+		case f.Commander == "" || f.Commander == drives[len(drives)-1].Commander:
+			drives[len(drives)-1].Last = i
+		case drives[len(drives)-1].Commander == "":
+			// Untagged fights at the start belong to whoever tagged up first
+			drives[len(drives)-1].Commander = f.Commander
+			drives[len(drives)-1].Last = i
+		default:
+			drives = append(drives, Drive{Commander: f.Commander, First: i, Last: i})
+		}
+	}
+	return drives
+}
+
+// DriverTotals is one commander's share of a run over all their drives.
+type DriverTotals struct {
+	Commander string
+	Fights    int
+	Record    Record
+	Kills     int
+	Deaths    int // Squad deaths
+}
+
+// SplitByDriver sums the fights of each commander over their drives, in the order
+// they first drove.
+func SplitByDriver(fights []FightSummary, drives []Drive, outnumberedRatio float64, excludeOutnumberedLosses bool) []DriverTotals {
+	var out []DriverTotals
+	index := make(map[string]int)
+	byDriver := make(map[string][]FightSummary)
+	for _, d := range drives {
+		if _, ok := index[d.Commander]; !ok {
+			index[d.Commander] = len(out)
+			out = append(out, DriverTotals{Commander: d.Commander})
+		}
+		byDriver[d.Commander] = append(byDriver[d.Commander], fights[d.First:d.Last+1]...)
+	}
+	for i := range out {
+		t := &out[i]
+		for _, f := range byDriver[t.Commander] {
+			t.Fights++
+			t.Kills += f.EnemyDeaths
+			t.Deaths += f.SquadDeaths
+		}
+		t.Record = Tally(byDriver[t.Commander], outnumberedRatio, excludeOutnumberedLosses)
+	}
+	return out
+}
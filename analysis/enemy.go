@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+	"strings"
+)
+
+// EnemyThreat is an enemy player's output against our squad.
+type EnemyThreat struct {
+	Name       string
+	Profession string
+	Damage     int
+	Dps        int
+	Downs      int
+	Kills      int
+}
+
+// EnemyProfession extracts the elite spec EI puts at the start of detailed WvW enemy
+// names, e.g. "Scourge pl-1234" -> "Scourge". It returns "" when the name has no spec.
+func EnemyProfession(name string) string {
+	if i := strings.Index(name, " pl-"); i > 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// EnemyPressure lists enemy players sorted by damage dealt, highest first.
+func EnemyPressure(log *parser.ParsedLog) []EnemyThreat {
+	var threats []EnemyThreat
+	for _, t := range log.Targets {
+		if !t.EnemyPlayer || t.IsFakeTarget {
+			continue
+		}
+		threat := EnemyThreat{Name: t.Name, Profession: EnemyProfession(t.Name)}
+		if len(t.StatsAll) > 0 {
+			threat.Damage = t.StatsAll[0].Dmg
+			threat.Downs = t.StatsAll[0].Downed
+			threat.Kills = t.StatsAll[0].Killed
+		}
+		if len(t.DpsAll) > 0 {
+			threat.Dps = t.DpsAll[0].Dps
+		}
+		threats = append(threats, threat)
+	}
+	sort.SliceStable(threats, func(i, j int) bool {
+		return threats[i].Damage > threats[j].Damage
+	})
+	return threats
+}
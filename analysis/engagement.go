@@ -0,0 +1,60 @@
+package analysis
+
+import "time"
+
+// DefaultEngagementGap is the longest break between two logs of the same engagement.
+const DefaultEngagementGap = 60 * time.Second
+
+// Engagement is a run of logs arcdps split out of one brawl, e.g. re-engages on the
+// same enemy. Totals adds up their damage, downs and deaths; the player counts are the
+// largest of any of its fights, and Start and End span all of them.
+type Engagement struct {
+	First, Last int // Positions of its first and last fight in the input slice
+	Totals      FightSummary
+}
+
+// Fights is the number of logs in the engagement.
+func (e Engagement) Fights() int {
+	return e.Last - e.First + 1
+}
+
+// Duration is the time from the start of the first fight to the end of the last.
+func (e Engagement) Duration() time.Duration {
+	return e.Totals.Duration()
+}
+
+// GroupEngagements groups chronologically ordered fights into engagements. A fight
+// joins the previous one's engagement when it starts within gap of that fight's end.
+// A gap <= 0 uses DefaultEngagementGap.
+func GroupEngagements(fights []FightSummary, gap time.Duration) []Engagement {
+	if gap <= 0 {
+		gap = DefaultEngagementGap
+	}
+	var engagements []Engagement
+	for i, s := range fights {
+		if i == 0 || !reengaged(fights[i-1], s, gap) {
+			engagements = append(engagements, Engagement{First: i, Totals: FightSummary{Start: s.Start}})
+		}
+		e := &engagements[len(engagements)-1]
+		e.Last = i
+		t := &e.Totals
+		t.SquadCount = max(t.SquadCount, s.SquadCount)
+		t.NotInSquadCount = max(t.NotInSquadCount, s.NotInSquadCount)
+		t.EnemyCount = max(t.EnemyCount, s.EnemyCount)
+		t.SquadDmg += s.SquadDmg
+		t.SquadDowns += s.SquadDowns
+		t.SquadDeaths += s.SquadDeaths
+		t.EnemyDmg += s.EnemyDmg
+		t.EnemyDowns += s.EnemyDowns
+		t.EnemyDeaths += s.EnemyDeaths
+		t.End = s.End
+	}
+	return engagements
+}
+
+func reengaged(prev, next FightSummary, gap time.Duration) bool {
+	if prev.End.IsZero() || next.Start.IsZero() {
+		return false
+	}
+	return next.Start.Sub(prev.End) <= gap
+}
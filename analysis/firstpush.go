@@ -0,0 +1,67 @@
+package analysis
+
+import "gw2-cmd-watch/parser"
+
+// FirstPushSeconds is the opening of a fight AnalyzeFirstPush looks at.
+const FirstPushSeconds = 20
+
+// PushStats are both sides' output over part of a fight.
+type PushStats struct {
+	Damage      int // Dealt by the squad
+	DamageTaken int
+	EnemyDowns  int
+	SquadDowns  int
+	EnemyDeaths int
+	SquadDeaths int
+	// StabLost is stability stacks the squad lost, the timed stand-in for incoming
+	// strips with the same upper-bound caveat as StripPressure.StabLost.
+	StabLost int
+}
+
+// FirstPush compares the opening of a fight with the whole of it.
+type FirstPush struct {
+	Opening PushStats
+	Fight   PushStats
+	Found   bool // False if the log has no timelines to split the fight by
+}
+
+// AnalyzeFirstPush computes damage, downs, deaths and stability lost for the first 20
+// seconds from the per-second damage timelines, combat replay data and buff states.
+// EI's strip counts are not timed, so stability stack drops stand in for them.
+func AnalyzeFirstPush(log *parser.ParsedLog) FirstPush {
+	var f FirstPush
+	squad := SquadPlayers(log)
+	openingMs := FirstPushSeconds * 1000
+	for _, p := range squad {
+		dealt, taken := firstPhase(p.Damage1S), firstPhase(p.DamageTaken1S)
+		f.Opening.Damage += cumulativeAt(dealt, FirstPushSeconds)
+		f.Opening.DamageTaken += cumulativeAt(taken, FirstPushSeconds)
+		f.Fight.Damage += cumulativeAt(dealt, len(dealt)-1)
+		f.Fight.DamageTaken += cumulativeAt(taken, len(taken)-1)
+		countBefore(DownTimes(p), openingMs, &f.Opening.SquadDowns, &f.Fight.SquadDowns)
+		countBefore(DeathTimes(p), openingMs, &f.Opening.SquadDeaths, &f.Fight.SquadDeaths)
+		if stab, ok := buff(p, BuffStability); ok {
+			f.Opening.StabLost += stackDropsUntil(stab, openingMs)
+			f.Fight.StabLost += stackDrops(stab)
+		}
+	}
+	for _, t := range log.Targets {
+		if !t.EnemyPlayer || t.IsFakeTarget {
+			continue
+		}
+		countBefore(replayTimes(t.CombatReplayData.Down), openingMs, &f.Opening.EnemyDowns, &f.Fight.EnemyDowns)
+		countBefore(replayTimes(t.CombatReplayData.Dead), openingMs, &f.Opening.EnemyDeaths, &f.Fight.EnemyDeaths)
+	}
+	f.Found = f.Fight != PushStats{}
+	return f
+}
+
+// countBefore adds every time to total and those up to limitMs to opening.
+func countBefore(times []int, limitMs int, opening, total *int) {
+	for _, t := range times {
+		*total++
+		if t <= limitMs {
+			*opening++
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// focusShare is the part of our damage the focus score looks at.
+const focusShare = 0.80
+
+// TargetDamage is the squad's damage on one enemy.
+type TargetDamage struct {
+	Name   string
+	Damage int
+	Share  float64 // Of the squad's damage on all targets
+}
+
+// TargetFocus describes how concentrated the squad's damage was.
+type TargetFocus struct {
+	Targets   []TargetDamage // Enemies hit, most damaged first
+	Total     int
+	FocusedOn int // How many enemies took 80% of the damage
+	// Score is 1 - (FocusedOn-1)/len(Targets): near 1 when the squad burned a few
+	// enemies, low when damage was cleaved evenly over everyone.
+	Score float64
+}
+
+// AnalyzeTargetFocus sums the squad's full-fight damage per target from DpsTargets and
+// counts how many enemies took 80% of it.
+func AnalyzeTargetFocus(log *parser.ParsedLog) TargetFocus {
+	damage := make([]int, len(log.Targets))
+	for _, p := range SquadPlayers(log) {
+		for i, phases := range p.DpsTargets {
+			if i < len(damage) && len(phases) > 0 {
+				damage[i] += phases[0].Damage
+			}
+		}
+	}
+	var f TargetFocus
+	for i, t := range log.Targets {
+		if t.IsFakeTarget || damage[i] <= 0 {
+			continue
+		}
+		f.Targets = append(f.Targets, TargetDamage{Name: t.Name, Damage: damage[i]})
+		f.Total += damage[i]
+	}
+	if f.Total == 0 {
+		return f
+	}
+	sort.SliceStable(f.Targets, func(i, j int) bool { return f.Targets[i].Damage > f.Targets[j].Damage })
+	var sum int
+	for i := range f.Targets {
+		f.Targets[i].Share = float64(f.Targets[i].Damage) / float64(f.Total)
+		if float64(sum) < focusShare*float64(f.Total) {
+			sum += f.Targets[i].Damage
+			f.FocusedOn++
+		}
+	}
+	f.Score = 1 - float64(f.FocusedOn-1)/float64(len(f.Targets))
+	return f
+}
@@ -0,0 +1,81 @@
+package analysis
+
+import "time"
+
+const (
+	// gvgMaxGap is the longest break between two fights of the same match.
+	gvgMaxGap = 5 * time.Minute
+	// gvgCountTolerance is how far the enemy count may drift between rounds (25%).
+	gvgCountTolerance = 0.25
+	// gvgWipeFraction of a side dead counts as a wipe.
+	gvgWipeFraction = 0.8
+)
+
+// GvGRound is one fight of a GvG match. Result is Win or Loss when a side was
+// wiped and Draw otherwise.
+type GvGRound struct {
+	Index   int // Position of the fight in the input slice
+	Summary FightSummary
+	Result  Outcome
+}
+
+// GvGMatch is a series of rounds against the same enemy group.
+type GvGMatch struct {
+	Rounds     []GvGRound
+	Wins       int
+	Losses     int
+	EnemyCount int // Enemy count of the first round
+}
+
+// RoundResult scores a fight by wipe: the side that lost most of its players loses
+// the round. If neither or both sides were wiped there is no winner.
+func RoundResult(s FightSummary) Outcome {
+	enemyWiped := s.EnemyCount > 0 && float64(s.EnemyDeaths) >= float64(s.EnemyCount)*gvgWipeFraction
+	squadWiped := s.SquadCount > 0 && float64(s.SquadDeaths) >= float64(s.SquadCount)*gvgWipeFraction
+	switch {
+	case enemyWiped && !squadWiped:
+		return Win
+	case squadWiped && !enemyWiped:
+		return Loss
+	}
+	return Draw
+}
+
+// TrackGvG groups chronologically ordered fights into matches. Consecutive fights
+// belong to the same match when the enemy count is similar and the gap between them
+// is short.
+func TrackGvG(fights []FightSummary) []GvGMatch {
+	var matches []GvGMatch
+	var prev FightSummary
+	for i, s := range fights {
+		if len(matches) == 0 || !sameOpponent(prev, s) {
+			matches = append(matches, GvGMatch{EnemyCount: s.EnemyCount})
+		}
+		g := &matches[len(matches)-1]
+		round := GvGRound{Index: i, Summary: s, Result: RoundResult(s)}
+		switch round.Result {
+		case Win:
+			g.Wins++
+		case Loss:
+			g.Losses++
+		}
+		g.Rounds = append(g.Rounds, round)
+		prev = s
+	}
+	return matches
+}
+
+func sameOpponent(prev, next FightSummary) bool {
+	if !prev.End.IsZero() && !next.Start.IsZero() && next.Start.Sub(prev.End) > gvgMaxGap {
+		return false
+	}
+	larger := prev.EnemyCount
+	if next.EnemyCount > larger {
+		larger = next.EnemyCount
+	}
+	diff := prev.EnemyCount - next.EnemyCount
+	if diff < 0 {
+		diff = -diff
+	}
+	return larger == 0 || float64(diff) <= float64(larger)*gvgCountTolerance
+}
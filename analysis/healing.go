@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// HealingSplit separates a squad member's outgoing healing by who received it.
+type HealingSplit struct {
+	Name      string
+	Allies    int // Healing to other squad members
+	AlliesHps int
+	Self      int
+	Downed    int // Part of Allies that went to downed players
+}
+
+// HealingBreakdown splits full-fight healing (needs the healing stats addon) and sorts
+// squad members by healing to squadmates, highest first. Healing to allies outside
+// the squad is left out.
+func HealingBreakdown(log *parser.ParsedLog) []HealingSplit {
+	var out []HealingSplit
+	for i, p := range log.Players {
+		if p.NotInSquad {
+			continue
+		}
+		h := HealingSplit{Name: p.Name}
+		// outgoingHealingAllies is indexed by the receiving player, then by phase
+		for target, phases := range p.ExtHealingStats.OutgoingHealingAllies {
+			if len(phases) == 0 {
+				continue
+			}
+			full := phases[0]
+			if target == i {
+				h.Self += full.Healing
+				continue
+			}
+			if target >= len(log.Players) || log.Players[target].NotInSquad {
+				continue
+			}
+			h.Allies += full.Healing
+			h.AlliesHps += full.Hps
+			h.Downed += full.DownedHealing
+		}
+		out = append(out, h)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Allies > out[j].Allies
+	})
+	return out
+}
@@ -0,0 +1,42 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// KillCredit is a squad member's killing blows and downing blows on enemies, as EI
+// credits them. Unlike down contribution it only counts the hit that did it, so it
+// shows who stomps and cleaves rather than who dealt the damage.
+type KillCredit struct {
+	Name       string
+	Account    string
+	Profession string
+	Kills      int // Killing blows, including stomps and cleaves on downed enemies
+	Downs      int // Enemies downed by this player's hit
+}
+
+// KillCredits returns the squad members with at least one kill or down, most kills
+// first, then most downs.
+func KillCredits(log *parser.ParsedLog) []KillCredit {
+	var out []KillCredit
+	for _, p := range SquadPlayers(log) {
+		c := KillCredit{Name: p.Name, Account: p.Account, Profession: p.Profession}
+		for _, st := range p.StatsTargets {
+			for _, target := range st {
+				c.Kills += target.Killed
+				c.Downs += target.Downed
+			}
+		}
+		if c.Kills > 0 || c.Downs > 0 {
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Kills != out[j].Kills {
+			return out[i].Kills > out[j].Kills
+		}
+		return out[i].Downs > out[j].Downs
+	})
+	return out
+}
@@ -0,0 +1,40 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// MinionShare is the target damage dealt by one kind of minion.
+type MinionShare struct {
+	Name   string
+	Damage int
+}
+
+// MinionDamage returns a player's full-fight target damage dealt by minions, pets and
+// turrets, biggest first. EI already includes it in the player's dpsTargets totals.
+func MinionDamage(p parser.Player) (int, []MinionShare) {
+	byName := make(map[string]int)
+	total := 0
+	for _, minion := range p.Minions {
+		for _, phases := range minion.TotalTargetDamage {
+			if len(phases) > 0 {
+				byName[minion.Name] += phases[0]
+				total += phases[0]
+			}
+		}
+	}
+	var shares []MinionShare
+	for name, dmg := range byName {
+		if dmg > 0 {
+			shares = append(shares, MinionShare{Name: name, Damage: dmg})
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Damage != shares[j].Damage {
+			return shares[i].Damage > shares[j].Damage
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	return total, shares
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"fmt"
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// ModifierUptime is how often one damage modifier applied to a player's hits.
+type ModifierUptime struct {
+	Name       string
+	Uptime     float64 // Share of eligible hits the modifier applied to, 0..1
+	DamageGain int
+}
+
+// PlayerModifiers lists a squad member's outgoing damage modifiers, biggest gain first.
+type PlayerModifiers struct {
+	Name       string
+	Profession string
+	TotalGain  int
+	Modifiers  []ModifierUptime
+}
+
+// DamageModifiers reports full-fight outgoing damage modifier uptimes for the squad,
+// sorted by total damage gained. It needs ComputeDamageModifiers in ELI3.conf.
+func DamageModifiers(log *parser.ParsedLog) []PlayerModifiers {
+	var out []PlayerModifiers
+	for _, p := range SquadPlayers(log) {
+		pm := PlayerModifiers{Name: p.Name, Profession: p.Profession}
+		for _, group := range p.DamageModifiers {
+			if len(group.DamageModifiers) == 0 {
+				continue
+			}
+			desc, ok := log.DamageModMap[fmt.Sprintf("d%d", group.ID)]
+			if !ok {
+				desc.Name = fmt.Sprintf("Modifier %d", group.ID)
+			}
+			if desc.Incoming {
+				continue
+			}
+			data := group.DamageModifiers[0]
+			mod := ModifierUptime{Name: desc.Name, DamageGain: int(data.DamageGain)}
+			if data.TotalHitCount > 0 {
+				mod.Uptime = float64(data.HitCount) / float64(data.TotalHitCount)
+			}
+			if mod.Uptime == 0 && mod.DamageGain == 0 {
+				continue
+			}
+			pm.TotalGain += mod.DamageGain
+			pm.Modifiers = append(pm.Modifiers, mod)
+		}
+		sort.SliceStable(pm.Modifiers, func(i, j int) bool {
+			return pm.Modifiers[i].DamageGain > pm.Modifiers[j].DamageGain
+		})
+		out = append(out, pm)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].TotalGain > out[j].TotalGain
+	})
+	return out
+}
@@ -0,0 +1,29 @@
+package analysis
+
+import "gw2-cmd-watch/parser"
+
+// NPCDamage is the squad's damage to NPC targets, guards, lords, siege and gates,
+// which counts for PPT (points per tick) rather than against the enemy zerg.
+type NPCDamage struct {
+	Damage  int
+	Targets int // NPC targets the squad damaged
+}
+
+// SquadNPCDamage sums squad damage to NPC targets.
+func SquadNPCDamage(log *parser.ParsedLog) NPCDamage {
+	var out NPCDamage
+	hit := make(map[int]bool)
+	for _, p := range SquadPlayers(log) {
+		for i, phases := range p.DpsTargets {
+			if i >= len(log.Targets) || !log.Targets[i].IsNPC() || len(phases) == 0 {
+				continue
+			}
+			out.Damage += phases[0].Damage
+			if phases[0].Damage > 0 {
+				hit[i] = true
+			}
+		}
+	}
+	out.Targets = len(hit)
+	return out
+}
@@ -0,0 +1,78 @@
+package analysis
+
+// Outcome is the result of a fight from the squad's point of view.
+type Outcome int
+
+const (
+	Draw Outcome = iota
+	Win
+	Loss
+)
+
+// DefaultOutnumberedRatio flags a fight when enemies outnumber the squad by 30%.
+const DefaultOutnumberedRatio = 1.3
+
+func (o Outcome) String() string {
+	switch o {
+	case Win:
+		return "W"
+	case Loss:
+		return "L"
+	}
+	return "D"
+}
+
+// Outcome compares enemy players killed by the squad with squad deaths.
+func (s FightSummary) Outcome() Outcome {
+	switch {
+	case s.EnemyDeaths > s.SquadDeaths:
+		return Win
+	case s.EnemyDeaths < s.SquadDeaths:
+		return Loss
+	}
+	return Draw
+}
+
+// Outnumbered reports whether enemies exceeded the squad by the given ratio, e.g. 1
+// for any time they had more. An unset ratio (0) uses DefaultOutnumberedRatio.
+func (s FightSummary) Outnumbered(ratio float64) bool {
+	if ratio <= 0 {
+		ratio = DefaultOutnumberedRatio
+	}
+	if s.SquadCount == 0 {
+		return false
+	}
+	return float64(s.EnemyCount) > float64(s.SquadCount)*ratio
+}
+
+// Record is a win/loss tally over several fights.
+type Record struct {
+	Wins, Losses, Draws int
+	OutnumberedFights   int
+	ExcludedLosses      int // Outnumbered losses left out of Losses
+}
+
+// Tally builds a Record. With excludeOutnumberedLosses, losses in outnumbered fights
+// are counted separately instead of as losses.
+func Tally(summaries []FightSummary, ratio float64, excludeOutnumberedLosses bool) Record {
+	var r Record
+	for _, s := range summaries {
+		outnumbered := s.Outnumbered(ratio)
+		if outnumbered {
+			r.OutnumberedFights++
+		}
+		switch s.Outcome() {
+		case Win:
+			r.Wins++
+		case Loss:
+			if outnumbered && excludeOutnumberedLosses {
+				r.ExcludedLosses++
+			} else {
+				r.Losses++
+			}
+		default:
+			r.Draws++
+		}
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+package analysis
+
+import "time"
+
+// povTolerance is how far apart two logs may start and end to be points of view of
+// the same fight. Each recorder's log starts and ends with their own combat.
+const povTolerance = 10 * time.Second
+
+// SameFight reports whether two logs recorded the same fight, e.g. the commander's
+// log and one uploaded by a squad member, by their start and end times.
+func SameFight(a, b FightSummary) bool {
+	if a.Start.IsZero() || b.Start.IsZero() {
+		return false
+	}
+	near := func(x, y time.Time) bool {
+		d := x.Sub(y)
+		return d >= -povTolerance && d <= povTolerance
+	}
+	return near(a.Start, b.Start) && near(a.End, b.End)
+}
+
+// MergePoVs adds the squad members only other points of view of the fight saw to
+// primary, so attendance and trends count everyone once. Totals stay primary's, as
+// the same damage and deaths are in every log.
+func MergePoVs(primary FightSummary, others []FightSummary) FightSummary {
+	seen := make(map[string]bool)
+	for _, p := range primary.Players {
+		seen[p.Account] = true
+	}
+	players := append([]PlayerFight(nil), primary.Players...)
+	for _, o := range others {
+		for _, p := range o.Players {
+			if !seen[p.Account] {
+				seen[p.Account] = true
+				players = append(players, p)
+				primary.SquadCount++
+			}
+		}
+	}
+	primary.Players = players
+	return primary
+}
@@ -0,0 +1,82 @@
+package analysis
+
+// spec is an elite specialization, or a core profession, as EI names it in a
+// player's profession.
+type spec struct {
+	profession string
+	code       string
+}
+
+// specs maps the profession EI reports for a player to its core profession and the
+// short code squads call it by.
+var specs = map[string]spec{
+	"Guardian":     {"Guardian", "GRD"},
+	"Dragonhunter": {"Guardian", "DH"},
+	"Firebrand":    {"Guardian", "FB"},
+	"Willbender":   {"Guardian", "WB"},
+	"Luminary":     {"Guardian", "LUM"},
+
+	"Revenant":   {"Revenant", "REV"},
+	"Herald":     {"Revenant", "HRL"},
+	"Renegade":   {"Revenant", "REN"},
+	"Vindicator": {"Revenant", "VIN"},
+	"Conduit":    {"Revenant", "CON"},
+
+	"Warrior":      {"Warrior", "WAR"},
+	"Berserker":    {"Warrior", "BER"},
+	"Spellbreaker": {"Warrior", "SPB"},
+	"Bladesworn":   {"Warrior", "BLS"},
+	"Paragon":      {"Warrior", "PAR"},
+
+	"Engineer":  {"Engineer", "ENG"},
+	"Scrapper":  {"Engineer", "SCR"},
+	"Holosmith": {"Engineer", "HOL"},
+	"Mechanist": {"Engineer", "MEC"},
+	"Amalgam":   {"Engineer", "AMA"},
+
+	"Ranger":    {"Ranger", "RGR"},
+	"Druid":     {"Ranger", "DRU"},
+	"Soulbeast": {"Ranger", "SLB"},
+	"Untamed":   {"Ranger", "UNT"},
+	"Galeshot":  {"Ranger", "GAL"},
+
+	"Thief":     {"Thief", "THF"},
+	"Daredevil": {"Thief", "DD"},
+	"Deadeye":   {"Thief", "DE"},
+	"Specter":   {"Thief", "SPE"},
+	"Antiquary": {"Thief", "ANT"},
+
+	"Elementalist": {"Elementalist", "ELE"},
+	"Tempest":      {"Elementalist", "TMP"},
+	"Weaver":       {"Elementalist", "WVR"},
+	"Catalyst":     {"Elementalist", "CAT"},
+	"Evoker":       {"Elementalist", "EVO"},
+
+	"Mesmer":       {"Mesmer", "MES"},
+	"Chronomancer": {"Mesmer", "CHR"},
+	"Mirage":       {"Mesmer", "MIR"},
+	"Virtuoso":     {"Mesmer", "VIR"},
+	"Troubadour":   {"Mesmer", "TRB"},
+
+	"Necromancer": {"Necromancer", "NEC"},
+	"Reaper":      {"Necromancer", "RPR"},
+	"Scourge":     {"Necromancer", "SCG"},
+	"Harbinger":   {"Necromancer", "HRB"},
+	"Ritualist":   {"Necromancer", "RIT"},
+}
+
+// CoreProfession returns the profession an elite spec belongs to, e.g. "Scourge" ->
+// "Necromancer". Core professions return themselves, unknown names "".
+func CoreProfession(profession string) string {
+	return specs[profession].profession
+}
+
+// SpecCode is the short name of an elite spec, e.g. "FB" for Firebrand or "SCG" for
+// Scourge. Unknown specs get their first three letters.
+func SpecCode(profession string) string {
+	if s, ok := specs[profession]; ok {
+		return s.code
+	}
+	r := []rune(profession)
+	return string(r[:min(len(r), 3)])
+}
@@ -0,0 +1,106 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+	"strings"
+)
+
+// wvwTriggerID is the EI encounter ID of every WvW log.
+const wvwTriggerID = 1
+
+// IsWvW reports whether a log is a WvW fight rather than a raid, strike or fractal.
+func IsWvW(log *parser.ParsedLog) bool {
+	if log.TriggerID == wvwTriggerID {
+		return true
+	}
+	name := strings.ToLower(log.FightName)
+	return strings.Contains(name, "wvw") || strings.Contains(name, "world vs world")
+}
+
+// BossStatus is how far a PvE target was burned.
+type BossStatus struct {
+	Name        string
+	BurnedPct   float64
+	FinalHealth int
+	TotalHealth int
+}
+
+// Bosses lists the non-player targets with a health pool, in EI order.
+func Bosses(log *parser.ParsedLog) []BossStatus {
+	var out []BossStatus
+	for _, t := range log.Targets {
+		if t.EnemyPlayer || t.IsFakeTarget || t.TotalHealth <= 0 {
+			continue
+		}
+		out = append(out, BossStatus{Name: t.Name, BurnedPct: t.HealthPercentBurned, FinalHealth: t.FinalHealth, TotalHealth: t.TotalHealth})
+	}
+	return out
+}
+
+// MechanicFailures is how often squad members triggered one mechanic.
+type MechanicFailures struct {
+	Name     string
+	Count    int
+	TopActor string // Player who triggered it most
+	TopCount int
+}
+
+// Mechanics counts mechanics triggered by squad members, most frequent first.
+// EI lists both failures and successes (e.g. CCs) here, so the card shows all of them.
+func Mechanics(log *parser.ParsedLog) []MechanicFailures {
+	squad := make(map[string]bool)
+	for _, p := range SquadPlayers(log) {
+		squad[p.Name] = true
+	}
+	var out []MechanicFailures
+	for _, mech := range log.Mechanics {
+		byActor := make(map[string]int)
+		mf := MechanicFailures{Name: mech.Name}
+		for _, d := range mech.MechanicsData {
+			if !squad[d.Actor] {
+				continue
+			}
+			mf.Count++
+			byActor[d.Actor]++
+		}
+		if mf.Count == 0 {
+			continue
+		}
+		for actor, n := range byActor {
+			if n > mf.TopCount || (n == mf.TopCount && actor < mf.TopActor) {
+				mf.TopActor, mf.TopCount = actor, n
+			}
+		}
+		out = append(out, mf)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Count > out[j].Count
+	})
+	return out
+}
+
+// PlayerDps is one squad member's full-fight DPS against all targets.
+type PlayerDps struct {
+	Name       string
+	Profession string
+	Dps        int
+}
+
+// GroupDps returns the squad's combined DPS and each member's share, highest first.
+func GroupDps(log *parser.ParsedLog) (int, []PlayerDps) {
+	total := 0
+	var out []PlayerDps
+	for _, p := range SquadPlayers(log) {
+		pd := PlayerDps{Name: p.Name, Profession: p.Profession}
+		if len(p.DpsAll) > 0 {
+			pd.Dps = p.DpsAll[0].Dps
+		}
+		total += pd.Dps
+		out = append(out, pd)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Dps > out[j].Dps
+	})
+	return total, out
+}
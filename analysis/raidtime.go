@@ -0,0 +1,74 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultIdleGap is the shortest break between fights counted as time away, e.g.
+// waiting on a queue or a long regroup.
+const DefaultIdleGap = 10 * time.Minute
+
+// RaidTime is how a run's time was spent: the clock time from the first fight to the
+// last, how much of it was spent fighting, and the breaks in between.
+type RaidTime struct {
+	First, Last  time.Time     // Start of the first fight, end of the last
+	Combat       time.Duration // Time in fights; logs of the same fight count once
+	Idle         time.Duration // Breaks of at least the idle gap, added up
+	IdleBreaks   int           // How many breaks that is
+	LongestBreak time.Duration
+}
+
+// Span is the raid's clock time, from the start of its first fight to the end of its
+// last.
+func (r RaidTime) Span() time.Duration {
+	if r.First.IsZero() {
+		return 0
+	}
+	return r.Last.Sub(r.First)
+}
+
+// RunTime works out where a run's time went. Fights without timestamps are skipped.
+// Overlapping fights, e.g. logs from several points of view or a split encounter,
+// are merged before their time is added. An idle gap <= 0 uses DefaultIdleGap.
+func RunTime(fights []FightSummary, idleGap time.Duration) RaidTime {
+	if idleGap <= 0 {
+		idleGap = DefaultIdleGap
+	}
+	var timed []FightSummary
+	for _, s := range fights {
+		if s.Duration() > 0 {
+			timed = append(timed, s)
+		}
+	}
+	var r RaidTime
+	if len(timed) == 0 {
+		return r
+	}
+	sort.Slice(timed, func(i, j int) bool { return timed[i].Start.Before(timed[j].Start) })
+	r.First, r.Last = timed[0].Start, timed[0].End
+	start := timed[0].Start
+	for _, s := range timed[1:] {
+		if !s.Start.After(r.Last) {
+			r.Last = maxTime(r.Last, s.End)
+			continue
+		}
+		r.Combat += r.Last.Sub(start)
+		gap := s.Start.Sub(r.Last)
+		r.LongestBreak = max(r.LongestBreak, gap)
+		if gap >= idleGap {
+			r.Idle += gap
+			r.IdleBreaks++
+		}
+		start, r.Last = s.Start, s.End
+	}
+	r.Combat += r.Last.Sub(start)
+	return r
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,118 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// rallyWindowMs is how soon after a squad death an enemy must get up from downed for
+// the death to count as the kill that rallied them.
+const rallyWindowMs = 1000
+
+// RalliesGiven counts, per squad account, the downed enemies who got back up right
+// after that player died. Downed players rally when their side gets a kill, so these
+// are rallies the death likely handed the enemy. Each rally goes to the squad death
+// just before it. EI has no rally events, so this is read from replay timings.
+func RalliesGiven(log *parser.ParsedLog) map[string]int {
+	var rallies []int
+	for _, t := range log.Targets {
+		if !t.EnemyPlayer || t.IsFakeTarget {
+			continue
+		}
+		deaths := replayTimes(t.CombatReplayData.Dead)
+		for _, pair := range t.CombatReplayData.Down {
+			if len(pair) < 2 {
+				continue
+			}
+			end, ok := pair[1].(float64)
+			if !ok || diedAt(deaths, int(end)) {
+				continue
+			}
+			rallies = append(rallies, int(end))
+		}
+	}
+	if len(rallies) == 0 {
+		return nil
+	}
+
+	type death struct {
+		account string
+		time    int
+	}
+	var deaths []death
+	for _, p := range SquadPlayers(log) {
+		for _, t := range DeathTimes(p) {
+			deaths = append(deaths, death{p.Account, t})
+		}
+	}
+	sort.Slice(deaths, func(i, j int) bool { return deaths[i].time < deaths[j].time })
+	given := make(map[string]int)
+	for _, r := range rallies {
+		// The last death at or before the rally
+		i := sort.Search(len(deaths), func(i int) bool { return deaths[i].time > r }) - 1
+		if i >= 0 && r-deaths[i].time <= rallyWindowMs {
+			given[deaths[i].account]++
+		}
+	}
+	return given
+}
+
+// diedAt reports whether one of the sorted death times is at t, give or take a
+// replay tick.
+func diedAt(deaths []int, t int) bool {
+	const tick = 150
+	i := sort.SearchInts(deaths, t-tick)
+	return i < len(deaths) && deaths[i] <= t+tick
+}
+
+// Rallybot is one account's deaths over a run and the enemy rallies they handed out.
+type Rallybot struct {
+	Account string
+	Name    string // Character name in their last fight
+	Fights  int
+	Deaths  int
+	Rallies int
+}
+
+// DeathsPerFight is the player's mean deaths over the fights they were in.
+func (r Rallybot) DeathsPerFight() float64 {
+	if r.Fights == 0 {
+		return 0
+	}
+	return float64(r.Deaths) / float64(r.Fights)
+}
+
+// Rallybots ranks the squad by deaths per fight over a run's fights, then by rallies
+// given. Players who never died are left out.
+func Rallybots(fights []FightSummary) []Rallybot {
+	byAccount := make(map[string]*Rallybot)
+	for _, f := range fights {
+		for _, p := range f.Players {
+			r := byAccount[p.Account]
+			if r == nil {
+				r = &Rallybot{Account: p.Account}
+				byAccount[p.Account] = r
+			}
+			r.Name = p.Name
+			r.Fights++
+			r.Deaths += p.Deaths
+			r.Rallies += p.Rallies
+		}
+	}
+	var out []Rallybot
+	for _, r := range byAccount {
+		if r.Deaths > 0 {
+			out = append(out, *r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if a, b := out[i].DeathsPerFight(), out[j].DeathsPerFight(); a != b {
+			return a > b
+		}
+		if out[i].Rallies != out[j].Rallies {
+			return out[i].Rallies > out[j].Rallies
+		}
+		return out[i].Account < out[j].Account
+	})
+	return out
+}
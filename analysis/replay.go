@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"math"
+)
+
+// ReplayFrame is the state of a fight at one second, read from combat replay data.
+type ReplayFrame struct {
+	Second      int
+	SquadAlive  int
+	SquadDowned int
+	SquadDead   int
+	// Distance of squad members still up or downed from their centroid, in game units.
+	// Both are 0 when the log has no positions.
+	AvgSpread   float64
+	MaxSpread   float64
+	EnemyAlive  int // Enemy players, only listed one by one in detailed WvW logs
+	EnemyDowned int
+	EnemyDead   int
+	HasEnemies  bool // False when the log has no enemy players to count
+}
+
+// FightSeconds is the length of the full fight in whole seconds.
+func FightSeconds(log *parser.ParsedLog) int {
+	return fightLengthMs(log) / 1000
+}
+
+// ReplayAt counts who is up, downed and dead on both sides at a second of the fight,
+// and how spread out the squad is.
+func ReplayAt(log *parser.ParsedLog, second int) ReplayFrame {
+	f := ReplayFrame{Second: second}
+	ms := second * 1000
+	rate := log.CombatReplayMetaData.PollingRate
+	var positions [][2]float64
+	for _, p := range SquadPlayers(log) {
+		switch {
+		case inInterval(p.CombatReplayData.Dead, ms):
+			f.SquadDead++
+			continue
+		case inInterval(p.CombatReplayData.Down, ms):
+			f.SquadDowned++
+		default:
+			f.SquadAlive++
+		}
+		if rate > 0 {
+			if pos, ok := positionAt(p, rate, ms); ok {
+				positions = append(positions, pos)
+			}
+		}
+	}
+	f.AvgSpread, f.MaxSpread = spread(positions)
+
+	for _, t := range log.Targets {
+		if !t.EnemyPlayer || t.IsFakeTarget {
+			continue
+		}
+		f.HasEnemies = true
+		switch {
+		case inInterval(t.CombatReplayData.Dead, ms):
+			f.EnemyDead++
+		case inInterval(t.CombatReplayData.Down, ms):
+			f.EnemyDowned++
+		default:
+			f.EnemyAlive++
+		}
+	}
+	return f
+}
+
+// inInterval reports whether ms falls in one of the replay [start, end] pairs.
+func inInterval(pairs [][]interface{}, ms int) bool {
+	for _, iv := range replayIntervals(pairs) {
+		if ms >= iv[0] && ms <= iv[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// spread is the average and largest distance of the positions from their centroid.
+func spread(positions [][2]float64) (avg, largest float64) {
+	if len(positions) == 0 {
+		return 0, 0
+	}
+	var cx, cy float64
+	for _, p := range positions {
+		cx += p[0]
+		cy += p[1]
+	}
+	cx /= float64(len(positions))
+	cy /= float64(len(positions))
+	var sum float64
+	for _, p := range positions {
+		d := math.Hypot(p[0]-cx, p[1]-cy)
+		sum += d
+		largest = max(largest, d)
+	}
+	return sum / float64(len(positions)), largest
+}
@@ -0,0 +1,46 @@
+package analysis
+
+import "gw2-cmd-watch/parser"
+
+// Player roles used for per-role stat goals.
+const (
+	RoleDPS     = "dps"
+	RoleSupport = "support"
+)
+
+// supportShare is the part of the squad's ally healing or cleanses that marks a player
+// as support. A squad usually runs one support per subgroup, each doing well over this.
+const supportShare = 0.10
+
+// Roles guesses each squad member's role by character name. Players who did a large
+// share of the squad's healing to others or cleanses count as support, everyone else
+// as damage.
+func Roles(log *parser.ParsedLog) map[string]string {
+	healing := make(map[string]int)
+	var totalHealing int
+	for _, h := range HealingBreakdown(log) {
+		healing[h.Name] = h.Allies
+		totalHealing += h.Allies
+	}
+	var totalCleanses int
+	for _, p := range SquadPlayers(log) {
+		if len(p.Support) > 0 {
+			totalCleanses += p.Support[0].CondiCleanse
+		}
+	}
+
+	roles := make(map[string]string)
+	for _, p := range SquadPlayers(log) {
+		role := RoleDPS
+		cleanses := 0
+		if len(p.Support) > 0 {
+			cleanses = p.Support[0].CondiCleanse
+		}
+		if (totalHealing > 0 && float64(healing[p.Name]) >= supportShare*float64(totalHealing)) ||
+			(totalCleanses > 0 && float64(cleanses) >= supportShare*float64(totalCleanses)) {
+			role = RoleSupport
+		}
+		roles[p.Name] = role
+	}
+	return roles
+}
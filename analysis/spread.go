@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// DamageSpread describes how evenly the squad's damage is shared.
+type DamageSpread struct {
+	Total   int
+	Players int
+	Top5    float64 // Share of squad damage dealt by the top 5
+	Top10   float64
+	// Gini is 0 when every damage player dealt the same and approaches 1 when a few
+	// carry the rest. Supports are left out, since their low damage is expected.
+	Gini       float64
+	DpsPlayers int
+}
+
+// AnalyzeDamageSpread computes the top 5 and top 10 shares of squad damage and a Gini
+// coefficient over the damage players.
+func AnalyzeDamageSpread(log *parser.ParsedLog) DamageSpread {
+	players := SquadDamage(log)
+	s := DamageSpread{Players: len(players)}
+	for _, p := range players {
+		s.Total += p.Damage
+	}
+	if s.Total == 0 {
+		return s
+	}
+	var top5, top10 int
+	for i, p := range players {
+		if i < 5 {
+			top5 += p.Damage
+		}
+		if i < 10 {
+			top10 += p.Damage
+		}
+	}
+	s.Top5 = float64(top5) / float64(s.Total)
+	s.Top10 = float64(top10) / float64(s.Total)
+
+	roles := Roles(log)
+	var damage []int
+	for _, p := range players {
+		if roles[p.Name] != RoleSupport {
+			damage = append(damage, p.Damage)
+		}
+	}
+	s.DpsPlayers = len(damage)
+	s.Gini = gini(damage)
+	return s
+}
+
+// gini computes the Gini coefficient of non-negative values.
+func gini(values []int) float64 {
+	n := len(values)
+	if n < 2 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	var sum, weighted float64
+	for i, v := range sorted {
+		sum += float64(v)
+		weighted += float64(i+1) * float64(v)
+	}
+	if sum == 0 {
+		return 0
+	}
+	return 2*weighted/(float64(n)*sum) - float64(n+1)/float64(n)
+}
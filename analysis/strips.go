@@ -0,0 +1,62 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"math"
+	"sort"
+)
+
+// StripPressure is how hard the enemy went after one squad member's boons.
+type StripPressure struct {
+	Name       string
+	Profession string
+	Strips     int // Boons removed from the player (EI defenses.boonStrips)
+	// StabLost is every stability stack drop. EI does not separate strips and corrupts
+	// from stacks consumed by CC or expiring, so treat it as an upper bound.
+	StabLost int
+}
+
+// IncomingStrips lists squad members sorted by boons stripped from them, highest first.
+func IncomingStrips(log *parser.ParsedLog) []StripPressure {
+	var out []StripPressure
+	for _, p := range SquadPlayers(log) {
+		sp := StripPressure{Name: p.Name, Profession: p.Profession}
+		if len(p.Defenses) > 0 {
+			sp.Strips = p.Defenses[0].BoonStrips
+		}
+		if stab, ok := buff(p, BuffStability); ok {
+			sp.StabLost = stackDrops(stab)
+		}
+		out = append(out, sp)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Strips != out[j].Strips {
+			return out[i].Strips > out[j].Strips
+		}
+		return out[i].StabLost > out[j].StabLost
+	})
+	return out
+}
+
+// stackDrops sums every decrease in stack count over a buff timeline.
+func stackDrops(b parser.BuffUptime) int {
+	return stackDropsUntil(b, math.MaxInt)
+}
+
+// stackDropsUntil sums the decreases in stack count up to endMs.
+func stackDropsUntil(b parser.BuffUptime, endMs int) int {
+	drops, prev := 0, 0
+	for _, st := range b.States {
+		if len(st) < 2 {
+			continue
+		}
+		if st[0] > endMs {
+			break
+		}
+		if st[1] < prev {
+			drops += prev - st[1]
+		}
+		prev = st[1]
+	}
+	return drops
+}
@@ -0,0 +1,61 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+// SubgroupTotals is one squad subgroup's (party's) share of a fight.
+type SubgroupTotals struct {
+	Group    int // From 1, 0 if EI didn't say
+	Players  int
+	Damage   int
+	Cleanses int
+	Strips   int
+	Deaths   int
+}
+
+// DamagePerPlayer evens out subgroups of different sizes.
+func (t SubgroupTotals) DamagePerPlayer() int {
+	if t.Players == 0 {
+		return 0
+	}
+	return t.Damage / t.Players
+}
+
+// Subgroups sums damage, cleanses, strips and deaths per squad subgroup, ranked by
+// damage per player, best first.
+func Subgroups(log *parser.ParsedLog) []SubgroupTotals {
+	dmg := make(map[string]int)
+	for _, d := range SquadDamage(log) {
+		dmg[d.Account] = d.Damage
+	}
+	byGroup := make(map[int]*SubgroupTotals)
+	for _, p := range SquadPlayers(log) {
+		t := byGroup[p.Group]
+		if t == nil {
+			t = &SubgroupTotals{Group: p.Group}
+			byGroup[p.Group] = t
+		}
+		t.Players++
+		t.Damage += dmg[p.Account]
+		if len(p.Support) > 0 {
+			t.Cleanses += p.Support[0].CondiCleanse + p.Support[0].CondiCleanseSelf
+			t.Strips += p.Support[0].BoonStrips
+		}
+		if len(p.Defenses) > 0 {
+			t.Deaths += p.Defenses[0].DeadCount
+		}
+	}
+	out := make([]SubgroupTotals, 0, len(byGroup))
+	for _, t := range byGroup {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if a, b := out[i].DamagePerPlayer(), out[j].DamagePerPlayer(); a != b {
+			return a > b
+		}
+		return out[i].Group < out[j].Group
+	})
+	return out
+}
@@ -0,0 +1,141 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"strings"
+	"time"
+)
+
+// eiTimeLayout is the format of timeStartStd / timeEndStd in EI JSON.
+const eiTimeLayout = "2006-01-02 15:04:05 -07:00"
+
+// FightSummary holds the squad and enemy totals shown on the Fight Balance card.
+// It is small enough to keep for every fight in a run.
+type FightSummary struct {
+	SquadCount      int // Players in squad
+	NotInSquadCount int // Allies in the fight but not in squad
+	EnemyCount      int
+
+	SquadDmg    int
+	SquadDps    int
+	SquadDowns  int
+	SquadDeaths int
+
+	EnemyDmg    int
+	EnemyDps    int
+	EnemyDowns  int // Enemies downed by the squad
+	EnemyDeaths int // Enemies killed by the squad
+
+	Start time.Time // Zero if the log has no parseable timestamps
+	End   time.Time
+	Map   string // Short map name, see MapName
+
+	Commander string // Account of the tagged squad member, empty if nobody was tagged
+
+	HealingStats bool // The recorder ran the healing addon, so outgoing healing is in the log
+
+	Players []PlayerFight // Squad members, for per-player trends over a run
+}
+
+// PlayerFight is one squad member's headline stats in a fight.
+type PlayerFight struct {
+	Account    string
+	Name       string
+	Profession string
+	Damage     int
+	Dps        int
+	Cleanses   int
+	Downs      int
+	Deaths     int
+	Rallies    int // Enemy rallies right after their deaths, see RalliesGiven
+
+	Character string // Name before parser.SetNameDisplay, so cached summaries can be renamed
+}
+
+// ZergCount is every allied player in the fight.
+func (s FightSummary) ZergCount() int {
+	return s.SquadCount + s.NotInSquadCount
+}
+
+// Summarize computes the Fight Balance totals for a log.
+func Summarize(log *parser.ParsedLog) FightSummary {
+	var s FightSummary
+	s.Start, _ = time.Parse(eiTimeLayout, log.TimeStartStd)
+	s.End, _ = time.Parse(eiTimeLayout, log.TimeEndStd)
+	s.Map = MapName(log.FightName)
+	s.HealingStats = HealingStats(log)
+	rallies := RalliesGiven(log)
+	for _, p := range log.Players {
+		if p.NotInSquad {
+			s.NotInSquadCount++
+			continue
+		}
+		s.SquadCount++
+		if p.HasCommanderTag && s.Commander == "" {
+			s.Commander = p.Account
+		}
+		pf := PlayerFight{Account: p.Account, Name: p.Name, Profession: p.Profession, Character: p.Character, Rallies: rallies[p.Account]}
+		for _, dpsT := range p.DpsTargets {
+			for _, dpsTarget := range dpsT {
+				s.SquadDps += dpsTarget.Dps
+				s.SquadDmg += dpsTarget.Damage
+				pf.Damage += dpsTarget.Damage
+				pf.Dps += dpsTarget.Dps
+			}
+		}
+		if len(p.Defenses) > 0 {
+			s.SquadDeaths += p.Defenses[0].DeadCount
+			s.SquadDowns += p.Defenses[0].DownCount
+			pf.Downs = p.Defenses[0].DownCount
+			pf.Deaths = p.Defenses[0].DeadCount
+		}
+		if len(p.Support) > 0 {
+			pf.Cleanses = p.Support[0].CondiCleanse + p.Support[0].CondiCleanseSelf
+		}
+		s.Players = append(s.Players, pf)
+		// Count downs and deaths for enemy players
+		// use StatsTargets
+		//this is the correct way to do it, don't change it
+		for _, ST := range p.StatsTargets {
+			for _, stAry := range ST {
+				s.EnemyDowns += stAry.Downed
+				s.EnemyDeaths += stAry.Killed
+			}
+		}
+	}
+	for _, t := range log.Targets {
+		if t.EnemyPlayer && !t.IsFakeTarget {
+			s.EnemyCount++
+			if len(t.StatsAll) > 0 {
+				s.EnemyDmg += t.StatsAll[0].Dmg
+			}
+			if len(t.DpsAll) > 0 {
+				s.EnemyDps += t.DpsAll[0].Dps
+			}
+		}
+	}
+	return s
+}
+
+// Duration is the length of the fight, zero without timestamps.
+func (s FightSummary) Duration() time.Duration {
+	if s.Start.IsZero() || s.End.IsZero() {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// MapName returns the short name of the WvW map in EI's fight name, or "PvE".
+func MapName(fightName string) string {
+	switch {
+	case strings.HasPrefix(fightName, "Detailed WvW - Blue"):
+		return "BBL"
+	case strings.HasPrefix(fightName, "Detailed WvW - Red"):
+		return "RBL"
+	case strings.HasPrefix(fightName, "Detailed WvW - Green"):
+		return "GBL"
+	case strings.HasPrefix(fightName, "Detailed WvW - Eternal"):
+		return "EBG"
+	}
+	return "PvE"
+}
@@ -0,0 +1,47 @@
+package analysis
+
+import "gw2-cmd-watch/parser"
+
+// DownsTimeline counts downs on both sides in equal slices of the fight.
+type DownsTimeline struct {
+	LengthMs int
+	Squad    []int // Squad members going down per slice
+	Enemy    []int // Enemies going down per slice
+	Found    bool  // False if the log has no combat replay downs
+}
+
+// AnalyzeDownsTimeline buckets the down times of squad members and enemy targets
+// from combat replay data into the given number of slices.
+func AnalyzeDownsTimeline(log *parser.ParsedLog, slices int) DownsTimeline {
+	t := DownsTimeline{LengthMs: fightLengthMs(log), Squad: make([]int, slices), Enemy: make([]int, slices)}
+	if t.LengthMs <= 0 || slices <= 0 {
+		return t
+	}
+	add := func(counts []int, times []int) {
+		for _, ms := range times {
+			counts[min(max(ms*slices/t.LengthMs, 0), slices-1)]++
+			t.Found = true
+		}
+	}
+	for _, p := range SquadPlayers(log) {
+		add(t.Squad, DownTimes(p))
+	}
+	for _, target := range log.Targets {
+		if !target.IsFakeTarget {
+			add(t.Enemy, replayTimes(target.CombatReplayData.Down))
+		}
+	}
+	return t
+}
+
+// fightLengthMs is the length of the full fight in milliseconds.
+func fightLengthMs(log *parser.ParsedLog) int {
+	if len(log.Phases) > 0 && log.Phases[0].End > 0 {
+		return int(log.Phases[0].End)
+	}
+	s := Summarize(log)
+	if s.Start.IsZero() || s.End.Before(s.Start) {
+		return 0
+	}
+	return int(s.End.Sub(s.Start).Milliseconds())
+}
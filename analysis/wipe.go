@@ -0,0 +1,161 @@
+package analysis
+
+import (
+	"gw2-cmd-watch/parser"
+	"sort"
+)
+
+const (
+	wipeWindowMs        = 10000 // Look-back window before each death
+	deathClusterGapMs   = 10000 // Deaths closer than this belong to the same cluster
+	damageSpikeFactor   = 2.0   // Window damage this many times the player's average is a spike
+	minDeathClusterSize = 2
+)
+
+// DeathCause records what was happening to a player in the window before they died.
+type DeathCause struct {
+	Player        string
+	TimeMs        int
+	DamageSpike   bool // Took at least twice their average damage for the window
+	WindowDamage  int
+	Controlled    bool // Under a tracked CC effect (stun, daze, fear, taunt, immobile)
+	LostStability bool // Had stability in the window and none at death
+}
+
+// DeathCluster is a group of squad deaths close together in time.
+type DeathCluster struct {
+	StartMs int
+	EndMs   int
+	Deaths  []DeathCause
+}
+
+// WipeReport summarizes the likely causes of the squad's death clusters in one fight.
+type WipeReport struct {
+	Clusters      []DeathCluster
+	TotalDeaths   int
+	Spikes        int
+	Controlled    int
+	LostStability int
+	// HasTimelines is false when the log lacks damageTaken1S and buff states, in which
+	// case only death timing is meaningful.
+	HasTimelines bool
+}
+
+// LikelyCause names the most common factor across clustered deaths.
+func (r WipeReport) LikelyCause() string {
+	best, cause := 0, "no clear pattern"
+	if r.LostStability > best {
+		best, cause = r.LostStability, "stability strip"
+	}
+	if r.Controlled > best {
+		best, cause = r.Controlled, "crowd control"
+	}
+	if r.Spikes > best {
+		cause = "damage spike"
+	}
+	return cause
+}
+
+// AnalyzeWipes correlates incoming damage spikes, CC and stability loss in the ten
+// seconds before each death that is part of a death cluster.
+func AnalyzeWipes(log *parser.ParsedLog) WipeReport {
+	var report WipeReport
+	type death struct {
+		player parser.Player
+		time   int
+	}
+	var deaths []death
+	for _, p := range SquadPlayers(log) {
+		if len(p.DamageTaken1S) > 0 || len(p.BuffUptimes) > 0 {
+			report.HasTimelines = true
+		}
+		for _, t := range DeathTimes(p) {
+			deaths = append(deaths, death{player: p, time: t})
+		}
+	}
+	// Order by time so clustering is a single pass
+	sort.Slice(deaths, func(i, j int) bool { return deaths[i].time < deaths[j].time })
+
+	var current []death
+	flush := func() {
+		if len(current) >= minDeathClusterSize {
+			cluster := DeathCluster{StartMs: current[0].time, EndMs: current[len(current)-1].time}
+			for _, d := range current {
+				cause := deathCause(d.player, d.time)
+				cluster.Deaths = append(cluster.Deaths, cause)
+				report.TotalDeaths++
+				if cause.DamageSpike {
+					report.Spikes++
+				}
+				if cause.Controlled {
+					report.Controlled++
+				}
+				if cause.LostStability {
+					report.LostStability++
+				}
+			}
+			report.Clusters = append(report.Clusters, cluster)
+		}
+		current = nil
+	}
+	for _, d := range deaths {
+		if len(current) > 0 && d.time-current[len(current)-1].time > deathClusterGapMs {
+			flush()
+		}
+		current = append(current, d)
+	}
+	flush()
+	return report
+}
+
+func deathCause(p parser.Player, timeMs int) DeathCause {
+	cause := DeathCause{Player: p.Name, TimeMs: timeMs}
+	windowStart := timeMs - wipeWindowMs
+	if windowStart < 0 {
+		windowStart = 0
+	}
+
+	if len(p.DamageTaken1S) > 0 {
+		series := p.DamageTaken1S[0]
+		cause.WindowDamage = cumulativeAt(series, timeMs/1000) - cumulativeAt(series, windowStart/1000)
+		if len(series) > 0 {
+			total := series[len(series)-1]
+			windows := float64(len(series)) / float64(wipeWindowMs/1000)
+			if windows > 0 && total > 0 {
+				average := float64(total) / windows
+				cause.DamageSpike = float64(cause.WindowDamage) >= average*damageSpikeFactor
+			}
+		}
+	}
+
+	for _, id := range controlBuffs {
+		if b, ok := buff(p, id); ok && hadStacksIn(b, windowStart, timeMs) {
+			cause.Controlled = true
+			break
+		}
+	}
+
+	if stab, ok := buff(p, BuffStability); ok {
+		cause.LostStability = hadStacksIn(stab, windowStart, timeMs) && stab.StacksAt(timeMs) == 0
+	}
+	return cause
+}
+
+// hadStacksIn reports whether the buff had any stacks during [start, end].
+func hadStacksIn(b parser.BuffUptime, start, end int) bool {
+	if b.StacksAt(start) > 0 {
+		return true
+	}
+	for _, st := range b.States {
+		if len(st) < 2 {
+			continue
+		}
+		if st[0] > end {
+			break
+		}
+		if st[0] >= start && st[1] > 0 {
+			return true
+		}
+	}
+	return false
+}
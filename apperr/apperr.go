@@ -0,0 +1,46 @@
+// Package apperr classifies the errors of the log pipeline, so the TUI can tell the
+// user how to fix them rather than only what went wrong.
+package apperr
+
+import "errors"
+
+// Kind is the class of a pipeline error. Kinds are sent between instances, so their
+// values must not change.
+type Kind string
+
+const (
+	CLIMissing    Kind = "cli_missing"    // The Elite Insights CLI is not installed
+	DotNetMissing Kind = "dotnet_missing" // EI cannot start without the .NET runtime
+	ParseFailed   Kind = "parse_failed"   // EI failed on a log or wrote no output for it
+	ArchiveFailed Kind = "archive_failed" // A parsed fight could not be moved into the archive
+	WatcherDead   Kind = "watcher_dead"   // New logs in the watch folder are no longer noticed
+)
+
+// Error is an error of a known kind. Its message is that of the wrapped error.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+// New classifies err.
+func New(kind Kind, err error) error {
+	return &Error{Kind: kind, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of the first classified error in err's chain, or "" if
+// there is none.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+// Package backend carries events from the log processing pipeline to the TUI. The
+// pipeline can run in the same process (Local) or in another instance the TUI is
+// attached to over the web server (Remote).
+package backend
+
+import (
+	"gw2-cmd-watch/apperr"
+	"sync"
+)
+
+// Event kinds.
+const (
+	KindStatus    = "status"    // Text is a status line
+	KindToast     = "toast"     // Text is a short notice that doesn't replace the status
+	KindError     = "error"     // Text is an error message
+	KindProcessed = "processed" // Path is EI's JSON for a new log in the temp folder, Text the log
+	KindArchived  = "archived"  // Path is a fight JSON, relative to the archive
+	KindMap       = "map"       // Live map from MumbleLink
+	KindUpdate    = "update"    // Text is the download URL of a new app version
+	KindParsing   = "parsing"   // Text names the logs EI is parsing, Line is its last output
+	KindParsed    = "parsed"    // EI finished the logs named by Text
+)
+
+// Event is one message from the pipeline.
+type Event struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text,omitempty"`
+	ErrKind string `json:"err_kind,omitempty"` // apperr.Kind of an error, if known
+	Path    string `json:"path,omitempty"`
+	Line    string `json:"line,omitempty"`
+	ParseID int64  `json:"parse_id,omitempty"` // The EI process of a parsing or parsed event
+	Map     string `json:"map,omitempty"`
+	Online  bool   `json:"online,omitempty"`
+	InWvW   bool   `json:"in_wvw,omitempty"`
+}
+
+// Backend delivers pipeline events until it is closed.
+type Backend interface {
+	Events() <-chan Event
+	Close() error
+}
+
+// localBuffer is how many events the pipeline may get ahead of the TUI, e.g. while
+// it starts up.
+const localBuffer = 64
+
+// Local is the pipeline running in this process.
+type Local struct {
+	events chan Event
+	done   chan struct{} // Closed by Close
+
+	mu        sync.RWMutex // Held for reading while an event is published
+	listeners []func(Event)
+	closed    bool
+	closeOnce sync.Once
+}
+
+// NewLocal creates an in-process backend.
+func NewLocal() *Local {
+	return &Local{events: make(chan Event, localBuffer), done: make(chan struct{})}
+}
+
+// Events returns the stream the TUI reads.
+func (l *Local) Events() <-chan Event {
+	return l.events
+}
+
+// Listen also passes every published event to fn, e.g. to share it with attached
+// instances. fn must not block.
+func (l *Local) Listen(fn func(Event)) {
+	l.mu.Lock()
+	l.listeners = append(l.listeners, fn)
+	l.mu.Unlock()
+}
+
+// Publish sends an event to the TUI and listeners. Events published after Close are
+// dropped.
+func (l *Local) Publish(e Event) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	for _, fn := range l.listeners {
+		fn(e)
+	}
+	select {
+	case l.events <- e:
+	case <-l.done:
+	}
+}
+
+// Status publishes a status line.
+func (l *Local) Status(text string) {
+	l.Publish(Event{Kind: KindStatus, Text: text})
+}
+
+// Toast publishes a short notice.
+func (l *Local) Toast(text string) {
+	l.Publish(Event{Kind: KindToast, Text: text})
+}
+
+// Error publishes an error.
+func (l *Local) Error(err error) {
+	l.Publish(Event{Kind: KindError, Text: err.Error(), ErrKind: string(apperr.KindOf(err))})
+}
+
+// Close stops delivering events and closes the events channel, once publishers
+// waiting for the TUI have given up.
+func (l *Local) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.closed = true
+		close(l.events)
+	})
+	return nil
+}
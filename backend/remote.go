@@ -0,0 +1,154 @@
+package backend
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/logger"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEType is the /events stream event type that carries pipeline events.
+const SSEType = "pipeline"
+
+// reconnectDelay is how long Remote waits before reconnecting a dropped stream.
+const reconnectDelay = 5 * time.Second
+
+// Remote follows the pipeline of another instance through its web server's /events
+// stream. Only status, error and archived events are shared, since logs are archived
+// by the instance that parsed them.
+type Remote struct {
+	url    string
+	events chan Event
+	done   chan struct{} // Closed by Close
+
+	mu     sync.Mutex
+	resp   *http.Response
+	closed bool
+}
+
+// Dial connects to a running instance, e.g. "localhost:8080". It fails if nothing is
+// listening there; a connection dropped later is retried until Close.
+func Dial(addr string) (*Remote, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	r := &Remote{url: strings.TrimSuffix(addr, "/") + "/events", events: make(chan Event, localBuffer), done: make(chan struct{})}
+	resp, err := r.connect()
+	if err != nil {
+		return nil, err
+	}
+	go r.run(resp)
+	return r, nil
+}
+
+// Events returns the stream the TUI reads.
+func (r *Remote) Events() <-chan Event {
+	return r.events
+}
+
+// Close disconnects and stops reconnecting. The events channel is closed once the
+// stream has stopped.
+func (r *Remote) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.closed {
+		close(r.done)
+	}
+	r.closed = true
+	if r.resp != nil {
+		return r.resp.Body.Close()
+	}
+	return nil
+}
+
+func (r *Remote) connect() (*http.Response, error) {
+	resp, err := http.Get(r.url)
+	if err != nil {
+		return nil, fmt.Errorf("no running instance at %s: %w", r.url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %s", r.url, resp.Status)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		resp.Body.Close()
+		return nil, fmt.Errorf("closed")
+	}
+	r.resp = resp
+	return resp, nil
+}
+
+func (r *Remote) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+// run reads the stream and reconnects when it drops, until Close.
+func (r *Remote) run(resp *http.Response) {
+	defer close(r.events)
+	r.send(Event{Kind: KindStatus, Text: i18n.T("status.attached", strings.TrimSuffix(r.url, "/events"))})
+	for {
+		err := r.read(resp)
+		if r.isClosed() {
+			return
+		}
+		logger.Warn("attach stream dropped: %v", err)
+		r.send(Event{Kind: KindError, Text: i18n.T("status.attach_lost", err)})
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-time.After(reconnectDelay):
+			}
+			if r.isClosed() {
+				return
+			}
+			if resp, err = r.connect(); err == nil {
+				break
+			}
+		}
+		r.send(Event{Kind: KindStatus, Text: i18n.T("status.reconnected")})
+	}
+}
+
+// send delivers an event unless Close is called while the TUI isn't reading.
+func (r *Remote) send(e Event) {
+	select {
+	case r.events <- e:
+	case <-r.done:
+	}
+}
+
+// read forwards pipeline events from one connection until it ends.
+func (r *Remote) read(resp *http.Response) error {
+	defer resp.Body.Close()
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var eventType string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && eventType == SSEType:
+			var e Event
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e); err == nil {
+				r.send(e)
+			}
+		case line == "":
+			eventType = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("stream ended")
+}
@@ -5,8 +5,166 @@ import (
 	"os"
 )
 
+// DefaultPath is where the app reads and writes its configuration.
+const DefaultPath = "config.json"
+
 type Config struct {
 	WatchFolder string `json:"watch_folder"`
+	// Theme is a built-in theme name or the path to a custom JSON theme file.
+	Theme string `json:"theme,omitempty"`
+	// CardLayout lists the dashboard card IDs to show, one slice per row.
+	CardLayout [][]string `json:"card_layout,omitempty"`
+	// PvECardLayout is the card layout used for raid, strike and fractal logs.
+	PvECardLayout [][]string `json:"pve_card_layout,omitempty"`
+	// NameWidth is the width of the player name column on the cards (default 20).
+	// Longer names are cut with an ellipsis.
+	NameWidth int `json:"name_width,omitempty"`
+	// TempDir and ArchiveDir replace FightLogTemp and Log_Archive next to the app,
+	// e.g. to keep the archive on a big drive and EI output on a fast one.
+	TempDir    string `json:"temp_dir,omitempty"`
+	ArchiveDir string `json:"archive_dir,omitempty"`
+	// ExcludeArchiveFromWatch stops the watcher descending into the archive and temp
+	// directories when they live inside the watch folder, e.g. on a shared drive.
+	ExcludeArchiveFromWatch bool `json:"exclude_archive_from_watch,omitempty"`
+	// MyAccount is preferred as commander when it is in the squad, e.g. when several
+	// players are tagged or you tag up late.
+	MyAccount string `json:"my_account,omitempty"`
+	// OutnumberedRatio flags fights where enemies exceed our allies by this factor (default 1.3).
+	OutnumberedRatio float64 `json:"outnumbered_ratio,omitempty"`
+	// ExcludeOutnumberedLosses leaves outnumbered losses out of W/L records.
+	ExcludeOutnumberedLosses bool `json:"exclude_outnumbered_losses,omitempty"`
+	// SplitByDriver adds each commander's record, kills and deaths to the run overview
+	// when the tag changed hands during a run.
+	SplitByDriver bool `json:"split_by_driver,omitempty"`
+	// ExcludeNPCDamage leaves damage to guards, lords, siege and gates out of the fight
+	// cards, so defending a keep doesn't inflate player damage. Toggled with V.
+	ExcludeNPCDamage bool `json:"exclude_npc_damage,omitempty"`
+	// EngagementGapSeconds groups logs starting within this many seconds of the previous
+	// log's end into one engagement in the run overview (default 60).
+	EngagementGapSeconds int `json:"engagement_gap_seconds,omitempty"`
+	// MaxEIProcesses limits how many Elite Insights processes run at once (default 1).
+	MaxEIProcesses int `json:"max_ei_processes,omitempty"`
+	// EIMemoryLimitMB and EISingleThreaded are written to ELI3.conf as MemoryLimit and
+	// SingleThreaded, to keep large parses from locking up a laptop.
+	EIMemoryLimitMB  int  `json:"ei_memory_limit_mb,omitempty"`
+	EISingleThreaded bool `json:"ei_single_threaded,omitempty"`
+	// EITimeoutSeconds stops a parse that takes longer than this per log (default 300).
+	EITimeoutSeconds int `json:"ei_timeout_seconds,omitempty"`
+	// ArchiveRetries and ArchiveRetryBackoffMs control retries when moving parsed
+	// files into the archive (defaults 3 and 250ms, doubling per retry).
+	ArchiveRetries        int `json:"archive_retries,omitempty"`
+	ArchiveRetryBackoffMs int `json:"archive_retry_backoff_ms,omitempty"`
+	// HTTPEnabled starts the LAN web server on HTTPPort (default 8080).
+	HTTPEnabled bool `json:"http_enabled,omitempty"`
+	HTTPPort    int  `json:"http_port,omitempty"`
+	// ServeReports opens EI HTML reports through the web server instead of as files,
+	// so the links keep working when runs are renamed. Needs HTTPEnabled.
+	ServeReports bool `json:"serve_reports,omitempty"`
+	// Browser is the program reports and links are opened with, e.g. "firefox" or a
+	// full path. Empty uses the system default.
+	Browser string `json:"browser,omitempty"`
+	// UploadTokens maps squad members to their upload secrets. Uploads are only
+	// accepted when at least one is set.
+	UploadTokens map[string]string `json:"upload_tokens,omitempty"`
+	// OverlayPath is written after every fight for OBS; empty disables it.
+	// OverlayTemplate optionally replaces the built-in template.
+	OverlayPath     string `json:"overlay_path,omitempty"`
+	OverlayTemplate string `json:"overlay_template,omitempty"`
+	// FightSummaries writes a small "<fight>.summary.json" with the app's totals next
+	// to each archived fight, for scripts that don't want to parse EI's JSON.
+	FightSummaries bool `json:"fight_summaries,omitempty"`
+	// DiscordBotToken enables the Discord bot. End-of-run reports go to
+	// DiscordReportChannel (a channel ID) when it is set.
+	DiscordBotToken      string `json:"discord_bot_token,omitempty"`
+	DiscordReportChannel string `json:"discord_report_channel,omitempty"`
+	// RallybotReport adds the players with the most deaths per fight, and the enemy
+	// rallies their deaths gave, to the end-of-run report.
+	RallybotReport bool `json:"rallybot_report,omitempty"`
+	// RunReportWebhook posts the end-of-run report to a Discord webhook when a run is
+	// closed. WebhookURL picks the webhook; empty uses the WebhookURL EI posts each
+	// fight to in ELI3.conf.
+	RunReportWebhook bool   `json:"run_report_webhook,omitempty"`
+	WebhookURL       string `json:"webhook_url,omitempty"`
+	// SheetsCredentials is a Google service account key file. When it and
+	// SheetsSpreadsheetID are set, every finished run is appended to the sheet.
+	SheetsCredentials   string `json:"sheets_credentials,omitempty"`
+	SheetsSpreadsheetID string `json:"sheets_spreadsheet_id,omitempty"`
+	// Tray shows a notification area icon with watcher status, queue depth and the
+	// last fight, with a menu to pause watching (Windows only).
+	Tray bool `json:"tray,omitempty"`
+	// MumbleLink follows the game's live map to start and end runs (Windows only).
+	MumbleLink bool `json:"mumble_link,omitempty"`
+	// GW2WorldID or GW2APIKey identify our WvW team for matchup info. The key only
+	// needs the "account" permission.
+	GW2WorldID int    `json:"gw2_world_id,omitempty"`
+	GW2APIKey  string `json:"gw2_api_key,omitempty"`
+	// RunNameTier adds the matchup tier to new run names, e.g. "_T2". It needs
+	// GW2WorldID or GW2APIKey.
+	RunNameTier bool `json:"run_name_tier,omitempty"`
+	// TimeZone (an IANA name, default the machine's zone), TimeFormat and DateFormat
+	// (Go layouts) control how times are shown. NumberLocale picks the thousands
+	// separator, e.g. "en" or "de".
+	TimeZone     string `json:"time_zone,omitempty"`
+	TimeFormat   string `json:"time_format,omitempty"`
+	DateFormat   string `json:"date_format,omitempty"`
+	NumberLocale string `json:"number_locale,omitempty"`
+	// AbbreviateNumbers shows numbers from 10,000 up as 12.3k or 1.2m in the app, so
+	// long damage numbers fit their columns on narrow terminals.
+	AbbreviateNumbers bool `json:"abbreviate_numbers,omitempty"`
+	// PlayerAliases maps account names to nicknames shown instead of character names.
+	// AltAccounts maps alt accounts to a main account so their stats are merged.
+	PlayerAliases map[string]string `json:"player_aliases,omitempty"`
+	AltAccounts   map[string]string `json:"alt_accounts,omitempty"`
+	// NameDisplay names players on the cards by "character" (default), "account", or
+	// "both" as "Character (Name.1234)".
+	NameDisplay string `json:"name_display,omitempty"`
+	// Anonymize replaces player names and accounts with pseudonyms such as "Player 4KQ7M"
+	// in the app and its exports. It only changes what is shown; logs and EI's reports
+	// keep the real names.
+	Anonymize bool `json:"anonymize,omitempty"`
+	// AnonymizeSeed keys the pseudonyms, so each account keeps its pseudonym across
+	// launches but it can't be worked out from a list of accounts. It is generated on
+	// first launch; changing it gives everyone a new pseudonym.
+	AnonymizeSeed string `json:"anonymize_seed,omitempty"`
+	// EIAnonymous turns on Elite Insights' Anonymous option, so the HTML and JSON of
+	// logs parsed while it is on have player names replaced for good, in the app as well.
+	EIAnonymous bool `json:"ei_anonymous,omitempty"`
+	// StatThresholds sets goals per role ("dps", "support" or "all") and stat, e.g.
+	// {"support": {"cleanses": {"warn": 300, "bad": 200}}}. Card values below warn are
+	// shown in the warning color and below bad in red.
+	StatThresholds map[string]map[string]Threshold `json:"stat_thresholds,omitempty"`
+	// Benchmark holds the squad's normal per-fight totals. The Fight Balance card shows
+	// each fight as a percentage of it. Press B in a run to save that run's averages, or
+	// edit the numbers by hand.
+	Benchmark *Benchmark `json:"benchmark,omitempty"`
+	// ReportWindow is the time window of the TUI's period report, e.g. "7d" (default)
+	// or "4w".
+	ReportWindow string `json:"report_window,omitempty"`
+	// ToastSeconds is how long notices like a newly archived fight stay in the corner
+	// of the TUI (default 4).
+	ToastSeconds int `json:"toast_seconds,omitempty"`
+	// Language selects a translation of the interface, e.g. "de" for lang/de.json.
+	Language string `json:"language,omitempty"`
+	// TemplatesDir holds template files that replace the built-in Discord reports,
+	// command-line report, overlay and positions CSV (default "templates").
+	TemplatesDir string `json:"templates_dir,omitempty"`
+}
+
+// Threshold is the goal for one stat. Zero disables a level.
+type Threshold struct {
+	Warn float64 `json:"warn,omitempty"`
+	Bad  float64 `json:"bad,omitempty"`
+}
+
+// Benchmark is an average fight. Zero fields are not compared.
+type Benchmark struct {
+	Source      string  `json:"source,omitempty"` // Run it was saved from
+	SquadDmg    float64 `json:"squad_dmg,omitempty"`
+	SquadDps    float64 `json:"squad_dps,omitempty"`
+	SquadDowns  float64 `json:"squad_downs,omitempty"`
+	SquadDeaths float64 `json:"squad_deaths,omitempty"`
+	EnemyDowns  float64 `json:"enemy_downs,omitempty"`
+	EnemyDeaths float64 `json:"enemy_deaths,omitempty"`
 }
 
 func LoadConfig(path string) (Config, error) {
@@ -25,4 +183,4 @@ func SaveConfig(path string, c *Config) error {
 		return err
 	}
 	return os.WriteFile(path, data, 0644)
-}
\ No newline at end of file
+}
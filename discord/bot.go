@@ -0,0 +1,398 @@
+// Package discord runs an optional Discord bot that answers stat questions from the
+// run archive and posts a report when a run ends.
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"io"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	apiBase    = "https://discord.com/api/v10"
+	gatewayURL = "wss://gateway.discord.gg/?v=10&encoding=json"
+
+	// Guild messages, direct messages and message content. Message content is a
+	// privileged intent and must be switched on in the developer portal.
+	intents = 1<<9 | 1<<12 | 1<<15
+
+	// maxContent is Discord's message length limit.
+	maxContent = 2000
+
+	// maxCommands is how many ! commands are answered at once. Commands arriving while
+	// that many are running are dropped.
+	maxCommands = 2
+)
+
+// Gateway opcodes.
+const (
+	opDispatch       = 0
+	opHeartbeat      = 1
+	opIdentify       = 2
+	opReconnect      = 7
+	opInvalidSession = 9
+	opHello          = 10
+	opHeartbeatACK   = 11
+)
+
+// Close codes after which reconnecting with the same settings can't succeed.
+var fatalCloseCodes = map[int]string{
+	4004: "invalid bot token",
+	4013: "invalid intents",
+	4014: "the Message Content intent is not enabled for this bot",
+}
+
+// Bot is a Discord gateway client answering ! commands.
+type Bot struct {
+	// Rallybot adds the deaths-per-fight ranking to run reports.
+	Rallybot bool
+
+	token         string
+	reportChannel string
+	onError       func(error)
+	client        *http.Client
+	commands      chan struct{} // Semaphore of maxCommands
+}
+
+// New creates a bot. reportChannel is the channel ID that end-of-run reports are posted
+// to, or empty. onError is called for problems the user should see.
+func New(token, reportChannel string, onError func(error)) *Bot {
+	return &Bot{
+		token:         token,
+		reportChannel: reportChannel,
+		onError:       onError,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		commands:      make(chan struct{}, maxCommands),
+	}
+}
+
+// Run stays connected to the gateway, reconnecting with backoff, until the token or
+// intents are rejected.
+func (b *Bot) Run() {
+	backoff := 5 * time.Second
+	for {
+		start := time.Now()
+		err := b.session()
+		var ce *closeError
+		if errors.As(err, &ce) {
+			if reason, fatal := fatalCloseCodes[ce.Code]; fatal {
+				b.onError(fmt.Errorf("discord bot stopped: %s", reason))
+				return
+			}
+		}
+		logger.Warn("discord gateway: %v", err)
+		if time.Since(start) > 5*time.Minute {
+			backoff = 5 * time.Second // The connection was healthy for a while
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, 5*time.Minute)
+	}
+}
+
+type gatewayPayload struct {
+	Op int             `json:"op"`
+	D  json.RawMessage `json:"d"`
+	S  *int64          `json:"s"`
+	T  string          `json:"t"`
+}
+
+type messageCreate struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+	Content   string `json:"content"`
+	Author    struct {
+		Bot bool `json:"bot"`
+	} `json:"author"`
+}
+
+// session runs one gateway connection until it fails.
+func (b *Bot) session() error {
+	conn, err := dialWS(gatewayURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	data, err := conn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	var hello struct {
+		Op int `json:"op"`
+		D  struct {
+			HeartbeatInterval int `json:"heartbeat_interval"`
+		} `json:"d"`
+	}
+	if err := json.Unmarshal(data, &hello); err != nil || hello.Op != opHello {
+		return fmt.Errorf("expected hello from gateway")
+	}
+	if hello.D.HeartbeatInterval <= 0 {
+		return errors.New("gateway sent no heartbeat interval")
+	}
+
+	var seq atomic.Int64
+	seq.Store(-1) // No event received yet
+	var acked atomic.Bool
+	acked.Store(true)
+	done := make(chan struct{})
+	defer close(done)
+	go b.heartbeat(conn, time.Duration(hello.D.HeartbeatInterval)*time.Millisecond, &seq, &acked, done)
+
+	identify := map[string]any{
+		"op": opIdentify,
+		"d": map[string]any{
+			"token":   b.token,
+			"intents": intents,
+			"properties": map[string]string{
+				"os":      "windows",
+				"browser": "gw2-cmd-watch",
+				"device":  "gw2-cmd-watch",
+			},
+		},
+	}
+	if err := writeJSON(conn, identify); err != nil {
+		return err
+	}
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var p gatewayPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			continue
+		}
+		if p.S != nil {
+			seq.Store(*p.S)
+		}
+		switch p.Op {
+		case opHeartbeat:
+			if err := sendHeartbeat(conn, &seq); err != nil {
+				return err
+			}
+		case opHeartbeatACK:
+			acked.Store(true)
+		case opReconnect:
+			return errors.New("gateway asked to reconnect")
+		case opInvalidSession:
+			return errors.New("gateway session invalidated")
+		case opDispatch:
+			if p.T == "READY" {
+				logger.Info("discord bot connected")
+			}
+			if p.T == "MESSAGE_CREATE" {
+				var m messageCreate
+				if err := json.Unmarshal(p.D, &m); err == nil && !m.Author.Bot {
+					b.startCommand(m)
+				}
+			}
+		}
+	}
+}
+
+// heartbeat sends the last sequence number every interval, starting at a random point
+// in the first interval as the gateway asks. If the gateway didn't acknowledge the
+// previous heartbeat, the connection is dead without having failed, so it is closed
+// and Run reconnects.
+func (b *Bot) heartbeat(conn *wsConn, interval time.Duration, seq *atomic.Int64, acked *atomic.Bool, done <-chan struct{}) {
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(interval))))
+	defer timer.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+			if !acked.Swap(false) {
+				logger.Warn("discord gateway stopped acknowledging heartbeats, reconnecting")
+				conn.conn.Close() // Unblocks ReadMessage in session
+				return
+			}
+			if err := sendHeartbeat(conn, seq); err != nil {
+				return
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+func sendHeartbeat(conn *wsConn, seq *atomic.Int64) error {
+	var d any
+	if s := seq.Load(); s >= 0 {
+		d = s
+	}
+	return writeJSON(conn, map[string]any{"op": opHeartbeat, "d": d})
+}
+
+func writeJSON(conn *wsConn, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return conn.WriteText(data)
+}
+
+// startCommand answers a message in the background, unless maxCommands are already
+// being answered.
+func (b *Bot) startCommand(m messageCreate) {
+	if !strings.HasPrefix(strings.TrimSpace(m.Content), "!") {
+		return
+	}
+	select {
+	case b.commands <- struct{}{}:
+	default:
+		logger.Warn("discord bot busy, ignoring message %s", m.ID)
+		return
+	}
+	go func() {
+		defer func() { <-b.commands }()
+		b.handleCommand(m)
+	}()
+}
+
+// handleCommand answers !lastfight, !run and !player.
+func (b *Bot) handleCommand(m messageCreate) {
+	fields := strings.Fields(m.Content)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "!") {
+		return
+	}
+	var reply string
+	switch strings.ToLower(fields[0]) {
+	case "!lastfight":
+		reply = lastFight()
+	case "!run":
+		reply = b.latestRunReport()
+	case "!player":
+		if len(fields) < 2 {
+			reply = "Usage: `!player <account or character name>`"
+		} else {
+			reply = playerInLatestRun(strings.Join(fields[1:], " "))
+		}
+	case "!help":
+		reply = "`!lastfight` latest fight, `!run` current run totals, `!player <name>` a player's run totals"
+	default:
+		return
+	}
+	if err := b.send(m.ChannelID, reply, m.ID); err != nil {
+		logger.Warn("discord reply: %v", err)
+	}
+}
+
+func lastFight() string {
+	run, err := processor.LatestRun()
+	if err != nil || run == "" {
+		return "No runs archived yet."
+	}
+	files, err := processor.RunLogFiles(run)
+	if err != nil || len(files) == 0 {
+		return "No fights in the latest run yet."
+	}
+	last := files[len(files)-1]
+	log, err := parser.ParseLog(last)
+	if err != nil {
+		return fmt.Sprintf("Could not read %s.", filepath.Base(last))
+	}
+	report, err := FightReport(processor.DisplayName(last), log)
+	if err != nil {
+		logger.Warn("discord fight report: %v", err)
+		return "Could not render the fight report, see the event log."
+	}
+	return report
+}
+
+func (b *Bot) latestRunReport() string {
+	run, err := processor.LatestRun()
+	if err != nil || run == "" {
+		return "No runs archived yet."
+	}
+	summaries, err := processor.RunSummaries(run)
+	if err != nil || len(summaries) == 0 {
+		return "No fights in the latest run yet."
+	}
+	report, err := RunReport(run, summaries, b.Rallybot)
+	if err != nil {
+		logger.Warn("discord run report: %v", err)
+		return "Could not render the run report, see the event log."
+	}
+	return report
+}
+
+func playerInLatestRun(query string) string {
+	run, err := processor.LatestRun()
+	if err != nil || run == "" {
+		return "No runs archived yet."
+	}
+	summaries, err := processor.RunSummaries(run)
+	if err != nil || len(summaries) == 0 {
+		return "No fights in the latest run yet."
+	}
+	return PlayerReport(summaries, query)
+}
+
+// PostRunReport posts the report for a finished run to the report channel. It is meant
+// for tui.OnRunClosed.
+func (b *Bot) PostRunReport(runPath string) {
+	if b.reportChannel == "" {
+		return
+	}
+	summaries, err := processor.RunSummaries(runPath)
+	if err != nil || len(summaries) == 0 {
+		return
+	}
+	report, err := RunReport(runPath, summaries, b.Rallybot)
+	if err != nil {
+		b.onError(err)
+		return
+	}
+	if err := b.send(b.reportChannel, report, ""); err != nil {
+		b.onError(fmt.Errorf("failed to post run report to Discord: %w", err))
+	}
+}
+
+// fitContent cuts a message down to Discord's length limit.
+func fitContent(content string) string {
+	if len(content) > maxContent {
+		content = strings.ToValidUTF8(content[:maxContent-3], "") + "..."
+	}
+	return content
+}
+
+// send posts a message, as a reply when replyTo is set.
+func (b *Bot) send(channelID, content, replyTo string) error {
+	body := map[string]any{"content": fitContent(content)}
+	if replyTo != "" {
+		body["message_reference"] = map[string]any{"message_id": replyTo, "fail_if_not_exists": false}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, apiBase+"/channels/"+channelID+"/messages", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bot "+b.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DiscordBot (https://github.com/theextendedname/GW2_Commanders_Watch, 1)")
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("discord returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package discord
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"gw2-cmd-watch/templates"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// topDamage is how many players the reports list.
+const topDamage = 5
+
+// Templates for the fight and run reports, see the templates package.
+var (
+	fightTemplate = templates.Register("discord_fight.tmpl", defaultFightTemplate)
+	runTemplate   = templates.Register("discord_run.tmpl", defaultRunTemplate)
+)
+
+const defaultFightTemplate = `**{{.Name}}** {{.Fight}} ({{.Duration}})
+{{.Outcome}} | {{.Squad}} squad + {{.Allies}} allies vs {{.Enemies}} enemies | Kills {{.Kills}} / Deaths {{.Deaths}}
+` + "```" + `
+{{range $i, $p := .Top}}{{inc $i}}. {{pad 20 $p.Name}} {{lpad 9 $p.Damage}} dmg {{lpad 6 $p.Dps}} dps
+{{end}}` + "```"
+
+const defaultRunTemplate = `**Run report: {{.Run}}**
+Fights: {{.Fights}} ({{.Wins}}W {{.Losses}}L {{.Draws}}D) | Kills {{.Kills}} / Deaths {{.Deaths}} | In combat {{.Combat}}
+Squad: up to {{.MaxSquad}} players, {{.Attended}} attended
+{{if .Drivers}}Drivers: {{join .Drivers " → "}}
+{{end}}{{with .Matchup}}Matchup {{.MatchID}}, skirmish {{.Skirmish}}: vs {{join .Opponents " and "}}
+{{end}}{{if .Top}}` + "```" + `
+{{range $i, $p := .Top}}{{inc $i}}. {{pad 20 $p.Name}} {{lpad 10 $p.Damage}} dmg over {{$p.Fights}} fights
+{{end}}` + "```" + `{{end}}{{if .Rallybots}}
+Rallybots (deaths per fight, enemy rallies given):
+` + "```" + `
+{{range $i, $r := .Rallybots}}{{inc $i}}. {{pad 20 $r.Name}} {{printf "%.2f" $r.DeathsPerFight}} ({{$r.Deaths}} deaths in {{$r.Fights}} fights), {{$r.Rallies}} rallies
+{{end}}` + "```" + `{{end}}`
+
+// FightReportData is what discord_fight.tmpl is rendered with.
+type FightReportData struct {
+	Name     string // Display name of the log
+	Fight    string // EI's fight name, e.g. "Detailed WvW - Eternal Battlegrounds"
+	Duration string
+	Outcome  string // "Win", "Loss" or "Draw"
+	Squad    int
+	Allies   int // Players not in the squad
+	Enemies  int
+	Kills    int
+	Deaths   int
+	Top      []analysis.PlayerDamage // Squad by damage, best first
+}
+
+// FightReport formats one fight for a Discord message.
+func FightReport(name string, log *parser.ParsedLog) (string, error) {
+	s := analysis.Summarize(log)
+	top := analysis.SquadDamage(log)
+	return templates.Render(fightTemplate, FightReportData{
+		Name:     name,
+		Fight:    log.FightName,
+		Duration: log.Duration,
+		Outcome:  outcomeName(s.Outcome()),
+		Squad:    s.SquadCount,
+		Allies:   s.NotInSquadCount,
+		Enemies:  s.EnemyCount,
+		Kills:    s.EnemyDeaths,
+		Deaths:   s.SquadDeaths,
+		Top:      top[:min(len(top), topDamage)],
+	}, nil)
+}
+
+// rallybots is how many players the rallybot ranking lists.
+const rallybots = 3
+
+// RunReportData is what discord_run.tmpl is rendered with.
+type RunReportData struct {
+	Run      string // Run label, e.g. "Name.1234 2025-01-01 20:00 EBG T2"
+	Fights   int
+	Wins     int
+	Losses   int
+	Draws    int
+	Kills    int
+	Deaths   int
+	Combat   time.Duration
+	MaxSquad int // Largest squad in a fight
+	Attended int // Players in at least one fight
+
+	Drivers   []string                // "Commander (fights)" per drive, empty with a single commander
+	Matchup   *processor.Matchup      // nil if unknown
+	Top       []analysis.PlayerTotals // Squad by damage, best first
+	Rallybots []analysis.Rallybot     // Empty unless the rallybot ranking is on
+}
+
+// RunReport formats the totals of a whole run from its fight summaries, see
+// processor.RunSummaries. With rallybot, the players who died most per fight are
+// named too.
+func RunReport(runPath string, summaries []analysis.FightSummary, rallybot bool) (string, error) {
+	data := RunReportData{
+		Run:    processor.RunLabel(filepath.Base(runPath)),
+		Fights: len(summaries),
+	}
+	for _, s := range summaries {
+		if !s.Start.IsZero() && s.End.After(s.Start) {
+			data.Combat += s.End.Sub(s.Start)
+		}
+		data.MaxSquad = max(data.MaxSquad, s.SquadCount)
+		data.Kills += s.EnemyDeaths
+		data.Deaths += s.SquadDeaths
+	}
+	data.Combat = data.Combat.Round(time.Second)
+	rec := analysis.Tally(summaries, analysis.DefaultOutnumberedRatio, false)
+	data.Wins, data.Losses, data.Draws = rec.Wins, rec.Losses, rec.Draws
+	totals := analysis.SummaryTotals(summaries)
+	data.Attended = len(totals)
+
+	if drives := analysis.Drives(summaries); len(drives) > 1 {
+		for _, d := range drives {
+			commander := d.Commander
+			if commander == "" {
+				commander = "no tag"
+			}
+			data.Drivers = append(data.Drivers, fmt.Sprintf("%s (%d)", commander, d.Fights()))
+		}
+	}
+	if meta, err := processor.LoadRunMeta(runPath); err == nil {
+		data.Matchup = meta.Matchup
+	}
+
+	players := append([]analysis.PlayerTotals(nil), totals...)
+	sort.SliceStable(players, func(i, j int) bool { return players[i].Damage > players[j].Damage })
+	data.Top = players[:min(len(players), topDamage)]
+	if rallybot {
+		ranked := analysis.Rallybots(summaries)
+		data.Rallybots = ranked[:min(len(ranked), rallybots)]
+	}
+	return templates.Render(runTemplate, data, nil)
+}
+
+// PlayerReport formats one player's run totals. query matches an account or character
+// name case-insensitively, preferring an exact match over a prefix.
+func PlayerReport(summaries []analysis.FightSummary, query string) string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var found *analysis.PlayerTotals
+	totals := analysis.SummaryTotals(summaries)
+	for i, t := range totals {
+		if strings.EqualFold(t.Account, query) || strings.EqualFold(t.Name, query) {
+			found = &totals[i]
+			break
+		}
+		if found == nil && (strings.HasPrefix(strings.ToLower(t.Account), query) || strings.HasPrefix(strings.ToLower(t.Name), query)) {
+			found = &totals[i]
+		}
+	}
+	if found == nil {
+		return fmt.Sprintf("No player matching %q in the latest run.", query)
+	}
+	return fmt.Sprintf("**%s** (%s, %s)\nFights %d | Damage %d | Avg DPS %d | Downed %d | Died %d",
+		found.Name, found.Account, found.Profession, found.Fights, found.Damage, found.AvgDps(), found.Downs, found.Deaths)
+}
+
+func outcomeName(o analysis.Outcome) string {
+	switch o {
+	case analysis.Win:
+		return "Win"
+	case analysis.Loss:
+		return "Loss"
+	}
+	return "Draw"
+}
@@ -0,0 +1,72 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw2-cmd-watch/processor"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Webhook posts run reports to a Discord webhook, e.g. the one EI already posts each
+// fight to, so the night's totals land in the same channel without a bot.
+type Webhook struct {
+	// Rallybot adds the deaths-per-fight ranking to run reports.
+	Rallybot bool
+
+	url     string
+	onError func(error)
+	client  *http.Client
+}
+
+// NewWebhook creates a poster for the webhook at url. Errors are reported to onError.
+func NewWebhook(url string, onError func(error)) *Webhook {
+	return &Webhook{
+		url:     url,
+		onError: onError,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// PostRunReport posts the report for a finished run. It is meant for tui.OnRunClosed.
+func (w *Webhook) PostRunReport(runPath string) {
+	summaries, err := processor.RunSummaries(runPath)
+	if err != nil || len(summaries) == 0 {
+		return
+	}
+	report, err := RunReport(runPath, summaries, w.Rallybot)
+	if err != nil {
+		w.onError(err)
+		return
+	}
+	if err := w.post(report); err != nil {
+		w.onError(fmt.Errorf("failed to post run report to the webhook: %w", err))
+	}
+}
+
+func (w *Webhook) post(content string) error {
+	data, err := json.Marshal(map[string]any{"content": fitContent(content)})
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		// The URL holds the webhook's token, keep it out of the event log
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("discord returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -3,7 +3,12 @@ package eicli
 import (
 	"archive/zip"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"gw2-cmd-watch/apperr"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/processor"
 	"io"
 	"net/http"
 	"os"
@@ -13,7 +18,6 @@ import (
 const (
 	githubAPIURL = "https://api.github.com/repos/baaron4/GW2-Elite-Insights-Parser/releases/latest"
 	cliDir       = "GW2EICLI"
-	tempDir      = "FightLogTemp" // Using the same temp dir as the processor
 )
 
 // CheckCLIExists verifies if the Elite Insights CLI executable is present.
@@ -24,20 +28,20 @@ func CheckCLIExists() bool {
 }
 
 // InstallCLI downloads and unzips the latest Elite Insights CLI if it's not already present.
-// It sends status updates via the provided channel.
-func InstallCLI(statusChan chan<- string) {
+// It sends status updates via the provided channel. A failed install is an
+// apperr.CLIMissing error.
+func InstallCLI(statusChan chan<- string) error {
 	if CheckCLIExists() {
-		statusChan <- "Elite Insights CLI found."
-		return
+		sendStatus(statusChan, i18n.T("eicli.found"))
+		return nil
 	}
 
-	statusChan <- "Elite Insights CLI not found. Downloading..."
+	sendStatus(statusChan, i18n.T("eicli.missing"))
 
 	// 1. Get latest release info from GitHub
 	resp, err := http.Get(githubAPIURL)
 	if err != nil {
-		statusChan <- fmt.Sprintf("Error getting release info: %v", err)
-		return
+		return installError(fmt.Errorf("error getting release info: %w", err))
 	}
 	defer resp.Body.Close()
 
@@ -49,8 +53,7 @@ func InstallCLI(statusChan chan<- string) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		statusChan <- fmt.Sprintf("Error parsing release info: %v", err)
-		return
+		return installError(fmt.Errorf("error parsing release info: %w", err))
 	}
 
 	// 2. Find the correct download URL for "GW2EICLI.zip"
@@ -63,27 +66,42 @@ func InstallCLI(statusChan chan<- string) {
 	}
 
 	if downloadURL == "" {
-		statusChan <- "Error: Could not find GW2EICLI.zip in the latest release."
-		return
+		return installError(errors.New("could not find GW2EICLI.zip in the latest release"))
 	}
 
 	// 3. Download the zip file to the temp directory
-	statusChan <- "Downloading GW2EICLI.zip..."
-	zipPath := filepath.Join(tempDir, "GW2EICLI.zip")
+	sendStatus(statusChan, i18n.T("eicli.downloading"))
+	if err := os.MkdirAll(processor.FightLogTemp, 0755); err != nil {
+		return installError(fmt.Errorf("error creating %s: %w", processor.FightLogTemp, err))
+	}
+	zipPath := filepath.Join(processor.FightLogTemp, "GW2EICLI.zip")
 	if err := downloadFile(zipPath, downloadURL); err != nil {
-		statusChan <- fmt.Sprintf("Error downloading zip: %v", err)
-		return
+		return installError(fmt.Errorf("error downloading zip: %w", err))
 	}
 	defer os.Remove(zipPath) // Clean up the zip file afterwards
 
 	// 4. Unzip the archive to the target directory
-	statusChan <- "Extracting CLI..."
+	sendStatus(statusChan, i18n.T("eicli.extracting"))
 	if err := unzip(zipPath, cliDir); err != nil {
-		statusChan <- fmt.Sprintf("Error extracting zip: %v", err)
-		return
+		return installError(fmt.Errorf("error extracting zip: %w", err))
 	}
 
-	statusChan <- "Elite Insights CLI installed successfully."
+	sendStatus(statusChan, i18n.T("eicli.installed"))
+	return nil
+}
+
+// sendStatus logs a progress message and forwards it to the TUI.
+func sendStatus(statusChan chan<- string, msg string) {
+	logger.Info("%s", msg)
+	statusChan <- msg
+}
+
+// installError logs a failed install and classifies it: the CLI stays missing until
+// the app is started again.
+func installError(err error) error {
+	err = apperr.New(apperr.CLIMissing, fmt.Errorf("could not install Elite Insights CLI: %w", err))
+	logger.Error("%v", err)
+	return err
 }
 
 func downloadFile(filepath string, url string) error {
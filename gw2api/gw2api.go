@@ -0,0 +1,234 @@
+// Package gw2api queries the official Guild Wars 2 API for WvW matchup context.
+package gw2api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw2-cmd-watch/processor"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	baseURL = "https://api.guildwars2.com"
+	// schemaVersion makes /v2/account include the WvW team of the account
+	schemaVersion = "2024-07-20T01:00:00Z"
+)
+
+var client = &http.Client{Timeout: 15 * time.Second}
+
+// get decodes the JSON response of an API path into v.
+func get(path string, query url.Values, v any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("v", schemaVersion)
+	resp, err := client.Get(baseURL + path + "?" + query.Encode())
+	if err != nil {
+		return fmt.Errorf("GW2 API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("GW2 API %s: %s %s", path, resp.Status, apiErr.Text)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GW2 API %s: %w", path, err)
+	}
+	return nil
+}
+
+// HomeWorld returns the configured world or WvW team. With an API key it is looked up
+// from the account, which follows team changes between relinks.
+func HomeWorld(worldID int, apiKey string) (int, error) {
+	if apiKey == "" {
+		if worldID == 0 {
+			return 0, errors.New("set gw2_world_id or gw2_api_key for matchup info")
+		}
+		return worldID, nil
+	}
+	var account struct {
+		World int `json:"world"`
+		WvW   struct {
+			TeamID int `json:"team_id"`
+		} `json:"wvw"`
+	}
+	if err := get("/v2/account", url.Values{"access_token": {apiKey}}, &account); err != nil {
+		return 0, err
+	}
+	if account.WvW.TeamID != 0 {
+		return account.WvW.TeamID, nil
+	}
+	return account.World, nil
+}
+
+// match is the part of /v2/wvw/matches we use.
+type match struct {
+	ID         string           `json:"id"`
+	Worlds     map[string]int   `json:"worlds"`
+	AllWorlds  map[string][]int `json:"all_worlds"`
+	Skirmishes []struct {
+		ID int `json:"id"`
+	} `json:"skirmishes"`
+	Maps []matchMap `json:"maps"`
+}
+
+func fetchMatch(worldID int) (match, error) {
+	var m match
+	err := get("/v2/wvw/matches", url.Values{"world": {strconv.Itoa(worldID)}}, &m)
+	return m, err
+}
+
+// worldNames looks up names for world and team IDs. IDs without a name are missing
+// from the result.
+func worldNames(ids []int) map[string]string {
+	names := make(map[string]string)
+	if len(ids) == 0 {
+		return names
+	}
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = strconv.Itoa(id)
+	}
+	var worlds []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := get("/v2/worlds", url.Values{"ids": {strings.Join(strIDs, ",")}}, &worlds); err != nil {
+		return names
+	}
+	for _, w := range worlds {
+		names[strconv.Itoa(w.ID)] = w.Name
+	}
+	return names
+}
+
+// FetchMatchup returns the current matchup of a world or WvW team.
+func FetchMatchup(worldID int) (processor.Matchup, error) {
+	m, err := fetchMatch(worldID)
+	if err != nil {
+		return processor.Matchup{}, err
+	}
+	var ids []int
+	for _, worlds := range m.AllWorlds {
+		ids = append(ids, worlds...)
+	}
+	for _, id := range m.Worlds {
+		ids = append(ids, id)
+	}
+	names := worldNames(ids)
+	name := func(id int) string {
+		if n, ok := names[strconv.Itoa(id)]; ok {
+			return n
+		}
+		return fmt.Sprintf("Team %d", id)
+	}
+
+	mu := processor.Matchup{MatchID: m.ID, Skirmish: len(m.Skirmishes), Teams: make(map[string]string)}
+	for color, main := range m.Worlds {
+		team := []string{name(main)}
+		for _, id := range m.AllWorlds[color] {
+			if id != main {
+				team = append(team, name(id))
+			}
+		}
+		mu.Teams[color] = strings.Join(team, " + ")
+		for _, id := range append(m.AllWorlds[color], main) {
+			if id == worldID {
+				mu.Color = color
+			}
+		}
+	}
+	return mu, nil
+}
+
+// ObjectiveState is the current owner of one WvW objective.
+type ObjectiveState struct {
+	ID          string
+	Name        string
+	Type        string // Camp, Tower, Keep, Castle, ...
+	Map         string // EBG, RBL, BBL or GBL
+	Owner       string // Red, Green, Blue or Neutral
+	LastFlipped time.Time
+}
+
+type matchMap struct {
+	Type       string `json:"type"`
+	Objectives []struct {
+		ID          string `json:"id"`
+		Type        string `json:"type"`
+		Owner       string `json:"owner"`
+		LastFlipped string `json:"last_flipped"`
+	} `json:"objectives"`
+}
+
+// objectiveNames caches /v2/wvw/objectives, which only changes with map updates.
+var objectiveNames struct {
+	sync.Mutex
+	byID map[string]string
+}
+
+func objectiveName(id string) string {
+	objectiveNames.Lock()
+	defer objectiveNames.Unlock()
+	if objectiveNames.byID == nil {
+		var all []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := get("/v2/wvw/objectives", url.Values{"ids": {"all"}}, &all); err != nil {
+			return id // Try again next time
+		}
+		objectiveNames.byID = make(map[string]string, len(all))
+		for _, o := range all {
+			objectiveNames.byID[o.ID] = o.Name
+		}
+	}
+	if name := objectiveNames.byID[id]; name != "" {
+		return name
+	}
+	return id
+}
+
+// mapCodes turns API map types into the short names used for fight locations.
+var mapCodes = map[string]string{
+	"Center":    "EBG",
+	"RedHome":   "RBL",
+	"BlueHome":  "BBL",
+	"GreenHome": "GBL",
+}
+
+// Objectives returns the owner of every objective in a world's current match.
+func Objectives(worldID int) ([]ObjectiveState, error) {
+	m, err := fetchMatch(worldID)
+	if err != nil {
+		return nil, err
+	}
+	var out []ObjectiveState
+	for _, mm := range m.Maps {
+		code, ok := mapCodes[mm.Type]
+		if !ok {
+			continue
+		}
+		for _, o := range mm.Objectives {
+			flipped, _ := time.Parse(time.RFC3339, o.LastFlipped)
+			out = append(out, ObjectiveState{
+				ID:          o.ID,
+				Name:        objectiveName(o.ID),
+				Type:        o.Type,
+				Map:         code,
+				Owner:       o.Owner,
+				LastFlipped: flipped,
+			})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+// Package i18n looks up the TUI's user-facing text in a message catalog. English is
+// built in; translations are JSON files with the same keys, so the community can add
+// languages without a new build.
+package i18n
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LangDir holds translation files named after their language, e.g. lang/de.json.
+const LangDir = "lang"
+
+//go:embed en.json
+var baseJSON []byte
+
+var (
+	mu     sync.RWMutex
+	base   map[string]string
+	active map[string]string // Selected translation, nil for English
+)
+
+func init() {
+	if err := json.Unmarshal(baseJSON, &base); err != nil {
+		panic(fmt.Sprintf("i18n: invalid built-in catalog: %v", err))
+	}
+}
+
+// Load selects the translation for lang. A language code such as "de" reads
+// lang/de.json and a path ending in .json reads that file; "" and "en" use English.
+// Keys missing from the file keep their English text.
+func Load(lang string) error {
+	lang = strings.TrimSpace(lang)
+	if lang == "" || strings.EqualFold(lang, "en") {
+		mu.Lock()
+		active = nil
+		mu.Unlock()
+		return nil
+	}
+	path := lang
+	if !strings.HasSuffix(strings.ToLower(lang), ".json") {
+		path = filepath.Join(LangDir, lang+".json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read translation: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Notepad adds a BOM
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return fmt.Errorf("failed to parse translation %s: %w", path, err)
+	}
+	mu.Lock()
+	active = catalog
+	mu.Unlock()
+	return nil
+}
+
+// T returns the text for key in the selected language, formatted with args like
+// fmt.Sprintf. Unknown keys are returned as is so a typo shows up on screen.
+func T(key string, args ...any) string {
+	mu.RLock()
+	text, ok := active[key]
+	mu.RUnlock()
+	if !ok || text == "" {
+		if text, ok = base[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Missing lists the keys the selected translation does not cover yet.
+func Missing() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if active == nil {
+		return nil
+	}
+	var keys []string
+	for k := range base {
+		if active[k] == "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Template returns the English catalog, the starting point for a new translation.
+func Template() []byte {
+	return baseJSON
+}
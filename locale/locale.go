@@ -0,0 +1,151 @@
+// Package locale renders times and numbers in the time zone and style the user
+// configured, instead of whatever the machine or EI happened to use.
+package locale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Windows installs often lack a zoneinfo database
+)
+
+// Default layouts, in Go's reference time notation.
+const (
+	DefaultTimeFormat = "15:04:05"
+	DefaultDateFormat = "2006-01-02"
+)
+
+// groupSeparators maps a language or language-region tag to its thousands separator.
+var groupSeparators = map[string]string{
+	"en": ",", "ja": ",", "ko": ",", "zh": ",",
+	"de": ".", "nl": ".", "it": ".", "es": ".", "pt": ".", "da": ".", "tr": ".", "id": ".",
+	"fr": " ", "pl": " ", "cs": " ", "sv": " ", "fi": " ", "nb": " ", "no": " ", "ru": " ", "uk": " ",
+	"de-ch": "'", "it-ch": "'", "fr-ch": " ",
+}
+
+// abbreviateFrom is the smallest number Number abbreviates. Smaller ones fit the
+// columns as they are.
+const abbreviateFrom = 10000
+
+// abbreviations are the suffixes of abbreviated numbers, smallest first.
+var abbreviations = []struct {
+	size   float64
+	suffix string
+}{{1e3, "k"}, {1e6, "m"}, {1e9, "b"}}
+
+// Format renders times and numbers.
+type Format struct {
+	Zone       *time.Location
+	TimeLayout string
+	DateLayout string
+	Abbreviate bool   // Number shortens large numbers, e.g. 1.2m and 450k
+	group      string // Thousands separator
+	decimal    string // Decimal separator, for abbreviated numbers
+}
+
+// Default is local machine time with 24h clock and comma grouping.
+func Default() Format {
+	return Format{Zone: time.Local, TimeLayout: DefaultTimeFormat, DateLayout: DefaultDateFormat, group: ",", decimal: "."}
+}
+
+// New builds a Format from config values. Empty values keep the defaults. zone is an
+// IANA name such as "Europe/Berlin", "UTC" or "Local"; numbers is a language tag such
+// as "en", "de" or "de-CH".
+func New(zone, timeLayout, dateLayout, numbers string) (Format, error) {
+	f := Default()
+	if zone != "" {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return f, fmt.Errorf("unknown time zone %q", zone)
+		}
+		f.Zone = loc
+	}
+	if timeLayout != "" {
+		f.TimeLayout = timeLayout
+	}
+	if dateLayout != "" {
+		f.DateLayout = dateLayout
+	}
+	if numbers != "" {
+		tag := strings.ToLower(strings.ReplaceAll(numbers, "_", "-"))
+		sep, ok := groupSeparators[tag]
+		if !ok {
+			sep, ok = groupSeparators[strings.SplitN(tag, "-", 2)[0]]
+		}
+		if !ok {
+			return f, fmt.Errorf("unknown number locale %q", numbers)
+		}
+		f.group = sep
+		// Languages grouping with dots or spaces write decimals with a comma
+		if sep == "." || sep == " " {
+			f.decimal = ","
+		}
+	}
+	return f, nil
+}
+
+// In converts t to the configured zone.
+func (f Format) In(t time.Time) time.Time {
+	return t.In(f.Zone)
+}
+
+// Time renders the clock time of t.
+func (f Format) Time(t time.Time) string {
+	return f.In(t).Format(f.TimeLayout)
+}
+
+// Date renders the date of t.
+func (f Format) Date(t time.Time) string {
+	return f.In(t).Format(f.DateLayout)
+}
+
+// DateTime renders the date and clock time of t.
+func (f Format) DateTime(t time.Time) string {
+	return f.Date(t) + " " + f.Time(t)
+}
+
+// Number adds thousands separators to an integer, or abbreviates it from 10,000 up
+// when Abbreviate is set.
+func (f Format) Number(n int) string {
+	if f.Abbreviate && (n >= abbreviateFrom || n <= -abbreviateFrom) {
+		return f.abbreviated(n)
+	}
+	in := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, in = "-", in[1:]
+	}
+	var sb strings.Builder
+	sb.WriteString(sign)
+	for i, c := range in {
+		if i > 0 && (len(in)-i)%3 == 0 {
+			sb.WriteString(f.group)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// abbreviated writes n in the smallest unit that keeps it under 1000, with a decimal
+// below 100, e.g. 12.3k, 450k and 1.2m.
+func (f Format) abbreviated(n int) string {
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	unit := abbreviations[0]
+	for _, next := range abbreviations[1:] {
+		if math.Round(float64(n)/unit.size) < 1000 {
+			break
+		}
+		unit = next
+	}
+	v := float64(n) / unit.size
+	text := strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+	if math.Round(v*10)/10 < 100 {
+		text = strings.TrimSuffix(strconv.FormatFloat(v, 'f', 1, 64), ".0")
+	}
+	return sign + strings.Replace(text, ".", f.decimal, 1) + unit.suffix
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log line. Lines below the configured level are dropped.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	defaultMaxSize    = 5 * 1024 * 1024 // Rotate after 5 MB
+	defaultMaxBackups = 3               // Keep debug.log.1 .. debug.log.3
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// ParseLevel converts a level name ("debug", "info", "warn", "error") into a Level.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+var (
+	mu    sync.Mutex
+	out   io.Writer = io.Discard // Nothing is written until Init is called
+	level           = LevelInfo
+)
+
+// Init opens the log file at path with size-based rotation and routes all package
+// and standard library log output to it. Nothing is ever written to stdout, which
+// would corrupt the TUI.
+func Init(path string, lvl Level) (io.Closer, error) {
+	rf, err := newRotatingFile(path, defaultMaxSize, defaultMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	out = rf
+	level = lvl
+	mu.Unlock()
+
+	// Catch stray log.Printf calls from dependencies
+	log.SetOutput(rf)
+	log.SetFlags(log.LstdFlags)
+	return rf, nil
+}
+
+// SetLevel changes the minimum level that is written.
+func SetLevel(lvl Level) {
+	mu.Lock()
+	level = lvl
+	mu.Unlock()
+}
+
+// Debug logs verbose diagnostics, only written with --verbose.
+func Debug(format string, args ...any) { write(LevelDebug, format, args...) }
+
+// Info logs normal operational events.
+func Info(format string, args ...any) { write(LevelInfo, format, args...) }
+
+// Warn logs recoverable problems.
+func Warn(format string, args ...any) { write(LevelWarn, format, args...) }
+
+// Error logs failures.
+func Error(format string, args ...any) { write(LevelError, format, args...) }
+
+func write(lvl Level, format string, args ...any) {
+	mu.Lock()
+	defer mu.Unlock()
+	if lvl < level {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	line := fmt.Sprintf("%s %-5s %s\n", time.Now().Format("2006-01-02 15:04:05.000"), lvl, strings.TrimRight(msg, "\n"))
+	_, _ = io.WriteString(out, line)
+}
+
+// rotatingFile is an io.WriteCloser that rolls the file over once it exceeds maxSize.
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	return nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.size+int64(len(p)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	// Shift debug.log.2 -> debug.log.3 etc, dropping the oldest
+	for i := rf.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", rf.path, i), fmt.Sprintf("%s.%d", rf.path, i+1))
+	}
+	if rf.maxBackups > 0 {
+		_ = os.Rename(rf.path, rf.path+".1")
+	} else {
+		_ = os.Remove(rf.path)
+	}
+	return rf.open()
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.file.Close()
+}
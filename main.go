@@ -2,10 +2,29 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/backend"
 	"gw2-cmd-watch/config"
+	"gw2-cmd-watch/discord"
 	"gw2-cmd-watch/eicli"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/locale"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/metrics"
+	"gw2-cmd-watch/mumble"
+	"gw2-cmd-watch/overlay"
+	"gw2-cmd-watch/parser"
 	"gw2-cmd-watch/processor"
+	"gw2-cmd-watch/report"
+	"gw2-cmd-watch/server"
+	"gw2-cmd-watch/sheets"
+	"gw2-cmd-watch/state"
+	"gw2-cmd-watch/summary"
+	"gw2-cmd-watch/templates"
+	"gw2-cmd-watch/tray"
 	"gw2-cmd-watch/tui"
 	"gw2-cmd-watch/updater"
 	"gw2-cmd-watch/watcher"
@@ -15,20 +34,75 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/skratchdot/open-golang/open"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		if err := runReport(os.Args[2:]); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		if err := runVerify(); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	verbose := flag.Bool("verbose", false, "write debug-level messages to debug.log")
+	logLevel := flag.String("log-level", "info", "minimum log level: debug, info, warn or error")
+	langTemplate := flag.String("lang-template", "", "write the English message catalog to this file to start a translation")
+	writeTemplates := flag.String("write-templates", "", "write the built-in report and message templates to this folder to customize them")
+	attach := flag.String("attach", "", "show the TUI of an instance already running with http_enabled at this address, e.g. localhost:8080")
+	flag.Parse()
+
+	if *langTemplate != "" {
+		err := os.MkdirAll(filepath.Dir(*langTemplate), 0755)
+		if err == nil {
+			err = os.WriteFile(*langTemplate, i18n.Template(), 0644)
+		}
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *writeTemplates != "" {
+		written, err := templates.WriteDefaults(*writeTemplates)
+		if err != nil {
+			fmt.Println("fatal:", err)
+			os.Exit(1)
+		}
+		for _, path := range written {
+			fmt.Println("wrote", path)
+		}
+		return
+	}
 
-	logFile, err := tea.LogToFile("debug.log", "debug")
+	level, err := logger.ParseLevel(*logLevel)
+	if err != nil {
+		fmt.Println("fatal:", err)
+		os.Exit(1)
+	}
+	if *verbose {
+		level = logger.LevelDebug
+	}
+	logFile, err := logger.Init("debug.log", level)
 	if err != nil {
 		fmt.Println("fatal:", err)
 		os.Exit(1)
 	}
 	defer logFile.Close()
+	logger.Info("starting GW2 Commanders Watch")
 	if runtime.GOOS == "windows" {
 		// For cmd.exe and PowerShell, you can use the 'title' command.
 		// Note: This launches a new process, so error handling is important.
@@ -36,57 +110,78 @@ func main() {
 		cmd := exec.Command("cmd", "/C", "title", "GW2_Commanders_Watch")
 		err := cmd.Run()
 		if err != nil {
-			fmt.Println("Error setting console title:", err)
+			logger.Warn("error setting console title: %v", err)
 		}
 	}
-	const configPath = "config.json"
-	cfg, err := loadOrInitConfig(configPath)
+	cfg, err := loadOrInitConfig(config.DefaultPath)
 	if err != nil {
 		fmt.Printf("Error with configuration: %v\n", err)
 		os.Exit(1)
 	}
+	ensureAnonymizeSeed(&cfg)
 	fmt.Printf("Using WatchFolder: %s\n", cfg.WatchFolder)
 
-	// Ensure the Elite Insights config file exists
-	ensureEICLIConfig()
+	if err := i18n.Load(cfg.Language); err != nil {
+		logger.Warn("could not load language %q, using English: %v", cfg.Language, err)
+	} else if missing := i18n.Missing(); len(missing) > 0 {
+		logger.Info("%d messages are not translated to %s yet and are shown in English", len(missing), cfg.Language)
+	}
 
-	// Clean up the temp folder from any previous runs
-	if err := os.RemoveAll(processor.FightLogTemp); err != nil {
-		fmt.Printf("Warning: could not clear temp folder: %v\n", err)
+	processor.SetDirs(cfg.TempDir, cfg.ArchiveDir)
+	parser.SetAliases(cfg.PlayerAliases, cfg.AltAccounts)
+	parser.SetNameDisplay(cfg.NameDisplay)
+	parser.SetAnonymize(cfg.Anonymize, cfg.AnonymizeSeed)
+	templates.SetDir(cfg.TemplatesDir)
+	if *attach != "" {
+		if err := runAttached(cfg, *attach); err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+		return
 	}
-	if err := os.MkdirAll(processor.FightLogTemp, 0755); err != nil {
-		fmt.Printf("Warning: could not recreate temp folder: %v\n", err)
+	processor.SetMoveRetry(cfg.ArchiveRetries, time.Duration(cfg.ArchiveRetryBackoffMs)*time.Millisecond)
+
+	// Ensure the Elite Insights config file exists and matches our settings
+	ensureEICLIConfig()
+	if err := processor.ApplyEISettings(cfg.EIMemoryLimitMB, cfg.EISingleThreaded); err != nil {
+		logger.Warn("could not apply EI settings: %v", err)
+	}
+	if err := processor.SetEIAnonymous(cfg.EIAnonymous); err != nil {
+		logger.Warn("could not apply EI anonymous setting: %v", err)
 	}
 
-	// Get initial list of runs
-	initialRuns, err := getInitialRuns()
-	if err != nil {
-		fmt.Printf("Could not load initial runs: %v\n", err)
-		// Don't exit, just start with an empty list
+	// Clean up the temp folder from any previous runs
+	if err := processor.ClearTemp(); err != nil {
+		logger.Warn("could not clear temp folder: %v", err)
 	}
 
-	// Initialize the TUI program
-	initialModel := tui.NewModel(cfg, initialRuns)
-	p := tea.NewProgram(initialModel, tea.WithAltScreen())
+	// Initialize the TUI program. The pipeline below reports to it through events.
+	p, session := newProgram(cfg)
+	events := backend.NewLocal()
+	go tui.Attach(p, events)
 
 	// Goroutine for App Updater
 	go func() {
 		updateInfo, err := updater.CheckForUpdates()
 		if err != nil {
 			// Don't bother the user, just log it
-			fmt.Fprintf(logFile, "error checking for app update: %v\n", err)
+			logger.Warn("error checking for app update: %v", err)
 		}
 		if updateInfo != nil {
-			p.Send(tui.UpdateAvailableMsg{URL: updateInfo.URL})
+			events.Publish(backend.Event{Kind: backend.KindUpdate, Text: updateInfo.URL})
 		}
 	}()
 
 	// Goroutine for CLI Auto-Updater
 	cliUpdateChan := make(chan string)
-	go eicli.InstallCLI(cliUpdateChan)
+	go func() {
+		if err := eicli.InstallCLI(cliUpdateChan); err != nil {
+			events.Error(err)
+		}
+	}()
 	go func() {
 		for status := range cliUpdateChan {
-			p.Send(tui.StatusMsg(status))
+			events.Toast(status)
 		}
 	}()
 
@@ -102,29 +197,491 @@ func main() {
 			// A more robust solution would use a dedicated channel, but this is sufficient.
 			<-time.After(1 * time.Second)
 		}
-		if err := watcher.Start(cfg.WatchFolder, fileEventChan); err != nil {
-			p.Send(tui.ErrMsg{Err: fmt.Errorf("watcher error: %w", err)})
+		var exclude []string
+		if cfg.ExcludeArchiveFromWatch {
+			exclude = []string{processor.LogArchive, processor.FightLogTemp}
+		}
+		// Queue logs recorded while the app was closed or had crashed. Without a
+		// previous log there is nothing to catch up from.
+		if !session.LastProcessed.IsZero() {
+			found, err := watcher.Scan(cfg.WatchFolder, exclude, session.LastProcessed)
+			var missed []string
+			for _, path := range found {
+				if _, done := session.Processed[path]; !done {
+					// Noted now, before newer logs can move the catch-up point past it
+					state.Queued(path)
+					missed = append(missed, path)
+				}
+			}
+			if err != nil {
+				logger.Warn("catch-up scan failed: %v", err)
+			} else if len(missed) > 0 {
+				logger.Info("catching up on %d logs newer than %s", len(missed), session.LastProcessed.Format(time.RFC3339))
+				events.Status(i18n.T("status.catching_up", len(missed)))
+				// Fed alongside the watcher, so new logs aren't held up behind them
+				go func() {
+					for _, path := range missed {
+						fileEventChan <- path
+					}
+				}()
+			}
+		}
+		if err := watcher.Start(cfg.WatchFolder, exclude, fileEventChan); err != nil {
+			events.Error(fmt.Errorf("watcher error: %w", err))
 		}
 	}()
 
-	// Goroutine for Log Processor
+	// Queue new logs so a backlog can be parsed in batches. Pushing never blocks, so
+	// the watcher keeps up however far behind EI is.
+	queue := processor.NewQueue(processor.DefaultQueueCapacity)
+	metrics.RegisterGauge("gw2cw_queue_depth", "Logs waiting for Elite Insights.", func() float64 {
+		return float64(queue.Len())
+	})
 	go func() {
 		for filePath := range fileEventChan {
-			p.Send(tui.StatusMsg(fmt.Sprintf("Processing: %s", filepath.Base(filePath))))
-			tempJSONPath, err := processor.ProcessLog(filePath)
-			if err != nil {
-				p.Send(tui.ErrMsg{Err: err})
-			} else {
-				p.Send(tui.TempLogProcessedMsg{TempPath: tempJSONPath})
+			if inFolder(cfg.WatchFolder, filePath) {
+				state.Queued(filePath)
 			}
+			queue.Push(filePath)
 		}
 	}()
 
+	// Log processor goroutines, one per allowed EI process
+	workers := cfg.MaxEIProcesses
+	if workers < 1 {
+		workers = 1
+	}
+	processor.SetMaxConcurrent(workers)
+	processor.SetEITimeout(time.Duration(cfg.EITimeoutSeconds) * time.Second)
+	tui.OnCancelParse(processor.Cancel)
+	processor.OnProgress(func(pr processor.Progress) {
+		kind := backend.KindParsing
+		if pr.Done {
+			kind = backend.KindParsed
+		}
+		events.Publish(backend.Event{Kind: kind, Text: pr.Label, Line: pr.Line, ParseID: pr.ID})
+	})
+	for i := 0; i < workers; i++ {
+		go processQueue(events, queue, cfg.WatchFolder)
+	}
+	// A log is done once its fight is archived, so a crash before that retries it
+	tui.OnLogArchived(func(logPath string) {
+		recordProcessed(cfg.WatchFolder, logPath)
+	})
+
+	// Optional stream overlay file, refreshed after every fight
+	if cfg.OverlayPath != "" {
+		ow, err := overlay.New(cfg.OverlayPath, cfg.OverlayTemplate)
+		if err != nil {
+			logger.Error("overlay disabled: %v", err)
+			events.Error(fmt.Errorf("overlay disabled: %w", err))
+		} else {
+			tui.OnFightArchived(func(_ string, log *parser.ParsedLog) {
+				if err := ow.Write(log); err != nil {
+					logger.Warn("%v", err)
+					events.Error(err)
+				}
+			})
+		}
+	}
+
+	// Optional summary file next to every fight, for external tools
+	if cfg.FightSummaries {
+		tui.OnFightArchived(func(jsonPath string, log *parser.ParsedLog) {
+			if err := summary.Write(jsonPath, log); err != nil {
+				logger.Warn("%v", err)
+				events.Error(err)
+			}
+		})
+	}
+
+	// Optional Discord bot answering stat commands
+	if cfg.DiscordBotToken != "" {
+		bot := discord.New(cfg.DiscordBotToken, cfg.DiscordReportChannel, func(err error) {
+			logger.Error("%v", err)
+			events.Error(err)
+		})
+		bot.Rallybot = cfg.RallybotReport
+		tui.OnRunClosed(bot.PostRunReport)
+		go bot.Run()
+	}
+
+	// Optional end-of-run report to a Discord webhook
+	if cfg.RunReportWebhook {
+		hookURL := cfg.WebhookURL
+		if hookURL == "" {
+			var err error
+			if hookURL, err = processor.EIWebhookURL(); err != nil {
+				logger.Warn("%v", err)
+			}
+		}
+		if hookURL == "" {
+			err := errors.New("run report webhook disabled: set webhook_url in config.json or WebhookURL in ELI3.conf")
+			logger.Error("%v", err)
+			events.Error(err)
+		} else {
+			hook := discord.NewWebhook(hookURL, func(err error) {
+				logger.Error("%v", err)
+				events.Error(err)
+			})
+			hook.Rallybot = cfg.RallybotReport
+			tui.OnRunClosed(hook.PostRunReport)
+		}
+	}
+
+	// Optional Google Sheets export of finished runs
+	if cfg.SheetsCredentials != "" && cfg.SheetsSpreadsheetID != "" {
+		exporter, err := sheets.New(cfg.SheetsCredentials, cfg.SheetsSpreadsheetID)
+		if err != nil {
+			logger.Error("sheets export disabled: %v", err)
+			events.Error(fmt.Errorf("sheets export disabled: %w", err))
+		} else {
+			tui.OnRunClosed(func(runPath string) {
+				if err := exporter.ExportRun(runPath); err != nil {
+					logger.Error("sheets export of %s: %v", filepath.Base(runPath), err)
+					events.Error(err)
+					return
+				}
+				events.Status(i18n.T("status.sheet_exported", filepath.Base(runPath)))
+			})
+		}
+	}
+
+	// Optional tray icon with quick status
+	if cfg.Tray {
+		err := tray.Run(tray.Menu{
+			Paused:    watcher.Paused,
+			SetPaused: watcher.SetPaused,
+			OpenArchive: func() {
+				if err := open.Run(processor.LogArchive); err != nil {
+					logger.Warn("could not open archive: %v", err)
+				}
+			},
+		})
+		if err != nil {
+			logger.Error("tray icon disabled: %v", err)
+		} else {
+			defer tray.Close()
+			var lastFight atomic.Value
+			tui.OnFightArchived(func(_ string, log *parser.ParsedLog) {
+				s := analysis.Summarize(log)
+				lastFight.Store(fmt.Sprintf("Last fight: %s, %d vs %d, %d kills / %d deaths",
+					s.Outcome(), s.ZergCount(), s.EnemyCount, s.EnemyDeaths, s.SquadDeaths))
+			})
+			go updateTray(queue, &lastFight)
+		}
+	}
+
+	// Optional MumbleLink tracking of the player's map
+	if cfg.MumbleLink {
+		go func() {
+			err := mumble.Watch(2*time.Second, func(s mumble.State) {
+				logger.Debug("MumbleLink: online=%v map=%d (%s)", s.Online, s.MapID, s.Map)
+				events.Publish(backend.Event{Kind: backend.KindMap, Online: s.Online, Map: s.Map, InWvW: s.InWvW})
+			})
+			if err != nil {
+				logger.Error("%v", err)
+				events.Error(err)
+			}
+		}()
+	}
+
+	// Optional web server for browsing results from other devices on the LAN
+	if cfg.HTTPEnabled {
+		srv := server.New()
+		tui.OnFightArchived(srv.PublishFight)
+		// Share status, parse progress and new fights with instances started with --attach
+		events.Listen(func(e backend.Event) {
+			switch e.Kind {
+			case backend.KindStatus, backend.KindToast, backend.KindError, backend.KindParsing, backend.KindParsed:
+				srv.PublishPipeline(e)
+			}
+		})
+		tui.OnFightArchived(func(path string, _ *parser.ParsedLog) {
+			if rel, err := filepath.Rel(processor.LogArchive, path); err == nil {
+				srv.PublishPipeline(backend.Event{Kind: backend.KindArchived, Path: rel})
+			}
+		})
+		if len(cfg.UploadTokens) > 0 {
+			srv.EnableUploads(cfg.UploadTokens, queue, func(uploader, name string) {
+				events.Status(i18n.T("status.upload_received", name, uploader))
+			})
+		}
+		go func() {
+			port := cfg.HTTPPort
+			if port <= 0 {
+				port = server.DefaultPort
+			}
+			events.Status(i18n.T("status.web_server", port))
+			if err := srv.ListenAndServe(port); err != nil {
+				events.Error(fmt.Errorf("web server error: %w", err))
+			}
+		}()
+	}
+
 	// Run the TUI
-	if _, err := p.Run(); err != nil {
+	finalModel, err := p.Run()
+	events.Close() // Nothing reads events after the TUI exits
+	if err != nil {
 		fmt.Printf("Alas, there's been an error: %v\n", err)
 		os.Exit(1)
 	}
+	tui.CloseLiveRun(finalModel)
+	saveSession(finalModel)
+}
+
+// newProgram creates the TUI with the archived runs and the session restored from the
+// last launch.
+func newProgram(cfg config.Config) (*tea.Program, state.State) {
+	initialRuns, err := getInitialRuns()
+	if err != nil {
+		logger.Error("could not load initial runs: %v", err)
+		// Don't exit, just start with an empty list
+	}
+	session, err := state.Load(state.DefaultPath)
+	if err != nil {
+		logger.Warn("could not read session state: %v", err)
+	}
+	p := tea.NewProgram(tui.NewModel(cfg, initialRuns, session), tea.WithAltScreen())
+	go tui.WatchArchive(p)
+	return p, session
+}
+
+// saveSession stores where the user left off for the next launch.
+func saveSession(final tea.Model) {
+	s, ok := tui.SessionState(final)
+	if !ok {
+		return
+	}
+	// Keep what the workers recorded while the app ran
+	if prev, err := state.Load(state.DefaultPath); err == nil {
+		s.LastProcessed, s.Processed = prev.LastProcessed, prev.Processed
+	}
+	if err := state.Save(state.DefaultPath, &s); err != nil {
+		logger.Warn("could not save session state: %v", err)
+	}
+}
+
+// runAttached shows the TUI of an instance that is already running, following its
+// pipeline through the web server instead of watching and parsing logs itself.
+func runAttached(cfg config.Config, addr string) error {
+	remote, err := backend.Dial(addr)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+	logger.Info("attached to %s", addr)
+
+	p, _ := newProgram(cfg)
+	go tui.Attach(p, remote)
+	finalModel, err := p.Run()
+	if err != nil {
+		return err
+	}
+	saveSession(finalModel)
+	return nil
+}
+
+// runReport prints the aggregate of all runs in a time window, e.g. "report --since 7d".
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	since := fs.String("since", "7d", "time window to report on, e.g. 7d, 4w or 12h")
+	fs.Parse(args)
+	window, err := report.ParseWindow(*since)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(config.DefaultPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", config.DefaultPath, err)
+	}
+	processor.SetDirs(cfg.TempDir, cfg.ArchiveDir)
+	parser.SetAliases(cfg.PlayerAliases, cfg.AltAccounts)
+	parser.SetNameDisplay(cfg.NameDisplay)
+	parser.SetAnonymize(cfg.Anonymize, cfg.AnonymizeSeed)
+	templates.SetDir(cfg.TemplatesDir)
+	loc, err := locale.New(cfg.TimeZone, cfg.TimeFormat, cfg.DateFormat, cfg.NumberLocale)
+	if err != nil {
+		return err
+	}
+
+	r, err := report.Build(time.Now().Add(-window), cfg.OutnumberedRatio, cfg.ExcludeOutnumberedLosses)
+	if err != nil {
+		return err
+	}
+	out, err := report.Format(r, loc)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// runVerify checks the archive for fights that cannot be read or lack half of their
+// JSON/HTML pair, and offers to re-generate missing reports and quarantine the rest.
+func runVerify() error {
+	cfg, err := config.LoadConfig(config.DefaultPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", config.DefaultPath, err)
+	}
+	processor.SetDirs(cfg.TempDir, cfg.ArchiveDir)
+
+	checked, problems, err := processor.VerifyArchive()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Checked %d fights in %s\n", checked, processor.LogArchive)
+	if len(problems) == 0 {
+		fmt.Println("No problems found.")
+		return nil
+	}
+	var missing, broken []processor.Problem
+	for _, p := range problems {
+		fmt.Println(p)
+		if p.Broken() {
+			broken = append(broken, p)
+		} else {
+			missing = append(missing, p)
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	confirm := func(question string) bool {
+		fmt.Printf("\n%s (y/N): ", question)
+		answer, _ := reader.ReadString('\n')
+		return strings.ToLower(strings.TrimSpace(answer)) == "y"
+	}
+	if len(missing) > 0 && cfg.WatchFolder == "" {
+		fmt.Println("\nSet the watch folder in config.json to re-generate missing HTML reports.")
+	} else if len(missing) > 0 && confirm(fmt.Sprintf("Re-generate %d missing HTML reports from the logs in %s?", len(missing), cfg.WatchFolder)) {
+		regenerateReports(cfg, missing)
+	}
+	if len(broken) > 0 && confirm(fmt.Sprintf("Move %d broken files to %s?", len(broken), processor.QuarantineDir())) {
+		moved := 0
+		for _, p := range broken {
+			if err := processor.Quarantine(p); err != nil {
+				fmt.Println("error:", err)
+				continue
+			}
+			moved++
+		}
+		fmt.Printf("Moved %d of %d.\n", moved, len(broken))
+	}
+	return nil
+}
+
+// regenerateReports parses the source logs of fights without an HTML report again.
+// Logs are found in the watch folder by the name EI gave the fight.
+func regenerateReports(cfg config.Config, missing []processor.Problem) {
+	if !eicli.CheckCLIExists() {
+		fmt.Println("error: Elite Insights is not installed yet; start the app once to install it")
+		return
+	}
+	ensureEICLIConfig()
+	if err := processor.ApplyEISettings(cfg.EIMemoryLimitMB, cfg.EISingleThreaded); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	processor.SetEITimeout(time.Duration(cfg.EITimeoutSeconds) * time.Second)
+	logs, err := watcher.Scan(cfg.WatchFolder, []string{processor.LogArchive, processor.FightLogTemp}, time.Time{})
+	if err != nil {
+		fmt.Println("error: could not list logs:", err)
+		return
+	}
+	byName := make(map[string]string, len(logs))
+	for _, path := range logs {
+		byName[strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))] = path
+	}
+	done := 0
+	for _, p := range missing {
+		logPath, ok := byName[processor.DisplayName(p.Path)]
+		if !ok {
+			fmt.Printf("%s: source log not found\n", p.Path)
+			continue
+		}
+		fmt.Printf("Parsing %s...\n", filepath.Base(logPath))
+		if err := processor.RegenerateHTML(p.Path, logPath); err != nil {
+			fmt.Printf("%s: %v\n", p.Path, err)
+			continue
+		}
+		done++
+	}
+	fmt.Printf("Re-generated %d of %d reports.\n", done, len(missing))
+}
+
+// updateTray keeps the tray tooltip current with the watcher, queue and last fight.
+func updateTray(queue *processor.Queue, lastFight *atomic.Value) {
+	var shown string
+	for range time.Tick(2 * time.Second) {
+		watching := "Watching"
+		if watcher.Paused() {
+			watching = "Paused"
+		}
+		status := fmt.Sprintf("GW2 Commanders Watch\n%s | Queue: %d", watching, queue.Len())
+		if last, ok := lastFight.Load().(string); ok {
+			status += "\n" + last
+		}
+		if status != shown {
+			tray.SetStatus(status)
+			shown = status
+		}
+	}
+}
+
+// processQueue parses queued logs until the program exits, batching when the queue is
+// deep. Logs from watchFolder that EI failed on or that were canceled are recorded as
+// done in the state file; parsed ones are recorded once archived.
+func processQueue(events *backend.Local, queue *processor.Queue, watchFolder string) {
+	report := func(res processor.Result) {
+		if errors.Is(res.Err, processor.ErrCanceled) {
+			// Dropped for good, so catch-up doesn't queue it again on the next launch
+			events.Status(i18n.T("status.parse_canceled", filepath.Base(res.LogPath)))
+			recordProcessed(watchFolder, res.LogPath)
+			return
+		}
+		if res.Err != nil {
+			metrics.ProcessingFailures.Inc()
+			logger.Error("processing %s: %v", res.LogPath, res.Err)
+			events.Error(res.Err)
+			// Kept in parse_failures.json rather than tried again on every launch
+			recordProcessed(watchFolder, res.LogPath)
+			return
+		}
+		metrics.LogsProcessed.Inc()
+		events.Publish(backend.Event{Kind: backend.KindProcessed, Path: res.JSONPath, Text: res.LogPath})
+	}
+	for {
+		batch := queue.PopBatch(processor.MaxBatchSize)
+		if len(batch) >= processor.BatchMinLogs {
+			events.Status(i18n.T("status.processing_batch", len(batch)))
+			for _, res := range processor.ProcessLogs(batch) {
+				report(res)
+			}
+			continue
+		}
+		for _, filePath := range batch {
+			events.Status(i18n.T("status.processing", filepath.Base(filePath)))
+			tempJSONPath, err := processor.ProcessLog(filePath)
+			report(processor.Result{LogPath: filePath, JSONPath: tempJSONPath, Err: err})
+		}
+	}
+}
+
+// inFolder reports whether path is inside folder.
+func inFolder(folder, path string) bool {
+	abs, _ := filepath.Abs(folder)
+	return strings.HasPrefix(path, abs+string(filepath.Separator))
+}
+
+// recordProcessed moves the catch-up point in the state file past a finished log from
+// watchFolder.
+func recordProcessed(watchFolder, logPath string) {
+	if !inFolder(watchFolder, logPath) {
+		return
+	}
+	if err := state.RecordProcessed(state.DefaultPath, logPath); err != nil {
+		logger.Warn("could not record processed log: %v", err)
+	}
 }
 
 func getInitialRuns() ([]string, error) {
@@ -157,6 +714,18 @@ func loadOrInitConfig(configPath string) (config.Config, error) {
 	return cfg, nil
 }
 
+// ensureAnonymizeSeed gives the config a pseudonym seed if it has none yet, so players
+// keep their pseudonyms across launches.
+func ensureAnonymizeSeed(cfg *config.Config) {
+	if cfg.AnonymizeSeed != "" {
+		return
+	}
+	cfg.AnonymizeSeed = parser.NewPseudonymSeed()
+	if err := config.SaveConfig(config.DefaultPath, cfg); err != nil {
+		logger.Warn("could not save the pseudonym seed: %v", err)
+	}
+}
+
 func promptForConfig(configPath string) (config.Config, error) {
 	var cfg config.Config
 	reader := bufio.NewReader(os.Stdin)
@@ -255,7 +824,7 @@ func promptForConfig(configPath string) (config.Config, error) {
 }
 
 func ensureEICLIConfig() {
-	const eiConfigPath = "ELI3.conf"
+	const eiConfigPath = processor.EIConfPath
 	const defaultConfig = `LightTheme=False
 HtmlExternalScripts=False
 SaveOutHTML=True
@@ -0,0 +1,145 @@
+// Package metrics keeps process-wide counters for long-running installs and renders
+// them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counter is a value that only goes up.
+type Counter struct {
+	name, help string
+	v          atomic.Int64
+}
+
+func (c *Counter) Inc()        { c.v.Add(1) }
+func (c *Counter) Add(n int64) { c.v.Add(n) }
+
+// Histogram counts observations into fixed buckets.
+type Histogram struct {
+	name, help string
+	buckets    []float64 // Upper bounds, ascending
+
+	mu     sync.Mutex
+	counts []uint64 // Per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := sort.SearchFloat64s(h.buckets, v)
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.sum += v
+	h.count++
+}
+
+// ObserveSince records the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+type gauge struct {
+	name, help string
+	fn         func() float64
+}
+
+var (
+	mu         sync.Mutex
+	counters   []*Counter
+	histograms []*Histogram
+	gauges     []gauge
+)
+
+// NewCounter registers a counter.
+func NewCounter(name, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	mu.Lock()
+	counters = append(counters, c)
+	mu.Unlock()
+	return c
+}
+
+// NewHistogram registers a histogram with the given bucket upper bounds.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{name: name, help: help, buckets: buckets, counts: make([]uint64, len(buckets))}
+	mu.Lock()
+	histograms = append(histograms, h)
+	mu.Unlock()
+	return h
+}
+
+// RegisterGauge adds a gauge whose value is read by fn at scrape time. Registering a
+// name again replaces the previous function.
+func RegisterGauge(name, help string, fn func() float64) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i := range gauges {
+		if gauges[i].name == name {
+			gauges[i].fn = fn
+			return
+		}
+	}
+	gauges = append(gauges, gauge{name: name, help: help, fn: fn})
+}
+
+// The application's metrics.
+var (
+	LogsProcessed      = NewCounter("gw2cw_logs_processed_total", "Logs parsed by Elite Insights.")
+	ProcessingFailures = NewCounter("gw2cw_processing_failures_total", "Logs that failed to parse.")
+	LogsArchived       = NewCounter("gw2cw_logs_archived_total", "Parsed fights moved into the archive.")
+	ArchiveFailures    = NewCounter("gw2cw_archive_failures_total", "Parsed fights that could not be archived.")
+	EIDuration         = NewHistogram("gw2cw_ei_duration_seconds", "Time spent in one Elite Insights run.",
+		[]float64{2, 5, 10, 20, 30, 60, 120, 300, 600})
+)
+
+// WriteText writes every metric in the Prometheus text format.
+func WriteText(w io.Writer) error {
+	mu.Lock()
+	cs := append([]*Counter(nil), counters...)
+	hs := append([]*Histogram(nil), histograms...)
+	gs := append([]gauge(nil), gauges...)
+	mu.Unlock()
+
+	var err error
+	printf := func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	for _, c := range cs {
+		printf("# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.v.Load())
+	}
+	for _, g := range gs {
+		printf("# HELP %s %s\n# TYPE %s gauge\n%s %s\n", g.name, g.help, g.name, g.name, formatFloat(g.fn()))
+	}
+	for _, h := range hs {
+		h.mu.Lock()
+		printf("# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += h.counts[i]
+			printf("%s_bucket{le=\"%s\"} %d\n", h.name, formatFloat(le), cumulative)
+		}
+		printf("%s_bucket{le=\"+Inf\"} %d\n%s_sum %s\n%s_count %d\n", h.name, h.count, h.name, formatFloat(h.sum), h.name, h.count)
+		h.mu.Unlock()
+	}
+	return err
+}
+
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", v)
+}
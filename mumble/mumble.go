@@ -0,0 +1,150 @@
+// Package mumble reads Guild Wars 2's MumbleLink shared memory to tell which map the
+// player is on and whether the game is running.
+package mumble
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf16"
+)
+
+// ErrUnsupported is returned on platforms without MumbleLink.
+var ErrUnsupported = errors.New("MumbleLink is only available on Windows")
+
+// linkSize is sizeof(LinkedMem) from the Mumble positional audio API.
+const linkSize = 5460
+
+// Offsets into LinkedMem and GW2's context block.
+const (
+	offTick       = 4
+	offIdentity   = 592
+	offContext    = 1108
+	identityChars = 256
+
+	ctxMapID   = 28
+	ctxMapType = 32
+)
+
+// staleAfter is how long uiTick may stand still before the game counts as closed.
+// It also stops while the game sits on the character select screen.
+const staleAfter = 30 * time.Second
+
+// GW2 map types that are part of WvW.
+const (
+	mapTypeCenter        = 9  // Eternal Battlegrounds
+	mapTypeBlueHome      = 10 // Blue Borderlands
+	mapTypeGreenHome     = 11 // Green Borderlands
+	mapTypeRedHome       = 12 // Red Borderlands
+	mapTypeFortunesVale  = 13
+	mapTypeJumpPuzzle    = 14 // Obsidian Sanctum
+	mapTypeEdgeOfTheMist = 15
+)
+
+// State is what the tracker reports on each change.
+type State struct {
+	Online    bool // The game is running and in a map
+	MapID     uint32
+	MapType   uint32
+	Map       string // Short name such as "EBG", empty outside WvW
+	InWvW     bool
+	Character string
+	Commander bool // The player has a commander tag up
+}
+
+// link is one snapshot of the shared memory.
+type link struct {
+	tick     uint32
+	mapID    uint32
+	mapType  uint32
+	identity identity
+}
+
+type identity struct {
+	Name      string `json:"name"`
+	Commander bool   `json:"commander"`
+}
+
+func parse(buf []byte) link {
+	l := link{
+		tick:    binary.LittleEndian.Uint32(buf[offTick:]),
+		mapID:   binary.LittleEndian.Uint32(buf[offContext+ctxMapID:]),
+		mapType: binary.LittleEndian.Uint32(buf[offContext+ctxMapType:]),
+	}
+	chars := make([]uint16, 0, identityChars)
+	for i := 0; i < identityChars; i++ {
+		c := binary.LittleEndian.Uint16(buf[offIdentity+2*i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+	_ = json.Unmarshal([]byte(string(utf16.Decode(chars))), &l.identity)
+	return l
+}
+
+// MapCode returns the short name of a WvW map type, or "" for other maps.
+func MapCode(mapType uint32) string {
+	switch mapType {
+	case mapTypeCenter:
+		return "EBG"
+	case mapTypeBlueHome:
+		return "BBL"
+	case mapTypeGreenHome:
+		return "GBL"
+	case mapTypeRedHome:
+		return "RBL"
+	case mapTypeFortunesVale:
+		return "Vale"
+	case mapTypeJumpPuzzle:
+		return "OS"
+	case mapTypeEdgeOfTheMist:
+		return "EotM"
+	}
+	return ""
+}
+
+// Watch polls MumbleLink every interval and calls onChange whenever the game starts or
+// stops, or the player changes map. It only returns if MumbleLink can't be opened.
+func Watch(interval time.Duration, onChange func(State)) error {
+	r, err := open()
+	if err != nil {
+		return fmt.Errorf("failed to open MumbleLink: %w", err)
+	}
+	defer r.close()
+
+	buf := make([]byte, linkSize)
+	var last State
+	first := true
+	// The game only counts as running once the tick has been seen to move, so data
+	// left behind by a closed game isn't reported.
+	r.read(buf)
+	lastTick := parse(buf).tick
+	var tickChanged time.Time
+	for {
+		r.read(buf)
+		l := parse(buf)
+		now := time.Now()
+		if l.tick != lastTick {
+			lastTick = l.tick
+			tickChanged = now
+		}
+
+		s := State{Online: !tickChanged.IsZero() && now.Sub(tickChanged) < staleAfter && l.mapID != 0}
+		if s.Online {
+			s.MapID = l.mapID
+			s.MapType = l.mapType
+			s.Map = MapCode(l.mapType)
+			s.InWvW = s.Map != ""
+			s.Character = l.identity.Name
+			s.Commander = l.identity.Commander
+		}
+		if first || s.Online != last.Online || s.MapID != last.MapID || s.Commander != last.Commander {
+			onChange(s)
+			last, first = s, false
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package mumble
+
+type reader struct{}
+
+func open() (*reader, error) { return nil, ErrUnsupported }
+
+func (r *reader) read(buf []byte) {}
+
+func (r *reader) close() {}
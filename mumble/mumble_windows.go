@@ -0,0 +1,40 @@
+//go:build windows
+
+package mumble
+
+import "golang.org/x/sys/windows"
+
+type reader struct {
+	handle windows.Handle
+	addr   uintptr
+}
+
+// open maps the "MumbleLink" shared memory. It is created if the game hasn't started
+// yet, and GW2 will use the same mapping when it does.
+func open() (*reader, error) {
+	name, err := windows.UTF16PtrFromString("MumbleLink")
+	if err != nil {
+		return nil, err
+	}
+	h, err := windows.CreateFileMapping(windows.InvalidHandle, nil, windows.PAGE_READWRITE, 0, linkSize, name)
+	if h == 0 {
+		return nil, err
+	}
+	addr, err := windows.MapViewOfFile(h, windows.FILE_MAP_READ, 0, 0, linkSize)
+	if addr == 0 {
+		windows.CloseHandle(h)
+		return nil, err
+	}
+	return &reader{handle: h, addr: addr}, nil
+}
+
+// read copies the mapped view into buf. The view is only known by its address, so it
+// is copied by the kernel rather than through a Go pointer.
+func (r *reader) read(buf []byte) {
+	windows.ReadProcessMemory(windows.CurrentProcess(), r.addr, &buf[0], uintptr(min(len(buf), linkSize)), nil)
+}
+
+func (r *reader) close() {
+	windows.UnmapViewOfFile(r.addr)
+	windows.CloseHandle(r.handle)
+}
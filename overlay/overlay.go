@@ -0,0 +1,169 @@
+// Package overlay writes a small per-fight summary file for OBS browser and text
+// sources, so commanders who stream can show the last fight on screen.
+package overlay
+
+import (
+	"bytes"
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/templates"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+)
+
+// TopPlayers is how many damage dealers are passed to the template.
+const TopPlayers = 5
+
+// DefaultHTMLTemplate is used for .html output when no template file is configured.
+// It has a transparent background and refreshes itself, for OBS browser sources.
+const DefaultHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<style>
+body { background: transparent; color: #fff; font-family: sans-serif; text-shadow: 1px 1px 2px #000; margin: 0; }
+.result { font-size: 1.4em; font-weight: bold; }
+td { padding: 0 0.6em 0 0; }
+</style>
+</head>
+<body>
+<div class="result">{{.Outcome}} &middot; {{.Kills}} kills / {{.Deaths}} deaths (K/D {{.KD}})</div>
+<div>{{.SquadCount}} squad + {{.AllyCount}} allies vs {{.EnemyCount}} enemies &middot; {{.Duration}}</div>
+<table>
+{{range $i, $p := .TopDamage}}<tr><td>{{inc $i}}.</td><td>{{$p.Name}}</td><td>{{$p.Dps}} DPS</td></tr>
+{{end}}</table>
+</body>
+</html>
+`
+
+// DefaultTextTemplate is used for any other output file, for OBS text sources.
+const DefaultTextTemplate = `{{.Outcome}} | K/D {{.Kills}}/{{.Deaths}} | {{.SquadCount}} vs {{.EnemyCount}} | {{.Duration}}
+{{range $i, $p := .TopDamage}}{{inc $i}}. {{$p.Name}} {{$p.Dps}} DPS
+{{end}}`
+
+// Data is what overlay templates can use.
+type Data struct {
+	FightName  string
+	Time       string
+	Duration   string
+	Outcome    string // Win, Loss or Draw
+	SquadCount int
+	AllyCount  int // Allies not in squad
+	EnemyCount int
+	Kills      int // Enemy players killed by the squad
+	Deaths     int // Squad deaths
+	KD         string
+	TopDamage  []analysis.PlayerDamage
+}
+
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// Writer renders the overlay file after each fight.
+type Writer struct {
+	path string
+	tmpl executor
+}
+
+// Names of the built-in templates in the templates folder.
+var (
+	htmlTemplate = templates.Register("overlay.html.tmpl", DefaultHTMLTemplate)
+	textTemplate = templates.Register("overlay.txt.tmpl", DefaultTextTemplate)
+)
+
+// New prepares a writer for the output path. templatePath may be empty to use
+// overlay.html.tmpl or overlay.txt.tmpl from the templates folder, or the built-in
+// template. Output ending in .html or .htm is escaped as HTML.
+func New(path, templatePath string) (*Writer, error) {
+	isHTML := strings.HasSuffix(strings.ToLower(path), ".html") || strings.HasSuffix(strings.ToLower(path), ".htm")
+	name := textTemplate
+	if isHTML {
+		name = htmlTemplate
+	}
+	src, err := templates.Source(name)
+	if err != nil {
+		return nil, err
+	}
+	if templatePath != "" {
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read overlay template: %w", err)
+		}
+		src = string(data)
+	}
+
+	var tmpl executor
+	if isHTML {
+		tmpl, err = htmltemplate.New("overlay").Funcs(htmltemplate.FuncMap(templates.Funcs)).Parse(src)
+	} else {
+		tmpl, err = texttemplate.New("overlay").Funcs(templates.Funcs).Parse(src)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid overlay template: %w", err)
+	}
+	return &Writer{path: path, tmpl: tmpl}, nil
+}
+
+// NewData collects the template values for a fight.
+func NewData(log *parser.ParsedLog) Data {
+	s := analysis.Summarize(log)
+	d := Data{
+		FightName:  log.FightName,
+		Time:       log.TimeStart,
+		Duration:   log.Duration,
+		Outcome:    outcomeName(s.Outcome()),
+		SquadCount: s.SquadCount,
+		AllyCount:  s.NotInSquadCount,
+		EnemyCount: s.EnemyCount,
+		Kills:      s.EnemyDeaths,
+		Deaths:     s.SquadDeaths,
+		TopDamage:  analysis.SquadDamage(log),
+	}
+	if len(d.TopDamage) > TopPlayers {
+		d.TopDamage = d.TopDamage[:TopPlayers]
+	}
+	if d.Deaths > 0 {
+		d.KD = fmt.Sprintf("%.2f", float64(d.Kills)/float64(d.Deaths))
+	} else {
+		d.KD = fmt.Sprintf("%d.00", d.Kills)
+	}
+	return d
+}
+
+func outcomeName(o analysis.Outcome) string {
+	switch o {
+	case analysis.Win:
+		return "Win"
+	case analysis.Loss:
+		return "Loss"
+	}
+	return "Draw"
+}
+
+// Write renders the overlay for a fight. The file is replaced in one step so OBS
+// never reads it half written.
+func (w *Writer) Write(log *parser.ParsedLog) error {
+	var buf bytes.Buffer
+	if err := w.tmpl.Execute(&buf, NewData(log)); err != nil {
+		return fmt.Errorf("failed to render overlay: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("failed to write overlay: %w", err)
+	}
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write overlay: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write overlay: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package parser
+
+import "strings"
+
+// aliases holds the player renames applied by ParseLog. Keys are lower-case accounts.
+var aliases struct {
+	nicknames map[string]string
+	alts      map[string]string // Alt account -> main account
+}
+
+// SetAliases configures player renames for every log parsed afterwards. nicknames maps
+// accounts to the name shown instead of the character name; alts maps alt accounts to
+// a main account, so the alt's fights count towards the main in run totals. Account
+// names are matched case-insensitively. Call it before any logs are parsed.
+func SetAliases(nicknames, alts map[string]string) {
+	aliases.nicknames = lowerKeys(nicknames)
+	aliases.alts = lowerKeys(alts)
+}
+
+func lowerKeys(in map[string]string) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+func applyAliases(l *ParsedLog) {
+	if aliases.nicknames == nil && aliases.alts == nil {
+		return
+	}
+	renamed := make(map[string]string)
+	for i := range l.Players {
+		p := &l.Players[i]
+		if main := aliases.alts[strings.ToLower(p.Account)]; main != "" {
+			p.Account = main
+		}
+		if nick := aliases.nicknames[strings.ToLower(p.Account)]; nick != "" {
+			renamed[p.Name] = nick
+			p.Name = nick
+		}
+	}
+	renameActors(l, renamed)
+}
+
+// renameActors applies player renames to mechanics and the lists of who ran each
+// extension, which refer to players by character name.
+func renameActors(l *ParsedLog, renamed map[string]string) {
+	for i := range l.Mechanics {
+		for j := range l.Mechanics[i].MechanicsData {
+			d := &l.Mechanics[i].MechanicsData[j]
+			if name, ok := renamed[d.Actor]; ok {
+				d.Actor = name
+			}
+		}
+	}
+	for i := range l.UsedExtensions {
+		running := l.UsedExtensions[i].RunningExtension
+		for j, actor := range running {
+			if name, ok := renamed[actor]; ok {
+				running[j] = name
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// pseudonyms gives every account the same "Player XXXXX" name whenever and in whatever
+// order its logs are parsed, so run totals still add up and screenshots taken on
+// different days match while names are hidden.
+var pseudonyms struct {
+	sync.Mutex
+	on        bool
+	seed      string
+	byAccount map[string]string // Lower-case account -> pseudonym
+	accounts  map[string]string // Pseudonym -> account
+}
+
+// pseudonymAlphabet spells pseudonym codes without letters that look like digits.
+const pseudonymAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewPseudonymSeed returns a random seed for SetAnonymize.
+func NewPseudonymSeed() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// SetAnonymize turns pseudonyms on or off for logs parsed afterwards. The seed keys
+// them, so they stay the same for as long as it does but can't be worked out from a
+// list of accounts.
+func SetAnonymize(on bool, seed string) {
+	pseudonyms.Lock()
+	defer pseudonyms.Unlock()
+	pseudonyms.on = on
+	if seed != pseudonyms.seed {
+		pseudonyms.seed = seed
+		pseudonyms.byAccount, pseudonyms.accounts = nil, nil
+	}
+}
+
+// Anonymizing reports whether logs are parsed with pseudonyms.
+func Anonymizing() bool {
+	pseudonyms.Lock()
+	defer pseudonyms.Unlock()
+	return pseudonyms.on
+}
+
+// Pseudonym returns the stable pseudonym of an account.
+func Pseudonym(account string) string {
+	pseudonyms.Lock()
+	defer pseudonyms.Unlock()
+	return pseudonymLocked(account)
+}
+
+// pseudonymLocked derives the pseudonym from the account alone. Two accounts sharing a
+// code is unlikely; should it happen, the one seen later tries the next code.
+func pseudonymLocked(account string) string {
+	key := strings.ToLower(account)
+	if name, ok := pseudonyms.byAccount[key]; ok {
+		return name
+	}
+	if pseudonyms.byAccount == nil {
+		pseudonyms.byAccount = make(map[string]string)
+		pseudonyms.accounts = make(map[string]string)
+	}
+	var name string
+	for attempt := byte(0); ; attempt++ {
+		name = "Player " + pseudonymCode(key, attempt)
+		if _, taken := pseudonyms.accounts[name]; !taken {
+			break
+		}
+	}
+	pseudonyms.byAccount[key] = name
+	pseudonyms.accounts[name] = account
+	return name
+}
+
+// pseudonymCode spells 25 bits of the account's keyed hash as five characters.
+func pseudonymCode(account string, attempt byte) string {
+	mac := hmac.New(sha256.New, []byte(pseudonyms.seed))
+	mac.Write([]byte(account))
+	mac.Write([]byte{attempt})
+	n := binary.BigEndian.Uint32(mac.Sum(nil))
+	code := make([]byte, 5)
+	for i := range code {
+		code[i] = pseudonymAlphabet[n%32]
+		n /= 32
+	}
+	return string(code)
+}
+
+// RealAccount returns the account behind a pseudonym, or name itself if it is not one.
+func RealAccount(name string) string {
+	pseudonyms.Lock()
+	defer pseudonyms.Unlock()
+	if account, ok := pseudonyms.accounts[name]; ok {
+		return account
+	}
+	return name
+}
+
+func applyAnonymize(l *ParsedLog) {
+	pseudonyms.Lock()
+	defer pseudonyms.Unlock()
+	if !pseudonyms.on {
+		return
+	}
+	renamed := make(map[string]string)
+	for i := range l.Players {
+		p := &l.Players[i]
+		name := pseudonymLocked(p.Account)
+		renamed[p.Name] = name
+		p.Name, p.Account, p.Character = name, name, name
+	}
+	renameActors(l, renamed)
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"gw2-cmd-watch/i18n"
+	"strconv"
+	"strings"
+)
+
+// MaxTestedEIMajor is the newest Elite Insights major version this parser was checked
+// against. Newer JSON still loads, but renamed fields would silently read as zero.
+const MaxTestedEIMajor = 3
+
+// compatRule fills a field from an older EI name when the expected one is missing.
+// Rules only fill empty fields, so they are safe to run on every version. They cover
+// the log's timestamps and arcdps version only; the stats the cards read are not
+// mapped between EI versions, which is what the version check and CompatWarnings are
+// for.
+type compatRule struct {
+	Name  string
+	Apply func(l *ParsedLog)
+}
+
+var compatRules = []compatRule{
+	{
+		// Older EI only wrote timeStart/timeEnd, already in the "std" format
+		Name: "timeStartStd from timeStart",
+		Apply: func(l *ParsedLog) {
+			if l.TimeStartStd == "" {
+				l.TimeStartStd = l.TimeStart
+			}
+			if l.TimeEndStd == "" {
+				l.TimeEndStd = l.TimeEnd
+			}
+		},
+	},
+	{
+		Name: "evtcVersion from arcVersion",
+		Apply: func(l *ParsedLog) {
+			if l.EvtcVersion == "" {
+				l.EvtcVersion = l.ArcVersion
+			}
+		},
+	},
+}
+
+// EIMajorVersion returns the major part of eliteInsightsVersion, e.g. 2 for "2.62.0.0",
+// or 0 if the log does not say.
+func (l *ParsedLog) EIMajorVersion() int {
+	major, _, _ := strings.Cut(l.EliteInsightsVersion, ".")
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(major), "v"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func applyCompat(l *ParsedLog) {
+	for _, r := range compatRules {
+		r.Apply(l)
+	}
+}
+
+// CompatWarnings describes why a log's numbers may be unreliable: an EI version newer
+// than the parser was tested with, or sections the dashboard needs that are missing.
+func CompatWarnings(l *ParsedLog) []string {
+	var warnings []string
+	if major := l.EIMajorVersion(); major > MaxTestedEIMajor {
+		warnings = append(warnings, i18n.T("compat.newer_ei", l.EliteInsightsVersion, MaxTestedEIMajor))
+	}
+	if len(l.Players) > 0 {
+		missing := 0
+		for _, p := range l.Players {
+			if len(p.StatsAll) == 0 && len(p.DpsAll) == 0 && len(p.Defenses) == 0 {
+				missing++
+			}
+		}
+		if missing == len(l.Players) {
+			warnings = append(warnings, i18n.T("compat.no_player_stats"))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,61 @@
+package parser
+
+import "sync"
+
+// Ways of naming players on cards, see SetNameDisplay.
+const (
+	NamesCharacter = "character" // Character name, or the nickname from player_aliases
+	NamesAccount   = "account"   // Account name, e.g. "Name.1234"
+	NamesBoth      = "both"      // "Character (Name.1234)"
+)
+
+var nameDisplay struct {
+	sync.Mutex
+	mode string
+}
+
+// SetNameDisplay picks how players are named in logs parsed afterwards: NamesCharacter,
+// NamesAccount or NamesBoth. Anything else means NamesCharacter.
+func SetNameDisplay(mode string) {
+	nameDisplay.Lock()
+	nameDisplay.mode = mode
+	nameDisplay.Unlock()
+}
+
+// NameDisplay is the mode set with SetNameDisplay.
+func NameDisplay() string {
+	nameDisplay.Lock()
+	defer nameDisplay.Unlock()
+	switch nameDisplay.mode {
+	case NamesAccount, NamesBoth:
+		return nameDisplay.mode
+	}
+	return NamesCharacter
+}
+
+// DisplayName names a player the way SetNameDisplay asked for.
+func DisplayName(character, account string) string {
+	if account == "" {
+		return character
+	}
+	switch NameDisplay() {
+	case NamesAccount:
+		return account
+	case NamesBoth:
+		return character + " (" + account + ")"
+	}
+	return character
+}
+
+func applyNameDisplay(l *ParsedLog) {
+	renamed := make(map[string]string)
+	for i := range l.Players {
+		p := &l.Players[i]
+		p.Character = p.Name
+		if name := DisplayName(p.Name, p.Account); name != p.Name {
+			renamed[p.Name] = name
+			p.Name = name
+		}
+	}
+	renameActors(l, renamed)
+}
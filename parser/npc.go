@@ -0,0 +1,53 @@
+package parser
+
+// IsNPC reports whether a target is a non-player enemy such as a guard, lord, siege
+// or gate, rather than an enemy player or EI's fake "Enemy Players" target.
+func (t Target) IsNPC() bool {
+	return !t.EnemyPlayer && !t.IsFakeTarget
+}
+
+// WithoutNPCDamage returns a copy of the log where squad damage to NPC targets is
+// zeroed, so cards count damage to enemy players only. Logs without enemy player
+// targets, like PvE or non-detailed WvW logs, are returned unchanged, as their damage
+// would all be lost. Per-second damage timelines are not split by target and stay
+// as they are.
+func (l *ParsedLog) WithoutNPCDamage() *ParsedLog {
+	npc := make([]bool, len(l.Targets))
+	var players, npcs bool
+	for i, t := range l.Targets {
+		npc[i] = t.IsNPC()
+		players = players || t.EnemyPlayer
+		npcs = npcs || npc[i]
+	}
+	if !players || !npcs {
+		return l
+	}
+	out := *l
+	out.Players = make([]Player, len(l.Players))
+	for i, p := range l.Players {
+		dps := make([][]PlayerTargetDps, len(p.DpsTargets))
+		for j, phases := range p.DpsTargets {
+			if j < len(npc) && npc[j] {
+				phases = make([]PlayerTargetDps, len(phases))
+			}
+			dps[j] = phases
+		}
+		p.DpsTargets = dps
+
+		minions := make([]Minion, len(p.Minions))
+		for j, mn := range p.Minions {
+			damage := make([][]int, len(mn.TotalTargetDamage))
+			for k, phases := range mn.TotalTargetDamage {
+				if k < len(npc) && npc[k] {
+					phases = make([]int, len(phases))
+				}
+				damage[k] = phases
+			}
+			mn.TotalTargetDamage = damage
+			minions[j] = mn
+		}
+		p.Minions = minions
+		out.Players[i] = p
+	}
+	return &out
+}
@@ -36,34 +36,61 @@ func (f *FlexFloat64) UnmarshalJSON(data []byte) error {
 }
 
 // This file contains the Go structs for parsing the JSON output from Guild Wars 2 Elite Insights.
-// The structure is based on the format for `_detailed_wvw_kill.json` files.
+// The structure is based on the format for `_detailed_wvw_kill.json` files. Other EI
+// outputs (PvE, non-detailed WvW) share the same top-level layout.
 
 type ParsedLog struct {
-	FightName            string               `json:"fightName"`
-	TimeStart            string               `json:"timeStart"`
-	Duration             string               `json:"duration"`
-	EncounterDuration    string               `json:"encounterDuration"`
-	Players              []Player             `json:"players"`
-	Targets              []Target             `json:"targets"`
-	Mechanics            []Mechanic           `json:"mechanics"`
-	CombatReplayMetaData CombatReplayMetaData `json:"combatReplayMetaData"`
+	EliteInsightsVersion string                   `json:"eliteInsightsVersion"`
+	EvtcVersion          string                   `json:"evtcVersion"`
+	ArcVersion           string                   `json:"arcVersion"`
+	FightName            string                   `json:"fightName"`
+	TriggerID            int                      `json:"triggerID"` // Encounter ID, 1 for WvW
+	IsCM                 bool                     `json:"isCM"`
+	Success              bool                     `json:"success"`
+	TimeStart            string                   `json:"timeStart"`
+	TimeEnd              string                   `json:"timeEnd"`
+	TimeStartStd         string                   `json:"timeStartStd"`
+	TimeEndStd           string                   `json:"timeEndStd"`
+	Duration             string                   `json:"duration"`
+	EncounterDuration    string                   `json:"encounterDuration"`
+	Players              []Player                 `json:"players"`
+	Targets              []Target                 `json:"targets"`
+	Mechanics            []Mechanic               `json:"mechanics"`
+	CombatReplayMetaData CombatReplayMetaData     `json:"combatReplayMetaData"`
+	DamageModMap         map[string]DamageModDesc `json:"damageModMap"` // Keyed "d<id>"
+	Phases               []Phase                  `json:"phases"`       // Phase 0 is the full fight
+	UsedExtensions       []Extension              `json:"usedExtensions"`
+}
+
+// Extension is an arcdps addon that added data to the log, e.g. healing stats.
+type Extension struct {
+	Name             string   `json:"name"`
+	RunningExtension []string `json:"runningExtension"` // Players who ran it
 }
 
 type Player struct {
-	Name             string               `json:"name"`
-	Account          string               `json:"account"`
-	Profession       string               `json:"profession"`
-	HasCommanderTag  bool                 `json:"hasCommanderTag"`
-	NotInSquad       bool                 `json:"notInSquad"`
-	StatsAll         []PlayerStats        `json:"statsAll"`
-	DpsAll           []PlayerDps          `json:"dpsAll"`
-	DpsTargets       [][]PlayerTargetDps  `json:"dpsTargets"`
-	Defenses         []PlayerDefense      `json:"defenses"`
-	Support          []PlayerSupport      `json:"support"`
-	StatsTargets     [][]PlayerStatTarget `json:"statsTargets"`
-	CombatReplayData CombatReplayData     `json:"combatReplayData"`
-	ExtHealingStats  ExtHealingStats      `json:"extHealingStats"`
-	ExtBarrierStats  ExtBarrierStats      `json:"extBarrierStats"`
+	Name             string                `json:"name"`
+	Account          string                `json:"account"`
+	Profession       string                `json:"profession"`
+	Group            int                   `json:"group"` // Squad subgroup (party), from 1
+	HasCommanderTag  bool                  `json:"hasCommanderTag"`
+	NotInSquad       bool                  `json:"notInSquad"`
+	StatsAll         []PlayerStats         `json:"statsAll"`
+	DpsAll           []PlayerDps           `json:"dpsAll"`
+	DpsTargets       [][]PlayerTargetDps   `json:"dpsTargets"`
+	Defenses         []PlayerDefense       `json:"defenses"`
+	Support          []PlayerSupport       `json:"support"`
+	StatsTargets     [][]PlayerStatTarget  `json:"statsTargets"`
+	CombatReplayData CombatReplayData      `json:"combatReplayData"`
+	ExtHealingStats  ExtHealingStats       `json:"extHealingStats"`
+	ExtBarrierStats  ExtBarrierStats       `json:"extBarrierStats"`
+	BuffUptimes      []BuffUptime          `json:"buffUptimes"`
+	Damage1S         [][]int               `json:"damage1S"`      // Cumulative damage dealt per second, per phase
+	DamageTaken1S    [][]int               `json:"damageTaken1S"` // Cumulative damage taken per second, per phase
+	DamageModifiers  []DamageModifierGroup `json:"damageModifiers"`
+	Minions          []Minion              `json:"minions"`
+	ActiveTimes      []int                 `json:"activeTimes"` // Milliseconds alive and in the instance, per phase
+	Character        string                `json:"-"`           // Name before SetNameDisplay, after aliases
 }
 
 type PlayerDps struct {
@@ -88,9 +115,12 @@ type PlayerStatTarget struct {
 }
 
 type PlayerDefense struct {
+	DamageTaken          int `json:"damageTaken"`
 	DownCount            int `json:"downCount"`
 	DeadCount            int `json:"deadCount"`
 	ReceivedCrowdControl int `json:"receivedCrowdControl"`
+	BoonStrips           int `json:"boonStrips"`    // Boons stripped from this player
+	DamageBarrier        int `json:"damageBarrier"` // Incoming damage absorbed by barrier
 }
 
 type PlayerSupport struct {
@@ -100,9 +130,10 @@ type PlayerSupport struct {
 }
 
 type CombatReplayData struct {
-	Down      []interface{}   `json:"down"`
+	Start     int             `json:"start"` // ms from fight start of the first position
+	Down      [][]interface{} `json:"down"`
 	Dead      [][]interface{} `json:"dead"`
-	Positions [][]float64     `json:"positions"`
+	Positions [][]float64     `json:"positions"` // [x, y] on the replay map, one per polling interval
 }
 
 type CombatReplayMetaData struct {
@@ -114,12 +145,15 @@ type ExtHealingStats struct {
 }
 
 type Healing struct {
-	Healing int `json:"healing"`
-	Hps     int `json:"hps"`
+	Healing       int `json:"healing"`
+	Hps           int `json:"hps"`
+	DownedHealing int `json:"downedHealing"` // Part of Healing that went to downed players
 }
 
 type ExtBarrierStats struct {
-	OutgoingBarrier []Barrier `json:"outgoingBarrier"`
+	OutgoingBarrier       []Barrier   `json:"outgoingBarrier"`
+	OutgoingBarrierAllies [][]Barrier `json:"outgoingBarrierAllies"` // Per receiving player, per phase
+	IncomingBarrier       []Barrier   `json:"incomingBarrier"`
 }
 
 type Barrier struct {
@@ -127,13 +161,61 @@ type Barrier struct {
 	Bps     int `json:"bps"`
 }
 
+// BuffUptime holds the stack timeline of one buff on a player.
+type BuffUptime struct {
+	ID     int64   `json:"id"`
+	States [][]int `json:"states"` // [time ms, stacks] pairs, each valid until the next entry
+}
+
+// StacksAt returns the number of stacks at the given time in milliseconds.
+func (b BuffUptime) StacksAt(timeMs int) int {
+	stacks := 0
+	for _, st := range b.States {
+		if len(st) < 2 || st[0] > timeMs {
+			break
+		}
+		stacks = st[1]
+	}
+	return stacks
+}
+
+// Minion is a pet, turret or summon owned by a player.
+type Minion struct {
+	Name              string  `json:"name"`
+	TotalTargetDamage [][]int `json:"totalTargetDamage"` // Damage per target, per phase
+}
+
+// DamageModDesc describes a damage modifier from damageModMap.
+type DamageModDesc struct {
+	Name     string `json:"name"`
+	Incoming bool   `json:"incoming"`
+}
+
+// DamageModifierGroup holds one modifier's data for a player, one entry per phase.
+type DamageModifierGroup struct {
+	ID              int64                `json:"id"`
+	DamageModifiers []DamageModifierData `json:"damageModifiers"`
+}
+
+type DamageModifierData struct {
+	HitCount      int     `json:"hitCount"`      // Hits the modifier applied to
+	TotalHitCount int     `json:"totalHitCount"` // Hits it could have applied to
+	DamageGain    float64 `json:"damageGain"`
+	TotalDamage   int     `json:"totalDamage"`
+}
+
 type Target struct {
-	Name         string          `json:"name"`
-	EnemyPlayer  bool            `json:"enemyPlayer"`
-	IsFakeTarget bool            `json:"isFake"`
-	StatsAll     []TargetStats   `json:"statsAll"`
-	DpsAll       []TargetDps     `json:"dpsAll"`
-	Defenses     []TargetDefense `json:"defenses"`
+	Name         string `json:"name"`
+	EnemyPlayer  bool   `json:"enemyPlayer"`
+	IsFakeTarget bool   `json:"isFake"`
+	// Boss health, only meaningful for PvE targets
+	TotalHealth         int              `json:"totalHealth"`
+	FinalHealth         int              `json:"finalHealth"`
+	HealthPercentBurned float64          `json:"healthPercentBurned"`
+	StatsAll            []TargetStats    `json:"statsAll"`
+	DpsAll              []TargetDps      `json:"dpsAll"`
+	Defenses            []TargetDefense  `json:"defenses"`
+	CombatReplayData    CombatReplayData `json:"combatReplayData"` // Enemy downs and deaths in WvW
 }
 
 type TargetStats struct {
@@ -173,6 +255,10 @@ func ParseLog(jsonPath string) (*ParsedLog, error) {
 	if err != nil {
 		return nil, err
 	}
+	applyCompat(&log)
+	applyAliases(&log)
+	applyNameDisplay(&log)
+	applyAnonymize(&log)
 
 	return &log, nil
 }
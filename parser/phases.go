@@ -0,0 +1,98 @@
+package parser
+
+// Phase is a section of the fight produced by EI when ParsePhases is enabled.
+type Phase struct {
+	Name  string `json:"name"`
+	Start int64  `json:"start"` // ms from fight start
+	End   int64  `json:"end"`
+}
+
+// ForPhase returns a copy of the log where every per-phase array holds only the given
+// phase at index 0, so code that reads the full fight from [0] reads that phase instead.
+// Down and death times are limited to the phase window; other timelines stay full-fight.
+// Phase 0 returns the log unchanged.
+func (l *ParsedLog) ForPhase(phase int) *ParsedLog {
+	if phase <= 0 || phase >= len(l.Phases) {
+		return l
+	}
+	out := *l
+	start, end := int(l.Phases[phase].Start), int(l.Phases[phase].End)
+
+	out.Players = make([]Player, len(l.Players))
+	for i, p := range l.Players {
+		p.StatsAll = pick(p.StatsAll, phase)
+		p.DpsAll = pick(p.DpsAll, phase)
+		p.Defenses = pick(p.Defenses, phase)
+		p.Support = pick(p.Support, phase)
+		p.ActiveTimes = pick(p.ActiveTimes, phase)
+		p.DpsTargets = pickEach(p.DpsTargets, phase)
+		p.StatsTargets = pickEach(p.StatsTargets, phase)
+		p.ExtHealingStats.OutgoingHealingAllies = pickEach(p.ExtHealingStats.OutgoingHealingAllies, phase)
+		p.ExtBarrierStats.OutgoingBarrier = pick(p.ExtBarrierStats.OutgoingBarrier, phase)
+		p.ExtBarrierStats.OutgoingBarrierAllies = pickEach(p.ExtBarrierStats.OutgoingBarrierAllies, phase)
+		p.ExtBarrierStats.IncomingBarrier = pick(p.ExtBarrierStats.IncomingBarrier, phase)
+
+		mods := make([]DamageModifierGroup, len(p.DamageModifiers))
+		for j, g := range p.DamageModifiers {
+			g.DamageModifiers = pick(g.DamageModifiers, phase)
+			mods[j] = g
+		}
+		p.DamageModifiers = mods
+
+		minions := make([]Minion, len(p.Minions))
+		for j, mn := range p.Minions {
+			mn.TotalTargetDamage = pickEach(mn.TotalTargetDamage, phase)
+			minions[j] = mn
+		}
+		p.Minions = minions
+
+		p.CombatReplayData.Down = inWindow(p.CombatReplayData.Down, start, end)
+		p.CombatReplayData.Dead = inWindow(p.CombatReplayData.Dead, start, end)
+		out.Players[i] = p
+	}
+
+	out.Targets = make([]Target, len(l.Targets))
+	for i, t := range l.Targets {
+		t.StatsAll = pick(t.StatsAll, phase)
+		t.DpsAll = pick(t.DpsAll, phase)
+		t.Defenses = pick(t.Defenses, phase)
+		t.CombatReplayData.Down = inWindow(t.CombatReplayData.Down, start, end)
+		t.CombatReplayData.Dead = inWindow(t.CombatReplayData.Dead, start, end)
+		out.Targets[i] = t
+	}
+	return &out
+}
+
+// pick returns a one-element slice holding s[phase], or nil if the phase is missing.
+func pick[T any](s []T, phase int) []T {
+	if phase >= len(s) {
+		return nil
+	}
+	return []T{s[phase]}
+}
+
+// pickEach applies pick to every inner per-phase slice.
+func pickEach[T any](s [][]T, phase int) [][]T {
+	if s == nil {
+		return nil
+	}
+	out := make([][]T, len(s))
+	for i, inner := range s {
+		out[i] = pick(inner, phase)
+	}
+	return out
+}
+
+// inWindow keeps replay [start, end] pairs that start inside the phase.
+func inWindow(pairs [][]interface{}, start, end int) [][]interface{} {
+	var out [][]interface{}
+	for _, pair := range pairs {
+		if len(pair) == 0 {
+			continue
+		}
+		if t, ok := pair[0].(float64); ok && int(t) >= start && int(t) <= end {
+			out = append(out, pair)
+		}
+	}
+	return out
+}
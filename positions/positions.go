@@ -0,0 +1,177 @@
+// Package positions exports where everyone was during a fight, from Elite Insights'
+// combat replay data, as CSV and GeoJSON files for external map and plotting tools.
+package positions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/templates"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Side is which side of the fight a track belongs to.
+type Side string
+
+const (
+	Squad Side = "squad"
+	Ally  Side = "ally"  // Players outside the recording player's squad
+	Enemy Side = "enemy" // Enemy players, only listed one by one in detailed WvW logs
+)
+
+// Track is one player's path through the fight.
+type Track struct {
+	Name       string
+	Account    string // Empty for enemies
+	Profession string
+	Side       Side
+	StartMs    int          // Fight time of the first point
+	Points     [][2]float64 // [x, y] on the replay map, one per polling interval
+}
+
+// TimeMs is the fight time of the track's i-th point.
+func (t Track) TimeMs(i, pollingRate int) int {
+	return t.StartMs + i*pollingRate
+}
+
+// Tracks collects the paths of every player and enemy player with positions.
+func Tracks(log *parser.ParsedLog) []Track {
+	var out []Track
+	add := func(t Track, replay parser.CombatReplayData) {
+		t.StartMs = replay.Start
+		for _, pos := range replay.Positions {
+			if len(pos) >= 2 {
+				t.Points = append(t.Points, [2]float64{pos[0], pos[1]})
+			}
+		}
+		if len(t.Points) > 0 {
+			out = append(out, t)
+		}
+	}
+	for _, p := range log.Players {
+		side := Squad
+		if p.NotInSquad {
+			side = Ally
+		}
+		add(Track{Name: p.Name, Account: p.Account, Profession: p.Profession, Side: side}, p.CombatReplayData)
+	}
+	for _, t := range log.Targets {
+		if t.EnemyPlayer && !t.IsFakeTarget {
+			add(Track{Name: t.Name, Profession: analysis.EnemyProfession(t.Name), Side: Enemy}, t.CombatReplayData)
+		}
+	}
+	return out
+}
+
+// csvTemplate is the CSV file, see the templates package. A custom one can change
+// the column set, e.g. drop enemies or add the fight name to every row.
+var csvTemplate = templates.Register("positions.csv.tmpl", `time_ms,side,name,account,profession,x,y
+{{range .Rows}}{{.TimeMs}},{{.Side}},{{csv .Name}},{{csv .Account}},{{csv .Profession}},{{.X}},{{.Y}}
+{{end}}`)
+
+// CSVData is what positions.csv.tmpl is rendered with.
+type CSVData struct {
+	FightName string
+	Rows      []CSVRow
+}
+
+// CSVRow is one player's position at one polling interval.
+type CSVRow struct {
+	TimeMs     int // Fight time
+	Side       Side
+	Name       string
+	Account    string
+	Profession string
+	X, Y       string // Replay map pixels, formatted without trailing zeros
+}
+
+// WriteCSV writes one row per player per polling interval: fight time in ms, who,
+// and the position.
+func WriteCSV(w io.Writer, log *parser.ParsedLog) error {
+	tmpl, err := templates.Parse(csvTemplate, nil)
+	if err != nil {
+		return err
+	}
+	rate := log.CombatReplayMetaData.PollingRate
+	data := CSVData{FightName: log.FightName}
+	for _, t := range Tracks(log) {
+		for i, pos := range t.Points {
+			data.Rows = append(data.Rows, CSVRow{
+				TimeMs:     t.TimeMs(i, rate),
+				Side:       t.Side,
+				Name:       t.Name,
+				Account:    t.Account,
+				Profession: t.Profession,
+				X:          strconv.FormatFloat(pos[0], 'f', -1, 64),
+				Y:          strconv.FormatFloat(pos[1], 'f', -1, 64),
+			})
+		}
+	}
+	return tmpl.Execute(w, data)
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string         `json:"type"`
+	Geometry   geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type geometry struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+// WriteGeoJSON writes a FeatureCollection with a LineString per player. Coordinates
+// are replay map pixels, not longitude and latitude, with y growing downwards as in
+// the map image. Each point's fight time is start_ms plus its index times
+// interval_ms.
+func WriteGeoJSON(w io.Writer, log *parser.ParsedLog) error {
+	rate := log.CombatReplayMetaData.PollingRate
+	fc := featureCollection{Type: "FeatureCollection", Features: []feature{}}
+	for _, t := range Tracks(log) {
+		fc.Features = append(fc.Features, feature{
+			Type:     "Feature",
+			Geometry: geometry{Type: "LineString", Coordinates: t.Points},
+			Properties: map[string]any{
+				"name":        t.Name,
+				"account":     t.Account,
+				"profession":  t.Profession,
+				"side":        t.Side,
+				"start_ms":    t.StartMs,
+				"interval_ms": rate,
+			},
+		})
+	}
+	return json.NewEncoder(w).Encode(fc)
+}
+
+// Export writes the fight's positions next to its JSON as "<name>_positions.csv"
+// and "<name>_positions.geojson" and returns their paths.
+func Export(jsonPath string, log *parser.ParsedLog) (csvPath, geoPath string, err error) {
+	if len(Tracks(log)) == 0 {
+		return "", "", errors.New("no combat replay positions in this log")
+	}
+	base := strings.TrimSuffix(jsonPath, ".json") + "_positions"
+	csvPath, geoPath = base+".csv", base+".geojson"
+	for path, write := range map[string]func(io.Writer, *parser.ParsedLog) error{csvPath: WriteCSV, geoPath: WriteGeoJSON} {
+		var buf bytes.Buffer
+		if err := write(&buf, log); err != nil {
+			return "", "", fmt.Errorf("failed to export positions: %w", err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return "", "", fmt.Errorf("failed to export positions: %w", err)
+		}
+	}
+	return csvPath, geoPath, nil
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"gw2-cmd-watch/parser"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RunLabel is how a run folder is shown to people. With player pseudonyms on, the
+// commander's account at the start of the name is replaced too.
+func RunLabel(runName string) string {
+	if !parser.Anonymizing() {
+		return runName
+	}
+	account, rest, ok := strings.Cut(runName, "_")
+	if !ok {
+		return runName
+	}
+	return CommanderLabel(account) + "_" + rest
+}
+
+// CommanderLabel is how a run's commander account is shown, a pseudonym if they are on.
+func CommanderLabel(account string) string {
+	if !parser.Anonymizing() || !strings.Contains(account, ".") {
+		return account
+	}
+	return parser.Pseudonym(account)
+}
+
+// SummarySuffix ends the name of a fight's summary file, written next to its JSON as
+// "<fight>.summary.json". It is not a fight log.
+const SummarySuffix = ".summary.json"
+
+// SummaryPath returns where the summary of the fight at jsonPath is written.
+func SummaryPath(jsonPath string) string {
+	return strings.TrimSuffix(jsonPath, ".json") + SummarySuffix
+}
+
+// IsFightFile reports whether a file in a run folder is a fight JSON rather than
+// run.json or a summary.
+func IsFightFile(name string) bool {
+	return strings.HasSuffix(name, ".json") && name != RunMetaFile && !strings.HasSuffix(name, SummarySuffix)
+}
+
+// RunLogFiles lists the fight JSON files of a run folder in name order, skipping
+// run.json and summaries.
+func RunLogFiles(runPath string) ([]string, error) {
+	entries, err := os.ReadDir(runPath)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && IsFightFile(e.Name()) {
+			files = append(files, filepath.Join(runPath, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// LatestRun returns the path of the most recently written run folder, or "" if the
+// archive has no runs. Run names start with the commander, so name order is not
+// time order.
+func LatestRun() (string, error) {
+	entries, err := os.ReadDir(LogArchive)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	var latest string
+	var latestMod int64
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if mod := info.ModTime().UnixNano(); latest == "" || mod > latestMod {
+			latest, latestMod = filepath.Join(LogArchive, e.Name()), mod
+		}
+	}
+	return latest, nil
+}
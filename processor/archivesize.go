@@ -0,0 +1,47 @@
+package processor
+
+import (
+	"gw2-cmd-watch/metrics"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// archiveSizeTTL keeps metrics scrapes from walking a large archive every few seconds.
+const archiveSizeTTL = time.Minute
+
+var archiveSize struct {
+	sync.Mutex
+	bytes   int64
+	checked time.Time
+}
+
+func init() {
+	metrics.RegisterGauge("gw2cw_archive_bytes", "Size of the log archive on disk.", func() float64 {
+		return float64(ArchiveSize())
+	})
+}
+
+// ArchiveSize returns the total size of the files in the archive, refreshed at most
+// once a minute.
+func ArchiveSize() int64 {
+	archiveSize.Lock()
+	defer archiveSize.Unlock()
+	if time.Since(archiveSize.checked) < archiveSizeTTL {
+		return archiveSize.bytes
+	}
+	var total int64
+	_ = filepath.WalkDir(LogArchive, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	archiveSize.bytes = total
+	archiveSize.checked = time.Now()
+	return total
+}
@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfIssue is an ELI3.conf option set to something the app cannot work with.
+type ConfIssue struct {
+	Key  string
+	Got  string // Empty if the option is missing
+	Want string
+}
+
+func (c ConfIssue) String() string {
+	got := c.Got
+	if got == "" {
+		got = "(missing)"
+	}
+	return fmt.Sprintf("%s=%s (needs %s)", c.Key, got, c.Want)
+}
+
+// requiredConf are the ELI3.conf options the app depends on. Without them EI writes no
+// JSON, writes it somewhere else or compressed, or leaves out the WvW and replay data
+// the dashboard reads.
+var requiredConf = []struct{ key, want string }{
+	{"SaveOutJSON", "True"},
+	{"SaveOutHTML", "True"},
+	{"DetailledWvW", "True"},
+	{"ParseCombatReplay", "True"},
+	{"ParsePhases", "True"},
+	{"CompressRaw", "False"},
+	{"SaveAtOut", "False"},
+}
+
+// LintEIConf checks ELI3.conf for options that would keep the app from finding or
+// reading EI's output, e.g. after the file was edited by hand.
+func LintEIConf() ([]ConfIssue, error) {
+	values, err := readEIConf()
+	if err != nil {
+		return nil, err
+	}
+	var issues []ConfIssue
+	for _, r := range requiredConf {
+		if got := values[r.key]; !strings.EqualFold(got, r.want) {
+			issues = append(issues, ConfIssue{Key: r.key, Got: got, Want: r.want})
+		}
+	}
+	want, err := eiOutLocation()
+	if err != nil {
+		return issues, err
+	}
+	if got := values["OutLocation"]; !strings.EqualFold(filepath.Clean(got), filepath.Clean(want)) {
+		issues = append(issues, ConfIssue{Key: "OutLocation", Got: got, Want: want})
+	}
+	return issues, nil
+}
+
+// EIWebhookURL is the Discord webhook EI posts each fight to, from ELI3.conf. It is
+// "" when none is set.
+func EIWebhookURL() (string, error) {
+	values, err := readEIConf()
+	if err != nil {
+		return "", err
+	}
+	return values["WebhookURL"], nil
+}
+
+// readEIConf reads the options in ELI3.conf.
+func readEIConf() (map[string]string, error) {
+	data, err := os.ReadFile(EIConfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", EIConfPath, err)
+	}
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		if k, v, ok := strings.Cut(strings.TrimRight(line, "\r"), "="); ok {
+			values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return values, nil
+}
+
+// FixEIConf sets the options of issues found by LintEIConf to what the app needs,
+// leaving the rest of ELI3.conf as it is.
+func FixEIConf(issues []ConfIssue) error {
+	data, err := os.ReadFile(EIConfPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", EIConfPath, err)
+	}
+	conf := string(data)
+	for _, issue := range issues {
+		conf = SetConfValue(conf, issue.Key, issue.Want)
+	}
+	return os.WriteFile(EIConfPath, []byte(conf), 0644)
+}
@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"encoding/json"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/parser"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// IndexFile caches the summary of every fight in a run folder, so a large run can be
+// listed without parsing each fight. It is not a .json file, so it is never taken for
+// a fight.
+const IndexFile = "summaries.cache"
+
+// indexVersion is raised when FightSummary gains fields, so summaries cached by older
+// versions are made again.
+const indexVersion = 6
+
+// IndexEntry is the cached summary of a fight file. It is current while the file's
+// size and modification time match.
+type IndexEntry struct {
+	Version int                   `json:"version,omitempty"`
+	Size    int64                 `json:"size"`
+	ModTime time.Time             `json:"mod_time"`
+	Summary analysis.FightSummary `json:"summary"`
+}
+
+// RunIndex maps fight file names to their cached summaries.
+type RunIndex map[string]IndexEntry
+
+// indexMu serializes read-modify-write of index files.
+var indexMu sync.Mutex
+
+// LoadIndex reads the index of a run folder. A missing or unreadable index is empty,
+// as it can always be rebuilt from the fights.
+func LoadIndex(runPath string) RunIndex {
+	idx := make(RunIndex)
+	data, err := os.ReadFile(filepath.Join(runPath, IndexFile))
+	if err != nil {
+		return idx
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return make(RunIndex)
+	}
+	return idx
+}
+
+// Lookup returns the cached summary of a fight file if it is current.
+func (idx RunIndex) Lookup(info os.FileInfo) (analysis.FightSummary, bool) {
+	e, ok := idx[info.Name()]
+	if !ok || e.Version != indexVersion || e.Size != info.Size() || !e.ModTime.Equal(info.ModTime()) {
+		return analysis.FightSummary{}, false
+	}
+	return e.Summary, true
+}
+
+// UpdateIndex adds the summaries of fight files, by full path, to their run's index
+// and drops entries whose file is gone.
+func UpdateIndex(runPath string, summaries map[string]analysis.FightSummary) error {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	idx := LoadIndex(runPath)
+	for path, s := range summaries {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		idx[info.Name()] = IndexEntry{Version: indexVersion, Size: info.Size(), ModTime: info.ModTime(), Summary: s}
+	}
+	for name := range idx {
+		if _, err := os.Stat(filepath.Join(runPath, name)); err != nil {
+			delete(idx, name)
+		}
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(runPath, IndexFile), data, 0644)
+}
+
+// DisplaySummary names the players of a summary from the index the way fights are
+// parsed now, as the index keeps the names of when the fight was first summarized.
+func DisplaySummary(s *analysis.FightSummary) {
+	if parser.Anonymizing() {
+		anonymizeSummary(s)
+		return
+	}
+	players := make([]analysis.PlayerFight, len(s.Players))
+	for i, p := range s.Players {
+		if p.Character != "" {
+			p.Name = parser.DisplayName(p.Character, p.Account)
+		}
+		players[i] = p
+	}
+	s.Players = players
+}
+
+// anonymizeSummary swaps the real names kept in the index for pseudonyms.
+func anonymizeSummary(s *analysis.FightSummary) {
+	players := make([]analysis.PlayerFight, len(s.Players))
+	for i, p := range s.Players {
+		name := parser.Pseudonym(p.Account)
+		p.Name, p.Account, p.Character = name, name, name
+		players[i] = p
+	}
+	s.Players = players
+	if s.Commander != "" {
+		s.Commander = parser.Pseudonym(s.Commander)
+	}
+}
+
+// RunSummaries returns the summary of every fight in a run, in fight order, taking
+// them from the run's index where current and parsing the rest. Fights that can't be
+// read are skipped. New summaries are added to the index unless anonymizing.
+func RunSummaries(runPath string) ([]analysis.FightSummary, error) {
+	files, err := RunLogFiles(runPath)
+	if err != nil {
+		return nil, err
+	}
+	idx := LoadIndex(runPath)
+	parsed := make(map[string]analysis.FightSummary)
+	var out []analysis.FightSummary
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if s, ok := idx.Lookup(info); ok {
+			DisplaySummary(&s)
+			out = append(out, s)
+			continue
+		}
+		log, err := parser.ParseLog(path)
+		if err != nil {
+			continue
+		}
+		s := analysis.Summarize(log)
+		parsed[path] = s
+		out = append(out, s)
+	}
+	if len(parsed) > 0 && !parser.Anonymizing() {
+		if err := UpdateIndex(runPath, parsed); err != nil {
+			logger.Warn("failed to save the run index: %v", err)
+		}
+	}
+	return out, nil
+}
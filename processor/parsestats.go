@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ParseStats records how EI did on one fight, kept in run.json to help tune the EI
+// settings and spot pathological logs.
+type ParseStats struct {
+	Parsed    time.Time `json:"parsed"`
+	Seconds   float64   `json:"seconds"`         // Wall time of the EI process
+	Batch     int       `json:"batch,omitempty"` // Logs parsed by the same process, if more than one
+	ExitCode  int       `json:"exit_code"`
+	TimedOut  bool      `json:"timed_out,omitempty"` // EI was stopped after the EI timeout
+	LogBytes  int64     `json:"log_bytes"`
+	JSONBytes int64     `json:"json_bytes"`
+	HTMLBytes int64     `json:"html_bytes,omitempty"`
+}
+
+// pendingStatsTTL is how long stats wait for their fight to be archived. Fights that
+// aren't archived by then never will be, e.g. because their JSON couldn't be read.
+const pendingStatsTTL = time.Hour
+
+// pendingStats holds stats of parsed logs until their fight is archived, by log path.
+var pendingStats struct {
+	sync.Mutex
+	byLog map[string]ParseStats
+}
+
+// recordStats keeps the stats of an EI process for each of its logs, replacing those
+// of an earlier attempt, and drops stats nobody took in time.
+func recordStats(logPaths []string, run eiRun) {
+	pendingStats.Lock()
+	defer pendingStats.Unlock()
+	if pendingStats.byLog == nil {
+		pendingStats.byLog = make(map[string]ParseStats)
+	}
+	now := time.Now().UTC()
+	for path, stats := range pendingStats.byLog {
+		if now.Sub(stats.Parsed) > pendingStatsTTL {
+			delete(pendingStats.byLog, path)
+		}
+	}
+	for _, path := range logPaths {
+		stats := ParseStats{
+			Parsed:   now,
+			Seconds:  run.took.Seconds(),
+			ExitCode: run.exitCode,
+			TimedOut: run.timedOut,
+			LogBytes: fileSize(path),
+		}
+		if len(logPaths) > 1 {
+			stats.Batch = len(logPaths)
+		}
+		pendingStats.byLog[path] = stats
+	}
+}
+
+func takeStats(logPath string) (stats ParseStats, ok bool) {
+	pendingStats.Lock()
+	defer pendingStats.Unlock()
+	stats, ok = pendingStats.byLog[logPath]
+	delete(pendingStats.byLog, logPath)
+	return stats, ok
+}
+
+// TakeParseStats returns the stats of logPath's parse, with the output sizes of its
+// archived files, and forgets them. ok is false for logs this process did not parse.
+func TakeParseStats(logPath, archivedJSONPath string) (stats ParseStats, ok bool) {
+	stats, ok = takeStats(logPath)
+	if !ok {
+		return stats, false
+	}
+	stats.JSONBytes = fileSize(archivedJSONPath)
+	stats.HTMLBytes = fileSize(strings.TrimSuffix(archivedJSONPath, ".json") + ".html")
+	return stats, true
+}
+
+// FailedParsesFile keeps the stats of logs EI failed on, in the archive folder, as
+// they have no run to be kept in.
+const FailedParsesFile = "parse_failures.json"
+
+// maxFailedParses is how many failures FailedParsesFile keeps, newest last.
+const maxFailedParses = 100
+
+// FailedParse is the stats of a log EI failed on.
+type FailedParse struct {
+	Log string `json:"log"` // File name of the log
+	ParseStats
+}
+
+// failedMu serializes read-modify-write of FailedParsesFile.
+var failedMu sync.Mutex
+
+// LoadFailedParses reads the recent failures. A missing file has none.
+func LoadFailedParses() ([]FailedParse, error) {
+	data, err := os.ReadFile(filepath.Join(LogArchive, FailedParsesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var failed []FailedParse
+	if err := json.Unmarshal(data, &failed); err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
+// saveFailedStats moves the stats of a log that failed into FailedParsesFile.
+func saveFailedStats(logPath string) error {
+	stats, ok := takeStats(logPath)
+	if !ok {
+		return nil // EI never ran on it
+	}
+	failedMu.Lock()
+	defer failedMu.Unlock()
+	failed, _ := LoadFailedParses() // An unreadable file is started over
+	failed = append(failed, FailedParse{Log: filepath.Base(logPath), ParseStats: stats})
+	if len(failed) > maxFailedParses {
+		failed = failed[len(failed)-maxFailedParses:]
+	}
+	if err := os.MkdirAll(LogArchive, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(failed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(LogArchive, FailedParsesFile), data, 0644)
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
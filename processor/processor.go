@@ -1,19 +1,256 @@
 package processor
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"gw2-cmd-watch/apperr"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/metrics"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	FightLogTemp = "FightLogTemp"
-	LogArchive   = "Log_Archive"
+	DefaultFightLogTemp = "FightLogTemp"
+	DefaultLogArchive   = "Log_Archive"
 )
 
+// Working directories, relative to the app folder unless configured. See SetDirs.
+var (
+	FightLogTemp = DefaultFightLogTemp
+	LogArchive   = DefaultLogArchive
+)
+
+// SetDirs overrides the temp and archive directories. Empty values keep the defaults.
+// Call it before anything is processed.
+func SetDirs(tempDir, archiveDir string) {
+	if tempDir != "" {
+		FightLogTemp = tempDir
+	}
+	if archiveDir != "" {
+		LogArchive = archiveDir
+	}
+}
+
+// ClearTemp removes leftovers of earlier EI runs from FightLogTemp. Only EI output
+// files are deleted, in case the temp directory is shared with other files.
+func ClearTemp() error {
+	if err := os.MkdirAll(FightLogTemp, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(FightLogTemp)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".html", ".conf", ".part":
+			if !e.IsDir() {
+				if err := os.Remove(filepath.Join(FightLogTemp, e.Name())); err != nil {
+					logger.Warn("could not remove %s: %v", e.Name(), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+const (
+	// EIConfPath is the Elite Insights settings file used for every parse.
+	EIConfPath = "ELI3.conf"
+	// BatchMinLogs is the queue depth at which logs are parsed by one EI process.
+	BatchMinLogs = 3
+	// MaxBatchSize caps how many logs go to one EI process.
+	MaxBatchSize = 10
+)
+
+// eiSlots limits concurrent EI processes, see SetMaxConcurrent.
+var eiSlots = make(chan struct{}, 1)
+
+// SetMaxConcurrent sets how many EI processes may run at once. Call it before any
+// processing starts.
+func SetMaxConcurrent(n int) {
+	if n < 1 {
+		n = 1
+	}
+	eiSlots = make(chan struct{}, n)
+}
+
+// DefaultEITimeout is how long one log may take to parse before EI is stopped.
+const DefaultEITimeout = 5 * time.Minute
+
+var eiTimeout = DefaultEITimeout
+
+// SetEITimeout sets how long EI may take per log; a batch gets that much per log in
+// it. Zero keeps the default.
+func SetEITimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultEITimeout
+	}
+	eiTimeout = d
+}
+
+// Progress reports on a running EI process.
+type Progress struct {
+	ID    int64  // Tells EI processes apart, as batches of the same size share a label
+	Label string // The log's file name, or how many logs are in a batch
+	Line  string // The last line EI printed, "" when it has just started
+	Done  bool
+}
+
+var progressFn = func(Progress) {}
+
+// OnProgress calls fn whenever EI starts, prints a line or exits. fn must not block.
+// Call it before any processing starts.
+func OnProgress(fn func(Progress)) {
+	progressFn = fn
+}
+
+// ErrCanceled is the error for logs whose parse was stopped with Cancel.
+var ErrCanceled = errors.New("parse canceled")
+
+// running holds the EI processes that can be canceled, by progress ID.
+var running struct {
+	sync.Mutex
+	lastID  int64
+	cancels map[int64]context.CancelCauseFunc
+}
+
+// Cancel stops the EI process with the ID reported through OnProgress, and reports
+// whether it was running. Its logs fail with ErrCanceled.
+func Cancel(id int64) bool {
+	running.Lock()
+	defer running.Unlock()
+	cancel, ok := running.cancels[id]
+	if ok {
+		cancel(ErrCanceled)
+	}
+	return ok
+}
+
+// trackRunning makes an EI process cancelable and returns its new ID.
+func trackRunning(cancel context.CancelCauseFunc) (id int64, untrack func()) {
+	running.Lock()
+	defer running.Unlock()
+	if running.cancels == nil {
+		running.cancels = make(map[int64]context.CancelCauseFunc)
+	}
+	running.lastID++
+	id = running.lastID
+	running.cancels[id] = cancel
+	return id, func() {
+		running.Lock()
+		delete(running.cancels, id)
+		running.Unlock()
+	}
+}
+
+// removeOutputs deletes whatever EI wrote for logs before it was stopped.
+func removeOutputs(logPaths []string) {
+	for _, path := range logPaths {
+		logBase := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		matches, _ := filepath.Glob(filepath.Join(FightLogTemp, logBase+"_*"))
+		for _, m := range matches {
+			if err := os.Remove(m); err != nil {
+				logger.Warn("could not remove %s: %v", m, err)
+			}
+		}
+	}
+}
+
+// progressWriter collects EI's output and reports every line as it is printed. EI
+// rewrites some lines in place with \r, so that also ends a line.
+type progressWriter struct {
+	out     bytes.Buffer
+	partial []byte
+	report  func(line string)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.out.Write(p)
+	for _, b := range p {
+		if b != '\n' && b != '\r' {
+			w.partial = append(w.partial, b)
+			continue
+		}
+		if line := strings.TrimSpace(string(w.partial)); line != "" {
+			w.report(line)
+		}
+		w.partial = w.partial[:0]
+	}
+	return len(p), nil
+}
+
+// ApplyEISettings writes the output location and the memory and threading options into
+// ELI3.conf, leaving the file untouched when they already match.
+func ApplyEISettings(memoryLimitMB int, singleThreaded bool) error {
+	data, err := os.ReadFile(EIConfPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", EIConfPath, err)
+	}
+	outLocation, err := eiOutLocation()
+	if err != nil {
+		return err
+	}
+	conf := SetConfValue(string(data), "OutLocation", outLocation)
+	conf = SetConfValue(conf, "MemoryLimit", strconv.Itoa(memoryLimitMB))
+	threaded := "False"
+	if singleThreaded {
+		threaded = "True"
+	}
+	conf = SetConfValue(conf, "SingleThreaded", threaded)
+	if conf == string(data) {
+		return nil
+	}
+	return os.WriteFile(EIConfPath, []byte(conf), 0644)
+}
+
+// eiOutLocation is the OutLocation EI must write to: FightLogTemp, relative to the app
+// folder by default.
+func eiOutLocation() (string, error) {
+	if FightLogTemp == DefaultFightLogTemp {
+		return `.\` + DefaultFightLogTemp, nil
+	}
+	outLocation, err := filepath.Abs(FightLogTemp)
+	if err != nil {
+		return "", fmt.Errorf("invalid temp directory %s: %w", FightLogTemp, err)
+	}
+	return outLocation, nil
+}
+
+// SetEIAnonymous turns EI's Anonymous option on or off in ELI3.conf, so new HTML and
+// JSON reports replace player names too.
+func SetEIAnonymous(on bool) error {
+	data, err := os.ReadFile(EIConfPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", EIConfPath, err)
+	}
+	value := "False"
+	if on {
+		value = "True"
+	}
+	conf := SetConfValue(string(data), "Anonymous", value)
+	if conf == string(data) {
+		return nil
+	}
+	return os.WriteFile(EIConfPath, []byte(conf), 0644)
+}
+
+// Result is the outcome of parsing one arcdps log.
+type Result struct {
+	LogPath  string
+	JSONPath string // Temporary JSON in FightLogTemp
+	Err      error
+}
+
 // ProcessLog runs the Elite Insights CLI and returns the path to the temporary JSON file it creates.
 // It no longer handles run creation or file archiving.
 func ProcessLog(logPath string) (string, error) {
@@ -24,40 +261,251 @@ func ProcessLog(logPath string) (string, error) {
 	}
 
 	// 2. Run Elite Insights CLI
+	run, err := runEI(EIConfPath, []string{logPath})
+	if err == nil {
+		var jsonPath string
+		if jsonPath, err = findOutput(run.output, logPath); err == nil {
+			return jsonPath, nil
+		}
+	}
+	keepFailedStats(logPath, err)
+	return "", err
+}
+
+// keepFailedStats saves the stats of a log EI failed on for the diagnostics view.
+func keepFailedStats(logPath string, err error) {
+	if errors.Is(err, ErrCanceled) {
+		return
+	}
+	if err := saveFailedStats(logPath); err != nil {
+		logger.Warn("could not save parse stats of %s: %v", filepath.Base(logPath), err)
+	}
+}
+
+// ProcessLogs parses several logs with a single EI process, with ParseMultipleLogs
+// enabled. The outputs are matched back to their source logs by file name. If EI fails
+// on the batch, the logs are parsed again one at a time, so a single corrupt log only
+// fails itself.
+func ProcessLogs(logPaths []string) []Result {
+	results := make([]Result, len(logPaths))
+	fail := func(err error) []Result {
+		for i, path := range logPaths {
+			results[i] = Result{LogPath: path, Err: err}
+		}
+		return results
+	}
+	if err := os.MkdirAll(FightLogTemp, 0755); err != nil {
+		return fail(fmt.Errorf("failed to create %s directory: %w", FightLogTemp, err))
+	}
+	confPath, err := writeBatchConf()
+	if err != nil {
+		return fail(err)
+	}
+	run, err := runEI(confPath, logPaths)
+	if err != nil && len(logPaths) > 1 && apperr.KindOf(err) == apperr.ParseFailed {
+		logger.Warn("Elite Insights failed on a batch of %d logs, parsing them one at a time: %v", len(logPaths), err)
+		removeOutputs(logPaths)
+		for i, path := range logPaths {
+			jsonPath, err := ProcessLog(path)
+			results[i] = Result{LogPath: path, JSONPath: jsonPath, Err: err}
+		}
+		return results
+	}
+	if err != nil {
+		for _, path := range logPaths {
+			keepFailedStats(path, err)
+		}
+		return fail(err)
+	}
+	for i, path := range logPaths {
+		jsonPath, err := findOutput(run.output, path)
+		if err != nil {
+			keepFailedStats(path, err)
+		}
+		results[i] = Result{LogPath: path, JSONPath: jsonPath, Err: err}
+	}
+	return results
+}
+
+// eiRun is the outcome of one EI process.
+type eiRun struct {
+	output   string
+	took     time.Duration
+	exitCode int
+	timedOut bool
+}
+
+// runEI runs the Elite Insights CLI on one or more logs and returns its output.
+func runEI(confPath string, logPaths []string) (eiRun, error) {
 	cliPath := filepath.Join("GW2EICLI", "GuildWars2EliteInsights-CLI.exe")
-	confPath := "ELI3.conf"
-	cmd := exec.Command(cliPath, "-c", confPath, logPath)
+	args := append([]string{"-c", confPath}, logPaths...)
+
+	eiSlots <- struct{}{}
+	defer func() { <-eiSlots }()
+	defer metrics.EIDuration.ObserveSince(time.Now())
 
-	output, err := cmd.CombinedOutput()
+	label := filepath.Base(logPaths[0])
+	if len(logPaths) > 1 {
+		label = fmt.Sprintf("%d logs", len(logPaths))
+	}
+	timeout := eiTimeout * time.Duration(len(logPaths))
+	parent, stop := context.WithCancelCause(context.Background())
+	defer stop(nil)
+	id, untrack := trackRunning(stop)
+	defer untrack()
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, cliPath, args...)
+	cmd.WaitDelay = time.Second // Don't wait on output pipes EI's children still hold
+	w := &progressWriter{report: func(line string) { progressFn(Progress{ID: id, Label: label, Line: line}) }}
+	cmd.Stdout, cmd.Stderr = w, w
+
+	logger.Info("running Elite Insights on %s", strings.Join(logPaths, ", "))
+	progressFn(Progress{ID: id, Label: label})
+	start := time.Now()
+	err := cmd.Run()
+	progressFn(Progress{ID: id, Label: label, Done: true})
+	output := w.out.Bytes()
+	run := eiRun{output: string(output), took: time.Since(start)}
+	if cmd.ProcessState != nil {
+		run.exitCode = cmd.ProcessState.ExitCode()
+	}
+	logger.Debug("Elite Insights output for %d log(s):\n%s", len(logPaths), string(output))
+
+	if errors.Is(context.Cause(parent), ErrCanceled) {
+		logger.Info("Elite Insights on %s was canceled", label)
+		removeOutputs(logPaths)
+		return eiRun{}, ErrCanceled
+	}
+	run.timedOut = errors.Is(ctx.Err(), context.DeadlineExceeded)
+	recordStats(logPaths, run)
+	if run.timedOut {
+		removeOutputs(logPaths)
+		return eiRun{}, apperr.New(apperr.ParseFailed, fmt.Errorf("Elite Insights took longer than %s on %s and was stopped", timeout, label))
+	}
 
 	// Check for specific .NET error
 	if strings.Contains(string(output), "You must install .NET to run this application") {
-		return "", fmt.Errorf("EliteInsights-CLI required .NET runtime not found. Please install .NET 8.0.12 or a compatible version to continue")
+		return eiRun{}, apperr.New(apperr.DotNetMissing, fmt.Errorf("EliteInsights-CLI required .NET runtime not found. Please install .NET 8.0.12 or a compatible version to continue"))
 	}
 
 	// Check for other execution errors
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, exec.ErrNotFound) {
+		return eiRun{}, apperr.New(apperr.CLIMissing, fmt.Errorf("Elite Insights CLI not found at %s: %w", cliPath, err))
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to execute Elite Insights CLI: %w\nOutput: %s", err, string(output))
+		return eiRun{}, apperr.New(apperr.ParseFailed, fmt.Errorf("failed to execute Elite Insights CLI: %w\nOutput: %s", err, string(output)))
 	}
+	return run, nil
+}
 
-	// 3. Determine expected output file name and wait for it
+// findOutput locates and waits for the JSON EI wrote for logPath.
+func findOutput(cliOutput, logPath string) (string, error) {
+	// 3. Find the output file. EI names it <log>_<encounter>_<result>.json, which is
+	// only _detailed_wvw_kill.json for detailed WvW logs.
 	baseName := filepath.Base(logPath)
-	ext := filepath.Ext(baseName)
-	jsonBaseName := strings.TrimSuffix(baseName, ext) + "_detailed_wvw_kill.json"
-	tempJSONPath := filepath.Join(FightLogTemp, jsonBaseName)
+	logBase := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	tempJSONPath, err := waitForOutputJSON(cliOutput, logBase)
+	if err != nil {
+		return "", apperr.New(apperr.ParseFailed, fmt.Errorf("error waiting for JSON file: %w", err))
+	}
 
 	unlockedJSONPath, err := waitForFile(tempJSONPath)
 	if err != nil {
-		return "", fmt.Errorf("error waiting for JSON file: %w", err)
+		return "", apperr.New(apperr.ParseFailed, fmt.Errorf("error waiting for JSON file: %w", err))
 	}
 
 	return unlockedJSONPath, nil
 }
 
+// writeBatchConf copies ELI3.conf into FightLogTemp with ParseMultipleLogs turned on.
+func writeBatchConf() (string, error) {
+	data, err := os.ReadFile(EIConfPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", EIConfPath, err)
+	}
+	conf := SetConfValue(string(data), "ParseMultipleLogs", "True")
+	path := filepath.Join(FightLogTemp, "ELI3_batch.conf")
+	if err := os.WriteFile(path, []byte(conf), 0644); err != nil {
+		return "", fmt.Errorf("failed to write batch EI config: %w", err)
+	}
+	return path, nil
+}
+
+// SetConfValue sets key=value in the contents of an EI .conf file, appending the
+// line if the key is missing.
+func SetConfValue(conf, key, value string) string {
+	lines := strings.Split(conf, "\n")
+	for i, line := range lines {
+		k, _, ok := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if ok && strings.TrimSpace(k) == key {
+			newLine := key + "=" + value
+			if strings.HasSuffix(line, "\r") {
+				newLine += "\r"
+			}
+			lines[i] = newLine
+			return strings.Join(lines, "\n")
+		}
+	}
+	if len(conf) > 0 && !strings.HasSuffix(conf, "\n") {
+		conf += "\n"
+	}
+	return conf + key + "=" + value
+}
+
+// DisplayName turns an archived EI JSON file name into the name shown in the log list,
+// e.g. "20250101-200000_detailed_wvw_kill.json" -> "20250101-200000".
+func DisplayName(jsonPath string) string {
+	name := strings.TrimSuffix(filepath.Base(jsonPath), ".json")
+	if i := strings.Index(name, "_detailed_wvw_"); i > 0 {
+		return name[:i]
+	}
+	// Drop the _<encounter>_<result> suffix of other logs
+	parts := strings.Split(name, "_")
+	if len(parts) >= 3 {
+		return strings.Join(parts[:len(parts)-2], "_")
+	}
+	return name
+}
+
+// waitForOutputJSON finds the JSON EI wrote for a log. It prefers a path mentioned in
+// the CLI output and otherwise scans FightLogTemp for <logBase>_*.json.
+func waitForOutputJSON(cliOutput, logBase string) (string, error) {
+	for _, field := range strings.Fields(cliOutput) {
+		if strings.HasSuffix(strings.ToLower(field), ".json") && strings.Contains(filepath.Base(field), logBase) {
+			if _, err := os.Stat(field); err == nil {
+				return field, nil
+			}
+		}
+	}
+
+	timeout := time.After(60 * time.Second)
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		matches, err := filepath.Glob(filepath.Join(FightLogTemp, logBase+"_*.json"))
+		if err != nil {
+			return "", err
+		}
+		if len(matches) > 0 {
+			if len(matches) > 1 {
+				logger.Warn("several EI outputs for %s, using %s", logBase, matches[0])
+			}
+			return matches[0], nil
+		}
+		select {
+		case <-timeout:
+			return "", fmt.Errorf("timed out waiting for Elite Insights output for %s (the log may be too short or unsupported)", logBase)
+		case <-ticker.C:
+		}
+	}
+}
+
 // ArchiveLogFiles moves the generated .json and .html files from the temp folder to the final run archive directory.
 func ArchiveLogFiles(tempJsonPath, finalRunPath string) (string, error) {
 	if err := os.MkdirAll(finalRunPath, 0755); err != nil {
-		return "", fmt.Errorf("failed to create final run directory %s: %w", finalRunPath, err)
+		metrics.ArchiveFailures.Inc()
+		return "", apperr.New(apperr.ArchiveFailed, fmt.Errorf("failed to create final run directory %s: %w", finalRunPath, err))
 	}
 
 	// Infer HTML path from JSON path
@@ -67,90 +515,148 @@ func ArchiveLogFiles(tempJsonPath, finalRunPath string) (string, error) {
 
 	// Move JSON file
 	archivedJSONPath := filepath.Join(finalRunPath, jsonBaseName)
-	if err := moveFileWithRetry(tempJsonPath, archivedJSONPath, 3); err != nil {
-		return "", fmt.Errorf("failed to move JSON file: %w", err)
+	if err := moveFileWithRetry(tempJsonPath, archivedJSONPath); err != nil {
+		metrics.ArchiveFailures.Inc()
+		return "", apperr.New(apperr.ArchiveFailed, fmt.Errorf("failed to move JSON file: %w", err))
 	}
+	metrics.LogsArchived.Inc()
 
 	// Move HTML file
 	unlockedHTMLPath, err := waitForFile(tempHTMLPath)
 	if err != nil {
-		fmt.Printf("Warning: could not find matching HTML file to archive: %v\n", err)
+		logger.Warn("could not find matching HTML file to archive: %v", err)
 	} else {
 		archivedHTMLPath := filepath.Join(finalRunPath, htmlBaseName)
-		if err := moveFileWithRetry(unlockedHTMLPath, archivedHTMLPath, 3); err != nil {
+		if err := moveFileWithRetry(unlockedHTMLPath, archivedHTMLPath); err != nil {
 			// Don't return an error, just print a warning, as the JSON is the critical part
-			fmt.Printf("Warning: failed to move HTML file: %v\n", err)
+			logger.Warn("failed to move HTML file: %v", err)
 		}
 	}
 
 	return archivedJSONPath, nil
 }
 
-// moveFileWithRetry attempts to copy a file and then delete the source, with a given number of retries.
-func moveFileWithRetry(src, dest string, retries int) error {
+// Default archive move retry settings, see SetMoveRetry.
+const (
+	DefaultMoveRetries = 3
+	DefaultMoveBackoff = 250 * time.Millisecond
+)
+
+var (
+	moveRetries = DefaultMoveRetries
+	moveBackoff = DefaultMoveBackoff
+)
+
+// SetMoveRetry sets how many attempts an archive move gets and the delay before the
+// first retry, which doubles on each further attempt. Zero values keep the defaults.
+func SetMoveRetry(retries int, backoff time.Duration) {
+	if retries < 1 {
+		retries = DefaultMoveRetries
+	}
+	if backoff <= 0 {
+		backoff = DefaultMoveBackoff
+	}
+	moveRetries = retries
+	moveBackoff = backoff
+}
+
+// moveFileWithRetry moves a file, retrying with backoff. A rename is tried first, which
+// is instant and atomic on the same volume; across volumes it falls back to copying.
+func moveFileWithRetry(src, dest string) error {
 	var lastErr error
-	for i := 0; i < retries; i++ {
-		// Open the source file
-		sourceFile, err := os.Open(src)
-		if err != nil {
-			lastErr = fmt.Errorf("could not open source file %s: %w", src, err)
-			time.Sleep(250 * time.Millisecond)
-			continue
+	delay := moveBackoff
+	for i := 0; i < moveRetries; i++ {
+		if i > 0 {
+			time.Sleep(delay)
+			delay *= 2
 		}
-
-		// Create the destination file
-		destFile, err := os.Create(dest)
-		if err != nil {
-			sourceFile.Close() // Close source since we're failing here
-			lastErr = fmt.Errorf("could not create destination file %s: %w", dest, err)
-			time.Sleep(250 * time.Millisecond)
+		err := os.Rename(src, dest)
+		if err == nil {
+			if err := syncFile(dest); err != nil {
+				logger.Warn("could not sync %s: %v", dest, err)
+			}
+			return nil
+		}
+		if IsSharingViolation(err) {
+			// Locked by a sync client or another guild member's app; wait and retry
+			lastErr = err
 			continue
 		}
+		logger.Debug("rename %s failed, copying instead: %v", src, err)
+		if lastErr = copyAndRemove(src, dest); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to move file %s after %d retries: %w", src, moveRetries, lastErr)
+}
 
-		// Copy data
-		_, err = destFile.ReadFrom(sourceFile)
+// copyAndRemove copies src next to dest under a temporary name, syncs it, renames it
+// into place and then deletes src, so dest is never seen half written.
+func copyAndRemove(src, dest string) error {
+	// Open the source file
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("could not open source file %s: %w", src, err)
+	}
+	defer sourceFile.Close()
 
-		// Explicitly close files right after use
-		sourceFile.Close()
-		destFile.Close()
+	// Create the destination file
+	partPath := dest + ".part"
+	destFile, err := os.Create(partPath)
+	if err != nil {
+		return fmt.Errorf("could not create destination file %s: %w", partPath, err)
+	}
 
-		if err != nil {
-			lastErr = fmt.Errorf("could not copy data from %s to %s: %w", src, dest, err)
-			time.Sleep(250 * time.Millisecond)
-			continue
-		}
+	// Copy data and flush it to disk before it becomes visible
+	_, err = destFile.ReadFrom(sourceFile)
+	if err == nil {
+		err = destFile.Sync()
+	}
+	if closeErr := destFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("could not copy data from %s to %s: %w", src, dest, err)
+	}
 
-		// Verify the copy by checking file info
-		srcInfo, err := os.Stat(src)
-		if err != nil {
-			lastErr = fmt.Errorf("could not stat source file %s: %w", src, err)
-			time.Sleep(250 * time.Millisecond)
-			continue
-		}
-		destInfo, err := os.Stat(dest)
-		if err != nil {
-			lastErr = fmt.Errorf("could not stat destination file %s: %w", dest, err)
-			time.Sleep(250 * time.Millisecond)
-			continue
-		}
+	// Verify the copy by checking file info
+	srcInfo, err := sourceFile.Stat()
+	if err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("could not stat source file %s: %w", src, err)
+	}
+	destInfo, err := os.Stat(partPath)
+	if err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("could not stat destination file %s: %w", partPath, err)
+	}
+	if srcInfo.Size() != destInfo.Size() {
+		os.Remove(partPath)
+		return fmt.Errorf("file copy failed: size mismatch for %s", src)
+	}
 
-		if srcInfo.Size() != destInfo.Size() {
-			lastErr = fmt.Errorf("file copy failed: size mismatch for %s", src)
-			time.Sleep(250 * time.Millisecond)
-			continue
-		}
+	if err := os.Rename(partPath, dest); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("could not move %s into place: %w", partPath, err)
+	}
 
-		// If copy is verified, delete the source file
-		if err := os.Remove(src); err != nil {
-			lastErr = fmt.Errorf("failed to remove source file %s after copy: %w", src, err)
-			time.Sleep(250 * time.Millisecond)
-			continue
-		}
+	// If copy is verified, delete the source file
+	sourceFile.Close()
+	if err := os.Remove(src); err != nil {
+		return fmt.Errorf("failed to remove source file %s after copy: %w", src, err)
+	}
+	return nil
+}
 
-		// Success
-		return nil
+// syncFile flushes a file's contents to disk.
+func syncFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf("failed to move file %s after %d retries: %w", src, retries, lastErr)
+	defer f.Close()
+	return f.Sync()
 }
 
 // waitForFile polls for a file to exist and then for it to be unlocked.
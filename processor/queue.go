@@ -0,0 +1,73 @@
+package processor
+
+import "sync"
+
+// DefaultQueueCapacity is how many logs may wait before TryPush refuses more.
+const DefaultQueueCapacity = 100
+
+// Queue holds arcdps logs waiting for Elite Insights. The watcher pushes, the
+// processing goroutines pop, possibly several at a time for a batch parse.
+// Push never blocks, so the watcher keeps taking events however deep the queue is;
+// only TryPush, for logs sent from outside, is held to the capacity. Holding the
+// watcher back instead would overflow the OS change buffer and lose logs for good,
+// while a queued log costs only its path. The load on the machine is bounded by
+// SetMaxConcurrent, which caps how many EI processes run however long the queue is.
+type Queue struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	items    []string
+	capacity int
+}
+
+// NewQueue creates a queue whose TryPush accepts at most capacity logs (no limit if
+// <= 0).
+func NewQueue(capacity int) *Queue {
+	q := &Queue{capacity: capacity}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Push adds a log to the end of the queue, past the capacity if need be.
+func (q *Queue) Push(path string) {
+	q.mu.Lock()
+	q.items = append(q.items, path)
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// TryPush adds a log unless the queue is full, and reports whether it was added.
+func (q *Queue) TryPush(path string) bool {
+	q.mu.Lock()
+	if q.capacity > 0 && len(q.items) >= q.capacity {
+		q.mu.Unlock()
+		return false
+	}
+	q.items = append(q.items, path)
+	q.mu.Unlock()
+	q.cond.Broadcast()
+	return true
+}
+
+// Len is the number of logs waiting.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// PopBatch blocks until at least one log is queued, then removes and returns up to max
+// logs in arrival order.
+func (q *Queue) PopBatch(max int) []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 {
+		q.cond.Wait()
+	}
+	n := len(q.items)
+	if max > 0 && n > max {
+		n = max
+	}
+	batch := append([]string(nil), q.items[:n]...)
+	q.items = q.items[n:]
+	return batch
+}
@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunMetaFile holds per-run settings inside a run folder. It is not a fight log.
+const RunMetaFile = "run.json"
+
+// Run types.
+const (
+	RunTypeOpenField = ""    // Default zerg / open-field run
+	RunTypeGvG       = "gvg" // Scrim night tracked as rounds
+)
+
+// RunMeta is the contents of run.json. The run list reads who, when and where from
+// here, falling back to the folder name for runs from older versions.
+type RunMeta struct {
+	Created   time.Time         `json:"created,omitzero"`    // UTC time the folder was made
+	Closed    time.Time         `json:"closed,omitzero"`     // UTC time the run ended, zero while live
+	Started   time.Time         `json:"started,omitzero"`    // UTC start of the first fight
+	Commander string            `json:"commander,omitempty"` // Account the run was named after
+	Map       string            `json:"map,omitempty"`       // Map of the first fight
+	Tags      []string          `json:"tags,omitempty"`
+	Notes     string            `json:"notes,omitempty"`
+	Links     []string          `json:"links,omitempty"` // e.g. a video of the raid
+	Type      string            `json:"type,omitempty"`
+	FightMaps map[string]string `json:"fight_maps,omitempty"` // Live map per fight, from MumbleLink
+	Matchup   *Matchup          `json:"matchup,omitempty"`
+	Flips     []ObjectiveFlip   `json:"objective_flips,omitempty"`
+	Handoffs  []Handoff         `json:"handoffs,omitempty"`
+	// ParseStats are per fight, by display name like FightMaps
+	ParseStats map[string]ParseStats `json:"parse_stats,omitempty"`
+	// Totals caches numbers the run list sorts by, see RunTotals
+	Totals *RunTotals `json:"totals,omitempty"`
+}
+
+// RunTotals are counted from the run's fights. They are current while Fights matches
+// the number of fight files in the folder.
+type RunTotals struct {
+	Fights int `json:"fights"`
+	Kills  int `json:"kills"` // Enemy players killed by the squad
+}
+
+// ObjectiveFlip is a WvW objective changing hands during a run.
+type ObjectiveFlip struct {
+	Time      time.Time `json:"time"`
+	Objective string    `json:"objective"`
+	Type      string    `json:"type"` // Camp, Tower, Keep or Castle
+	Map       string    `json:"map"`  // EBG, RBL, BBL or GBL
+	Owner     string    `json:"owner"`
+}
+
+// Handoff is the commander tag changing hands between two fights of a run.
+type Handoff struct {
+	Time  time.Time `json:"time"`  // Start of the first fight of the new commander
+	Fight string    `json:"fight"` // Its display name
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+}
+
+// Matchup is the WvW matchup at the start of a run, from the GW2 API.
+type Matchup struct {
+	MatchID  string            `json:"match_id"` // e.g. "2-3" (region-tier)
+	Color    string            `json:"color"`    // Our team: red, green or blue
+	Teams    map[string]string `json:"teams"`    // Team names by color
+	Skirmish int               `json:"skirmish"`
+}
+
+// Opponents lists the other two teams' names.
+func (m Matchup) Opponents() []string {
+	var out []string
+	for _, color := range []string{"red", "green", "blue"} {
+		if color != m.Color && m.Teams[color] != "" {
+			out = append(out, m.Teams[color])
+		}
+	}
+	return out
+}
+
+// Tier is the matchup's tier from its ID, or 0 if the ID is not "region-tier".
+func (m Matchup) Tier() int {
+	_, tier, ok := strings.Cut(m.MatchID, "-")
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.Atoi(tier)
+	return n
+}
+
+// LoadRunMeta reads run.json from a run folder. A missing file yields an empty RunMeta.
+func LoadRunMeta(runPath string) (RunMeta, error) {
+	var meta RunMeta
+	data, err := os.ReadFile(filepath.Join(runPath, RunMetaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return meta, err
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("failed to parse %s: %w", RunMetaFile, err)
+	}
+	return meta, nil
+}
+
+// SaveRunMeta writes run.json into a run folder, creating the folder if needed.
+func SaveRunMeta(runPath string, meta RunMeta) error {
+	if err := os.MkdirAll(runPath, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Written to a temp file first, so a crash or a reader never sees half a file
+	path := filepath.Join(runPath, RunMetaFile)
+	tmp := path + ".part"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// runMetaMu serializes read-modify-write of run.json files.
+var runMetaMu sync.Mutex
+
+// UpdateRunMeta applies change to a run's run.json and saves it. Changes made from
+// several goroutines are all kept, as each one reads the file the last one saved.
+func UpdateRunMeta(runPath string, change func(meta *RunMeta)) error {
+	runMetaMu.Lock()
+	defer runMetaMu.Unlock()
+	meta, err := LoadRunMeta(runPath)
+	if err != nil {
+		return err
+	}
+	change(&meta)
+	return SaveRunMeta(runPath, meta)
+}
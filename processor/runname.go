@@ -0,0 +1,55 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// runStampLen is the length of the timestamp in a run name, "2006-01-02_15-04-05".
+const runStampLen = 19
+
+// RunNameParts are what a run folder name is made of. Runs from older versions have
+// no map or tier.
+type RunNameParts struct {
+	Commander string
+	Timestamp string // "2006-01-02_15-04-05" in the local time zone
+	Map       string // Map of the first fight, e.g. "EBG"
+	Tier      int    // WvW matchup tier, 0 if not in the name
+}
+
+// RunName builds a run folder name like "Name.1234_2025-01-01_20-00-00_EBG_T2". The
+// map and tier are left out when empty or zero.
+func RunName(commander, timestamp, mapName string, tier int) string {
+	name := commander + "_" + timestamp
+	if mapName != "" {
+		name += "_" + mapName
+	}
+	if tier > 0 {
+		name += fmt.Sprintf("_T%d", tier)
+	}
+	return name
+}
+
+// ParseRunName splits a run folder name into its parts. Names that don't follow the
+// pattern, e.g. renamed by hand, come back with just the commander part set.
+func ParseRunName(name string) RunNameParts {
+	commander, rest, ok := strings.Cut(name, "_")
+	p := RunNameParts{Commander: commander}
+	if !ok || len(rest) < runStampLen {
+		return p
+	}
+	p.Timestamp, rest = rest[:runStampLen], rest[runStampLen:]
+	for _, part := range strings.Split(rest, "_") {
+		if t, ok := strings.CutPrefix(part, "T"); ok {
+			if n, err := strconv.Atoi(t); err == nil && n > 0 {
+				p.Tier = n
+				continue
+			}
+		}
+		if part != "" && p.Map == "" {
+			p.Map = part
+		}
+	}
+	return p
+}
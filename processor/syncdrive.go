@@ -0,0 +1,18 @@
+package processor
+
+import "os"
+
+// Archives on OneDrive, Dropbox or a NAS share can contain online-only placeholders
+// and files briefly locked by the sync client. The platform files implement the checks.
+
+// IsCloudPlaceholder reports whether a file is an online-only placeholder that a sync
+// client would have to download before it can be read.
+func IsCloudPlaceholder(info os.FileInfo) bool {
+	return info != nil && isCloudPlaceholder(info)
+}
+
+// IsSharingViolation reports whether err means another process, typically a sync
+// client, has the file open. Such errors are worth retrying.
+func IsSharingViolation(err error) bool {
+	return err != nil && isSharingViolation(err)
+}
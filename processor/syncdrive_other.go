@@ -0,0 +1,9 @@
+//go:build !windows
+
+package processor
+
+import "os"
+
+func isCloudPlaceholder(info os.FileInfo) bool { return false }
+
+func isSharingViolation(err error) bool { return false }
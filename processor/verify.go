@@ -0,0 +1,188 @@
+package processor
+
+import (
+	"fmt"
+	"gw2-cmd-watch/parser"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Problems found by VerifyArchive.
+const (
+	ProblemEmpty       = "empty file"
+	ProblemBadJSON     = "JSON does not parse"
+	ProblemMissingHTML = "HTML report missing"
+	ProblemOrphanHTML  = "HTML report without JSON"
+)
+
+// QuarantineDirName is the folder next to the archive that broken fights are moved to.
+const QuarantineDirName = "Log_Quarantine"
+
+// Problem is one inconsistency in the archive.
+type Problem struct {
+	Kind string
+	Path string // The file at fault; the fight JSON for ProblemMissingHTML
+	Err  error  // Why the JSON does not parse
+}
+
+// Broken reports whether the fight can no longer be shown and should be quarantined.
+// A fight that only lacks its HTML report can be repaired by parsing the log again.
+func (p Problem) Broken() bool {
+	return p.Kind != ProblemMissingHTML
+}
+
+func (p Problem) String() string {
+	if p.Err != nil {
+		return fmt.Sprintf("%s: %s (%v)", p.Path, p.Kind, p.Err)
+	}
+	return fmt.Sprintf("%s: %s", p.Path, p.Kind)
+}
+
+// VerifyArchive checks every run folder in LogArchive: each fight JSON must parse and
+// have its HTML report, every HTML report must have its JSON, and no file may be
+// empty. It returns the number of fights checked and the problems found.
+func VerifyArchive() (int, []Problem, error) {
+	entries, err := os.ReadDir(LogArchive)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read %s: %w", LogArchive, err)
+	}
+	var checked int
+	var problems []Problem
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		n, runProblems, err := verifyRun(filepath.Join(LogArchive, e.Name()))
+		if err != nil {
+			return checked, problems, err
+		}
+		checked += n
+		problems = append(problems, runProblems...)
+	}
+	return checked, problems, nil
+}
+
+func verifyRun(runPath string) (int, []Problem, error) {
+	entries, err := os.ReadDir(runPath)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read %s: %w", runPath, err)
+	}
+	sizes := make(map[string]int64)
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		sizes[e.Name()] = info.Size()
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	var checked int
+	var problems []Problem
+	for _, name := range names {
+		path := filepath.Join(runPath, name)
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".json":
+			if strings.HasSuffix(name, SummarySuffix) {
+				continue // Written by the app from the fight, not worth checking
+			}
+			if name != RunMetaFile {
+				checked++
+			}
+			if sizes[name] == 0 {
+				problems = append(problems, Problem{Kind: ProblemEmpty, Path: path})
+				continue
+			}
+			if name == RunMetaFile {
+				continue
+			}
+			if _, err := parser.ParseLog(path); err != nil {
+				problems = append(problems, Problem{Kind: ProblemBadJSON, Path: path, Err: err})
+				continue
+			}
+			// An empty report is as good as none
+			if sizes[base+".html"] == 0 {
+				problems = append(problems, Problem{Kind: ProblemMissingHTML, Path: path})
+			}
+		case ".html":
+			if _, ok := sizes[base+".json"]; !ok {
+				problems = append(problems, Problem{Kind: ProblemOrphanHTML, Path: path})
+			}
+		}
+	}
+	return checked, problems, nil
+}
+
+// QuarantineDir returns the folder broken fights are moved to, next to LogArchive.
+func QuarantineDir() string {
+	return filepath.Join(filepath.Dir(filepath.Clean(LogArchive)), QuarantineDirName)
+}
+
+// Quarantine moves the file of a problem, and the other half of its JSON/HTML pair and
+// its summary if there are any, out of the archive into the same run folder under
+// QuarantineDir.
+func Quarantine(p Problem) error {
+	runName := filepath.Base(filepath.Dir(p.Path))
+	dest := filepath.Join(QuarantineDir(), runName)
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+	paths := []string{p.Path}
+	if filepath.Base(p.Path) != RunMetaFile {
+		base := strings.TrimSuffix(p.Path, filepath.Ext(p.Path))
+		for _, ext := range []string{".json", ".html", SummarySuffix} {
+			if pair := base + ext; pair != p.Path {
+				if _, err := os.Stat(pair); err == nil {
+					paths = append(paths, pair)
+				}
+			}
+		}
+	}
+	for _, path := range paths {
+		if err := moveFileWithRetry(path, filepath.Join(dest, filepath.Base(path))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegenerateHTML parses logPath again and puts the HTML report EI writes next to the
+// archived fight JSON. The new JSON is discarded, so the archived fight is unchanged.
+func RegenerateHTML(jsonPath, logPath string) error {
+	tempJSON, err := ProcessLog(logPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempJSON)
+	tempHTML := strings.TrimSuffix(tempJSON, ".json") + ".html"
+	if _, err := waitForFile(tempHTML); err != nil {
+		return fmt.Errorf("Elite Insights wrote no HTML report: %w", err)
+	}
+	htmlPath := strings.TrimSuffix(jsonPath, ".json") + ".html"
+	return moveFileWithRetry(tempHTML, htmlPath)
+}
+
+// ReparseFight parses logPath again and replaces the archived fight JSON, and its HTML
+// report if EI wrote one, with the new output. It repairs a truncated or corrupt JSON.
+func ReparseFight(jsonPath, logPath string) error {
+	tempJSON, err := ProcessLog(logPath)
+	if err != nil {
+		return err
+	}
+	tempHTML := strings.TrimSuffix(tempJSON, ".json") + ".html"
+	if err := moveFileWithRetry(tempJSON, jsonPath); err != nil {
+		return err
+	}
+	if _, err := os.Stat(tempHTML); err != nil {
+		return nil
+	}
+	return moveFileWithRetry(tempHTML, strings.TrimSuffix(jsonPath, ".json")+".html")
+}
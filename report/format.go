@@ -0,0 +1,50 @@
+package report
+
+import (
+	"gw2-cmd-watch/locale"
+	"gw2-cmd-watch/templates"
+	"text/template"
+	"time"
+)
+
+// topPlayers is how many players the top lists of the report show.
+const topPlayers = 5
+
+// reportTemplate is the command-line report, see the templates package. Custom ones
+// can be written as Markdown to paste into a guild forum or wiki.
+var reportTemplate = templates.Register("report.tmpl", `Report {{datetime .Since}} to {{datetime .Until}}
+Runs: {{.Runs}} | Fights: {{.Fights}} ({{.Record.Wins}}W {{.Record.Losses}}L {{.Record.Draws}}D) | Kills {{num .Kills}} / Deaths {{num .Deaths}} | In combat {{.Combat}}
+{{if .Players}}
+Top damage:
+{{range $i, $p := .TopDamage}}{{inc $i}}. {{pad 20 $p.Name}} {{lpad 15 (num $p.Damage)}} dmg over {{$p.Fights}} fights
+{{end}}
+Top average DPS:
+{{range $i, $p := .TopDps}}{{inc $i}}. {{pad 20 $p.Name}} {{lpad 8 (num $p.AvgDps)}} dps over {{$p.Fights}} fights
+{{end}}
+Attendance ({{len .Players}} players):
+{{range .Players}}{{pad 20 .Name}} {{pad 28 .Account}} {{.Runs}}/{{$.Runs}} runs, {{.Fights}} fights
+{{end}}{{end}}`)
+
+// FormatData is what report.tmpl is rendered with. Besides the shared template
+// functions it can use num, date, time and datetime to format in the user's locale.
+type FormatData struct {
+	Report
+	TopDamage []PlayerTotals
+	TopDps    []PlayerTotals
+}
+
+// Format renders the report with report.tmpl.
+func Format(r Report, loc locale.Format) (string, error) {
+	r.Combat = r.Combat.Round(time.Second)
+	data := FormatData{
+		Report:    r,
+		TopDamage: r.TopBy(topPlayers, ByDamage),
+		TopDps:    r.TopBy(topPlayers, ByAvgDps),
+	}
+	return templates.Render(reportTemplate, data, template.FuncMap{
+		"num":      loc.Number,
+		"date":     loc.Date,
+		"time":     loc.Time,
+		"datetime": loc.DateTime,
+	})
+}
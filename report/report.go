@@ -0,0 +1,135 @@
+// Package report aggregates every run in a time window into one summary, e.g. the
+// guild's week in WvW.
+package report
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/processor"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report is the aggregate of all fights that started in a window.
+type Report struct {
+	Since, Until time.Time
+	Runs         int
+	Fights       int
+	Record       analysis.Record
+	Combat       time.Duration
+	Kills        int
+	Deaths       int
+	Players      []PlayerTotals // Attendance order, most runs first
+}
+
+// PlayerTotals are one account's totals over the window.
+type PlayerTotals struct {
+	analysis.PlayerTotals
+	Runs int // Runs the player was in at least one fight of
+}
+
+// ParseWindow reads a window length such as "7d", "2w" or "36h".
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+	if unit == 0 {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("invalid time window %q, use e.g. 7d, 2w or 12h", s)
+		}
+		return d, nil
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid time window %q, use e.g. 7d, 2w or 12h", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// Build reads every run in the archive with fights starting at or after since. Run
+// folders not written to since then are skipped without parsing, and fights are taken
+// from each run's index where it is current; unreadable fight files are left out.
+func Build(since time.Time, ratio float64, excludeOutnumberedLosses bool) (Report, error) {
+	r := Report{Since: since, Until: time.Now()}
+	entries, err := os.ReadDir(processor.LogArchive)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return r, nil
+		}
+		return r, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	var summaries []analysis.FightSummary
+	runs := make(map[string]int)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if info, err := e.Info(); err != nil || info.ModTime().Before(since) {
+			continue
+		}
+		fights, err := processor.RunSummaries(filepath.Join(processor.LogArchive, e.Name()))
+		if err != nil {
+			continue
+		}
+		var inWindow []analysis.FightSummary
+		for _, s := range fights {
+			if s.Start.Before(since) {
+				continue
+			}
+			inWindow = append(inWindow, s)
+			if s.End.After(s.Start) {
+				r.Combat += s.End.Sub(s.Start)
+			}
+			r.Kills += s.EnemyDeaths
+			r.Deaths += s.SquadDeaths
+		}
+		if len(inWindow) == 0 {
+			continue
+		}
+		r.Runs++
+		for _, t := range analysis.SummaryTotals(inWindow) {
+			runs[t.Account]++
+		}
+		summaries = append(summaries, inWindow...)
+	}
+
+	r.Fights = len(summaries)
+	r.Record = analysis.Tally(summaries, ratio, excludeOutnumberedLosses)
+	for _, t := range analysis.SummaryTotals(summaries) {
+		r.Players = append(r.Players, PlayerTotals{PlayerTotals: t, Runs: runs[t.Account]})
+	}
+	sort.SliceStable(r.Players, func(i, j int) bool {
+		if r.Players[i].Runs != r.Players[j].Runs {
+			return r.Players[i].Runs > r.Players[j].Runs
+		}
+		return r.Players[i].Fights > r.Players[j].Fights
+	})
+	return r, nil
+}
+
+// TopBy returns up to n players ranked by less, leaving Players untouched.
+func (r Report) TopBy(n int, less func(a, b PlayerTotals) bool) []PlayerTotals {
+	out := append([]PlayerTotals(nil), r.Players...)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// ByDamage ranks players by total damage for TopBy.
+func ByDamage(a, b PlayerTotals) bool { return a.Damage > b.Damage }
+
+// ByAvgDps ranks players by their mean DPS per fight for TopBy.
+func ByAvgDps(a, b PlayerTotals) bool { return a.AvgDps() > b.AvgDps() }
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gw2-cmd-watch/backend"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// keepAliveInterval is how often an idle event stream gets a comment line, so proxies
+// and phone browsers don't drop the connection.
+const keepAliveInterval = 30 * time.Second
+
+// subscriberBuffer is how many events a slow client may fall behind before events
+// are dropped for it.
+const subscriberBuffer = 16
+
+// Event is one message on the /events stream.
+type Event struct {
+	Type string `json:"type"` // "fight" or "pipeline"
+	Data any    `json:"data"`
+}
+
+// PublishFight announces a newly archived fight to every /events subscriber and
+// primes the summary cache with the already parsed log.
+func (s *Server) PublishFight(jsonPath string, log *parser.ParsedLog) {
+	run := filepath.Base(filepath.Dir(jsonPath))
+	summary := NewFightSummary(processor.DisplayName(jsonPath), run, log)
+	if info, err := os.Stat(jsonPath); err == nil {
+		s.mu.Lock()
+		s.cache[jsonPath] = cachedSummary{modTime: info.ModTime(), summary: summary}
+		s.mu.Unlock()
+	}
+	s.publish(Event{Type: "fight", Data: summary})
+}
+
+func (s *Server) publish(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- e:
+		default:
+			// Never block archiving on a stalled client
+		}
+	}
+}
+
+func (s *Server) subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+func (s *Server) unsubscribe(ch chan Event) {
+	s.mu.Lock()
+	delete(s.subscribers, ch)
+	s.mu.Unlock()
+}
+
+// handleEvents streams events as Server-Sent Events until the client disconnects.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httpError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Overlays and guild sites are usually served from another origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e := <-ch:
+			data, err := json.Marshal(e.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+// PublishPipeline shares a pipeline event with instances attached through /events.
+func (s *Server) PublishPipeline(e backend.Event) {
+	s.publish(Event{Type: backend.SSEType, Data: e})
+}
@@ -0,0 +1,293 @@
+// Package server is the optional LAN web server. It exposes the run archive as a small
+// JSON API and serves the archived Elite Insights HTML reports.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/metrics"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPort is used when http_port is not set.
+const DefaultPort = 8080
+
+// Server serves the archive. Parsed fight summaries are cached by path and file
+// modification time, since EI JSON files are large.
+type Server struct {
+	mux *http.ServeMux
+
+	mu          sync.Mutex
+	cache       map[string]cachedSummary
+	subscribers map[chan Event]struct{}
+
+	uploadTokens map[string]string
+	uploadQueue  *processor.Queue
+	uploadNotify func(uploader, name string)
+}
+
+type cachedSummary struct {
+	modTime time.Time
+	summary FightSummary
+}
+
+// Run is an archived run in API responses.
+type Run struct {
+	ID        string             `json:"id"`
+	Fights    int                `json:"fights"`
+	Commander string             `json:"commander,omitempty"`
+	Started   time.Time          `json:"started,omitzero"`
+	Closed    time.Time          `json:"closed,omitzero"` // Zero while live
+	Map       string             `json:"map,omitempty"`
+	Tags      []string           `json:"tags,omitempty"`
+	Notes     string             `json:"notes,omitempty"`
+	Links     []string           `json:"links,omitempty"`
+	Matchup   *processor.Matchup `json:"matchup,omitempty"`
+}
+
+// Fight is one archived fight in a run listing.
+type Fight struct {
+	ID         string `json:"id"`
+	Run        string `json:"run"`
+	SummaryURL string `json:"summary_url"`
+	ReportURL  string `json:"report_url,omitempty"`
+}
+
+// FightSummary is the squad and enemy totals of a fight.
+type FightSummary struct {
+	ID          string `json:"id"`
+	Run         string `json:"run"`
+	FightName   string `json:"fight_name"`
+	TimeStart   string `json:"time_start"`
+	Duration    string `json:"duration"`
+	SquadCount  int    `json:"squad_count"`
+	AllyCount   int    `json:"ally_count"` // Allies not in squad
+	EnemyCount  int    `json:"enemy_count"`
+	SquadDamage int    `json:"squad_damage"`
+	SquadDps    int    `json:"squad_dps"`
+	SquadDowns  int    `json:"squad_downs"`
+	SquadDeaths int    `json:"squad_deaths"`
+	EnemyDamage int    `json:"enemy_damage"`
+	EnemyDps    int    `json:"enemy_dps"`
+	EnemyDowns  int    `json:"enemy_downs"`
+	EnemyDeaths int    `json:"enemy_deaths"`
+	Outcome     string `json:"outcome"` // W, L or D
+}
+
+// New creates a server for the current archive directory.
+func New() *Server {
+	s := &Server{
+		mux:         http.NewServeMux(),
+		cache:       make(map[string]cachedSummary),
+		subscribers: make(map[chan Event]struct{}),
+	}
+	s.mux.HandleFunc("GET /runs", s.handleRuns)
+	s.mux.HandleFunc("GET /runs/{id}/fights", s.handleRunFights)
+	s.mux.HandleFunc("GET /fights/{id}/summary", s.handleFightSummary)
+	s.mux.HandleFunc("GET /fights/{id}/report", s.handleFightReport)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+	s.mux.HandleFunc("GET /metrics", handleMetrics)
+	return s
+}
+
+// Handler returns the server's routes.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves on the given port on every interface, so phones on the LAN
+// can connect. It only returns on error.
+func (s *Server) ListenAndServe(port int) error {
+	if port <= 0 {
+		port = DefaultPort
+	}
+	addr := fmt.Sprintf(":%d", port)
+	logger.Info("web server listening on %s", addr)
+	srv := &http.Server{Addr: addr, Handler: s.mux, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
+
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	entries, err := os.ReadDir(processor.LogArchive)
+	if err != nil && !os.IsNotExist(err) {
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+	runs := []Run{}
+	for _, e := range entries {
+		if e.IsDir() {
+			runPath := filepath.Join(processor.LogArchive, e.Name())
+			run := Run{ID: e.Name(), Fights: len(fightFiles(runPath))}
+			if meta, err := processor.LoadRunMeta(runPath); err == nil {
+				run.Commander, run.Started, run.Closed = meta.Commander, meta.Started, meta.Closed
+				run.Map, run.Tags, run.Notes, run.Links = meta.Map, meta.Tags, meta.Notes, meta.Links
+				run.Matchup = meta.Matchup
+			}
+			runs = append(runs, run)
+		}
+	}
+	// Newest first, like the TUI
+	sort.Slice(runs, func(i, j int) bool { return runs[i].ID > runs[j].ID })
+	writeJSON(w, runs)
+}
+
+func (s *Server) handleRunFights(w http.ResponseWriter, r *http.Request) {
+	run := r.PathValue("id")
+	if !validName(run) {
+		httpError(w, http.StatusBadRequest, fmt.Errorf("invalid run id"))
+		return
+	}
+	runPath := filepath.Join(processor.LogArchive, run)
+	if info, err := os.Stat(runPath); err != nil || !info.IsDir() {
+		httpError(w, http.StatusNotFound, fmt.Errorf("run %s not found", run))
+		return
+	}
+	fights := []Fight{}
+	for _, file := range fightFiles(runPath) {
+		id := processor.DisplayName(file)
+		f := Fight{ID: id, Run: run, SummaryURL: "/fights/" + id + "/summary"}
+		if _, err := os.Stat(htmlPath(file)); err == nil {
+			f.ReportURL = "/fights/" + id + "/report"
+		}
+		fights = append(fights, f)
+	}
+	writeJSON(w, fights)
+}
+
+func (s *Server) handleFightSummary(w http.ResponseWriter, r *http.Request) {
+	path, run, ok := findFight(r.PathValue("id"))
+	if !ok {
+		httpError(w, http.StatusNotFound, fmt.Errorf("fight not found"))
+		return
+	}
+	summary, err := s.summary(path, run)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, summary)
+}
+
+func (s *Server) handleFightReport(w http.ResponseWriter, r *http.Request) {
+	path, _, ok := findFight(r.PathValue("id"))
+	if !ok {
+		httpError(w, http.StatusNotFound, fmt.Errorf("fight not found"))
+		return
+	}
+	http.ServeFile(w, r, htmlPath(path))
+}
+
+// summary parses a fight, or returns the cached result if the file is unchanged.
+func (s *Server) summary(path, run string) (FightSummary, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FightSummary{}, err
+	}
+	s.mu.Lock()
+	c, ok := s.cache[path]
+	s.mu.Unlock()
+	if ok && c.modTime.Equal(info.ModTime()) {
+		return c.summary, nil
+	}
+
+	log, err := parser.ParseLog(path)
+	if err != nil {
+		return FightSummary{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	summary := NewFightSummary(processor.DisplayName(path), run, log)
+	s.mu.Lock()
+	s.cache[path] = cachedSummary{modTime: info.ModTime(), summary: summary}
+	s.mu.Unlock()
+	return summary, nil
+}
+
+// NewFightSummary builds the API view of a parsed fight.
+func NewFightSummary(id, run string, log *parser.ParsedLog) FightSummary {
+	fs := analysis.Summarize(log)
+	return FightSummary{
+		ID:          id,
+		Run:         run,
+		FightName:   log.FightName,
+		TimeStart:   log.TimeStart,
+		Duration:    log.Duration,
+		SquadCount:  fs.SquadCount,
+		AllyCount:   fs.NotInSquadCount,
+		EnemyCount:  fs.EnemyCount,
+		SquadDamage: fs.SquadDmg,
+		SquadDps:    fs.SquadDps,
+		SquadDowns:  fs.SquadDowns,
+		SquadDeaths: fs.SquadDeaths,
+		EnemyDamage: fs.EnemyDmg,
+		EnemyDps:    fs.EnemyDps,
+		EnemyDowns:  fs.EnemyDowns,
+		EnemyDeaths: fs.EnemyDeaths,
+		Outcome:     fs.Outcome().String(),
+	}
+}
+
+// fightFiles lists the EI JSON files of a run in name order.
+func fightFiles(runPath string) []string {
+	files, _ := processor.RunLogFiles(runPath)
+	return files
+}
+
+// findFight looks a fight up by its display name across all runs, newest run first.
+func findFight(id string) (path, run string, ok bool) {
+	if !validName(id) {
+		return "", "", false
+	}
+	entries, err := os.ReadDir(processor.LogArchive)
+	if err != nil {
+		return "", "", false
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].IsDir() {
+			continue
+		}
+		for _, file := range fightFiles(filepath.Join(processor.LogArchive, entries[i].Name())) {
+			if processor.DisplayName(file) == id {
+				return file, entries[i].Name(), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func htmlPath(jsonPath string) string {
+	return strings.TrimSuffix(jsonPath, ".json") + ".html"
+}
+
+// validName rejects IDs that could escape the archive directory.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := metrics.WriteText(w); err != nil {
+		logger.Warn("web server: writing metrics: %v", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warn("web server: writing response: %v", err)
+	}
+}
+
+func httpError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/processor"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultUploadDir is where logs received from squad members are kept, next to the app.
+const DefaultUploadDir = "Uploads"
+
+// MaxUploadBytes caps a single uploaded log. Long WvW logs are a few MB compressed.
+const MaxUploadBytes = 64 << 20
+
+// uploaderTag is inserted into the file name of uploaded logs, so the fight shows up
+// as "<log>_from-<uploader>" and two squad members' logs of the same fight don't clash.
+const uploaderTag = "_from-"
+
+// Uploader returns who uploaded a fight, from its display name, or "" for the app's
+// own logs.
+func Uploader(fightName string) string {
+	_, uploader, _ := strings.Cut(fightName, uploaderTag)
+	return uploader
+}
+
+// EnableUploads turns on POST /upload. tokens maps each uploader's name to their
+// secret; received logs go into queue. notify, if set, is called for each accepted log.
+func (s *Server) EnableUploads(tokens map[string]string, queue *processor.Queue, notify func(uploader, name string)) {
+	s.uploadTokens = tokens
+	s.uploadQueue = queue
+	s.uploadNotify = notify
+	s.mux.HandleFunc("POST /upload", s.handleUpload)
+}
+
+// handleUpload accepts an arcdps log as the "file" field of a multipart form, or as
+// the raw request body with ?name=<file name>. Callers authenticate with
+// "Authorization: Bearer <token>".
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	uploader, ok := s.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		httpError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid upload token"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, MaxUploadBytes)
+
+	var body io.Reader
+	name := r.URL.Query().Get("name")
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			httpError(w, http.StatusBadRequest, fmt.Errorf("reading upload: %w", err))
+			return
+		}
+		defer file.Close()
+		body, name = file, header.Filename
+	} else {
+		body = r.Body
+	}
+	name = filepath.Base(name)
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext != ".evtc" && ext != ".zevtc" {
+		httpError(w, http.StatusBadRequest, fmt.Errorf("expected an .evtc or .zevtc file"))
+		return
+	}
+
+	dest := filepath.Join(DefaultUploadDir, strings.TrimSuffix(name, filepath.Ext(name))+uploaderTag+safeName(uploader)+ext)
+	if err := saveUpload(dest, body); err != nil {
+		logger.Error("saving upload from %s: %v", uploader, err)
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !s.uploadQueue.TryPush(dest) {
+		os.Remove(dest)
+		httpError(w, http.StatusServiceUnavailable, fmt.Errorf("processing queue is full, try again later"))
+		return
+	}
+	logger.Info("received %s from %s", name, uploader)
+	if s.uploadNotify != nil {
+		s.uploadNotify(uploader, name)
+	}
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, map[string]string{"status": "queued", "file": filepath.Base(dest)})
+}
+
+// authenticate returns the uploader whose token matches the request.
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	for uploader, want := range s.uploadTokens {
+		if want != "" && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return uploader, true
+		}
+	}
+	return "", false
+}
+
+// saveUpload writes the upload next to its final name first, so a dropped connection
+// never leaves a truncated log to be parsed.
+func saveUpload(dest string, body io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create upload folder: %w", err)
+	}
+	part := dest + ".part"
+	f, err := os.Create(part)
+	if err != nil {
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(part)
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(part)
+		return fmt.Errorf("failed to save upload: %w", err)
+	}
+	return os.Rename(part, dest)
+}
+
+// safeName keeps an uploader name usable in a file name.
+func safeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		}
+		return '-'
+	}, name)
+}
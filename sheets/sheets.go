@@ -0,0 +1,215 @@
+// Package sheets appends run attendance and per-player stats to a Google Sheet using
+// a service account.
+package sheets
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/processor"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tabs rows are appended to. Both must exist in the spreadsheet.
+const (
+	AttendanceSheet = "Attendance"
+	StatsSheet      = "Stats"
+)
+
+const (
+	sheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+	sheetsAPI   = "https://sheets.googleapis.com/v4/spreadsheets/"
+)
+
+// credentials is the part of a service account key file we need.
+type credentials struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// Exporter appends rows for finished runs.
+type Exporter struct {
+	spreadsheetID string
+	creds         credentials
+	key           *rsa.PrivateKey
+	client        *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// New loads a service account key file. Share the spreadsheet with the account's
+// client_email so it can edit it.
+func New(credentialsPath, spreadsheetID string) (*Exporter, error) {
+	data, err := os.ReadFile(credentialsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google credentials: %w", err)
+	}
+	var c credentials
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse Google credentials: %w", err)
+	}
+	if c.ClientEmail == "" || c.PrivateKey == "" {
+		return nil, errors.New("Google credentials are not a service account key")
+	}
+	if c.TokenURI == "" {
+		c.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	block, _ := pem.Decode([]byte(c.PrivateKey))
+	if block == nil {
+		return nil, errors.New("Google credentials have no PEM private key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Google private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("Google private key is not RSA")
+	}
+	return &Exporter{
+		spreadsheetID: spreadsheetID,
+		creds:         c,
+		key:           key,
+		client:        &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// ExportRun appends one attendance row and one stats row per squad member of a run.
+func (e *Exporter) ExportRun(runPath string) error {
+	fights, err := processor.RunSummaries(runPath)
+	if err != nil {
+		return err
+	}
+	if len(fights) == 0 {
+		return nil
+	}
+
+	run := processor.RunLabel(filepath.Base(runPath))
+	date := ""
+	if !fights[0].Start.IsZero() {
+		date = fights[0].Start.Format("2006-01-02")
+	}
+	matchup := ""
+	if meta, err := processor.LoadRunMeta(runPath); err == nil && meta.Matchup != nil {
+		matchup = "vs " + strings.Join(meta.Matchup.Opponents(), " and ")
+	}
+	var attendance, stats [][]any
+	for _, t := range analysis.SummaryTotals(fights) {
+		attendance = append(attendance, []any{date, run, t.Account, t.Name, t.Profession, t.Fights, len(fights), matchup})
+		stats = append(stats, []any{date, run, t.Account, t.Name, t.Profession, t.Fights, t.Damage, t.AvgDps(), t.Downs, t.Deaths, matchup})
+	}
+	if err := e.appendRows(AttendanceSheet, attendance); err != nil {
+		return err
+	}
+	return e.appendRows(StatsSheet, stats)
+}
+
+func (e *Exporter) appendRows(sheet string, rows [][]any) error {
+	token, err := e.accessToken()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]any{"values": rows})
+	if err != nil {
+		return err
+	}
+	u := sheetsAPI + url.PathEscape(e.spreadsheetID) + "/values/" + url.PathEscape(sheet+"!A1") +
+		":append?valueInputOption=RAW&insertDataOption=INSERT_ROWS"
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to append to %s sheet: %w", sheet, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to append to %s sheet: %s", sheet, apiError(resp))
+	}
+	return nil
+}
+
+// accessToken exchanges a signed JWT for an OAuth token, reusing it until shortly
+// before it expires.
+func (e *Exporter) accessToken() (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" && time.Now().Before(e.tokenExpiry) {
+		return e.token, nil
+	}
+	assertion, err := e.signedJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	resp, err := e.client.PostForm(e.creds.TokenURI, form)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Google access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to get Google access token: %s", apiError(resp))
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("failed to read Google access token: %w", err)
+	}
+	e.token = tok.AccessToken
+	e.tokenExpiry = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - time.Minute)
+	return e.token, nil
+}
+
+// signedJWT builds the RS256 assertion for the service account token request.
+func (e *Exporter) signedJWT(now time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   e.creds.ClientEmail,
+		"scope": sheetsScope,
+		"aud":   e.creds.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(nil, e.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign Google token request: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+func apiError(resp *http.Response) string {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return strings.TrimSpace(resp.Status + " " + string(msg))
+}
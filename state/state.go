@@ -0,0 +1,116 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultPath is where the app keeps UI session state between launches.
+const DefaultPath = "state.json"
+
+// State is saved on quit and restored on the next launch.
+type State struct {
+	LastRun         string `json:"last_run,omitempty"`      // Run folder name, relative to the archive
+	SelectedLog     string `json:"selected_log,omitempty"`  // Display name of the selected fight
+	FocusedPanel    string `json:"focused_panel,omitempty"` // "left" or "right"
+	SelectedCard    int    `json:"selected_card,omitempty"` // Index into the card layout
+	RunSort         string `json:"run_sort,omitempty"`      // "commander", "fights" or "kills"; date by default
+	RunSortReversed bool   `json:"run_sort_reversed,omitempty"`
+	RunGroup        string `json:"run_group,omitempty"` // "week" or "commander"
+
+	// LastProcessed is the modification time up to which every arcDPS log has been
+	// processed. Logs newer than this are picked up on the next launch, unless they are
+	// in Processed.
+	LastProcessed time.Time `json:"last_processed,omitzero"`
+	// Processed holds logs newer than LastProcessed that are done already, by path, as
+	// older logs may still have been waiting when the app closed.
+	Processed map[string]time.Time `json:"processed,omitempty"`
+}
+
+// mu serializes Queued and RecordProcessed, which the pipeline calls concurrently.
+var mu sync.Mutex
+
+// pending holds the modification times of logs queued or being processed, by path.
+var pending = make(map[string]time.Time)
+
+// Queued notes a log waiting to be processed, so LastProcessed doesn't pass it until
+// it is done.
+func Queued(logPath string) {
+	info, err := os.Stat(logPath)
+	if err != nil {
+		return
+	}
+	mu.Lock()
+	pending[logPath] = info.ModTime()
+	mu.Unlock()
+}
+
+// Load reads the state file. A missing file is not an error and returns an empty State.
+func Load(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// RecordProcessed marks a log done and moves LastProcessed forward as far as no older
+// log is still pending. It is written after every log rather than on quit, so a crash
+// mid-raid loses nothing.
+func RecordProcessed(path, logPath string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	t, ok := pending[logPath]
+	delete(pending, logPath)
+	if !ok {
+		info, err := os.Stat(logPath)
+		if err != nil {
+			return err
+		}
+		t = info.ModTime()
+	}
+	s, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if !t.After(s.LastProcessed) {
+		return nil
+	}
+	if s.Processed == nil {
+		s.Processed = make(map[string]time.Time)
+	}
+	s.Processed[logPath] = t
+
+	var oldest time.Time
+	for _, queued := range pending {
+		if oldest.IsZero() || queued.Before(oldest) {
+			oldest = queued
+		}
+	}
+	for _, done := range s.Processed {
+		if (oldest.IsZero() || done.Before(oldest)) && done.After(s.LastProcessed) {
+			s.LastProcessed = done
+		}
+	}
+	for p, done := range s.Processed {
+		if !done.After(s.LastProcessed) {
+			delete(s.Processed, p)
+		}
+	}
+	return Save(path, &s)
+}
+
+func Save(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,128 @@
+// Package summary writes a small JSON file next to each archived fight with the
+// totals the app works out from it, so scripts and bots can read a fight without
+// parsing Elite Insights' much larger JSON. The file layout is a stable schema: fields
+// are only ever added, and Version changes if one is removed or changes meaning.
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Version is the schema version written to every file.
+const Version = 1
+
+// topPlayers is how many squad members each top list holds.
+const topPlayers = 10
+
+// Fight is the contents of a summary file.
+type Fight struct {
+	Version   int       `json:"version"`
+	Fight     string    `json:"fight"`               // Elite Insights' fight name
+	Map       string    `json:"map,omitempty"`       // Short map name, e.g. "EBG"
+	Start     time.Time `json:"start,omitzero"`      // Missing if the log has no timestamps
+	Seconds   int       `json:"seconds"`             // Fight length
+	Outcome   string    `json:"outcome"`             // W, L or D, by kills against squad deaths
+	Commander string    `json:"commander,omitempty"` // Account of the tagged squad member
+	Squad     Side      `json:"squad"`
+	Allies    int       `json:"allies"` // Allied players outside the squad
+	Enemy     Side      `json:"enemy"`
+	Top       Top       `json:"top"`
+}
+
+// Side is one side's totals. For the enemy, downs and deaths are those dealt by the
+// squad.
+type Side struct {
+	Players int `json:"players"`
+	Damage  int `json:"damage"`
+	Dps     int `json:"dps"`
+	Downs   int `json:"downs"`
+	Deaths  int `json:"deaths"`
+}
+
+// Top ranks the squad by a few headline stats, best first.
+type Top struct {
+	Damage   []Entry `json:"damage"`
+	Cleanses []Entry `json:"cleanses"`
+	Strips   []Entry `json:"strips"`
+}
+
+// Entry is one player in a top list.
+type Entry struct {
+	Name       string `json:"name"`
+	Account    string `json:"account"`
+	Profession string `json:"profession"`
+	Value      int    `json:"value"`
+}
+
+// New works out the summary of a fight.
+func New(log *parser.ParsedLog) Fight {
+	s := analysis.Summarize(log)
+	f := Fight{
+		Version:   Version,
+		Fight:     log.FightName,
+		Map:       s.Map,
+		Start:     s.Start,
+		Seconds:   analysis.FightSeconds(log),
+		Outcome:   s.Outcome().String(),
+		Commander: s.Commander,
+		Squad:     Side{Players: s.SquadCount, Damage: s.SquadDmg, Dps: s.SquadDps, Downs: s.SquadDowns, Deaths: s.SquadDeaths},
+		Allies:    s.NotInSquadCount,
+		Enemy:     Side{Players: s.EnemyCount, Damage: s.EnemyDmg, Dps: s.EnemyDps, Downs: s.EnemyDowns, Deaths: s.EnemyDeaths},
+		Top:       Top{Damage: []Entry{}, Cleanses: []Entry{}, Strips: []Entry{}},
+	}
+	for _, p := range analysis.SquadDamage(log) {
+		f.Top.Damage = append(f.Top.Damage, Entry{Name: p.Name, Account: p.Account, Profession: p.Profession, Value: p.Damage})
+	}
+	for _, p := range analysis.SquadPlayers(log) {
+		if len(p.Support) == 0 {
+			continue
+		}
+		e := Entry{Name: p.Name, Account: p.Account, Profession: p.Profession}
+		e.Value = p.Support[0].CondiCleanse + p.Support[0].CondiCleanseSelf
+		f.Top.Cleanses = append(f.Top.Cleanses, e)
+		e.Value = p.Support[0].BoonStrips
+		f.Top.Strips = append(f.Top.Strips, e)
+	}
+	f.Top.Damage = top(f.Top.Damage)
+	f.Top.Cleanses = top(f.Top.Cleanses)
+	f.Top.Strips = top(f.Top.Strips)
+	return f
+}
+
+// top sorts a list best first and keeps the first topPlayers with a value.
+func top(entries []Entry) []Entry {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Value > entries[j].Value })
+	n := 0
+	for n < len(entries) && n < topPlayers && entries[n].Value > 0 {
+		n++
+	}
+	return entries[:n]
+}
+
+// Write saves the summary of the fight at jsonPath next to it, see
+// processor.SummaryPath.
+func Write(jsonPath string, log *parser.ParsedLog) error {
+	data, err := json.MarshalIndent(New(log), "", "  ")
+	if err != nil {
+		return err
+	}
+	// Written to a temp file first, so readers never see half a summary
+	path := processor.SummaryPath(jsonPath)
+	tmp := path + ".part"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary for %s: %w", filepath.Base(jsonPath), err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write summary for %s: %w", filepath.Base(jsonPath), err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package templates lets users replace the built-in text of the app's exports and
+// messages (Discord reports, the command-line report, the OBS overlay and the
+// positions CSV) with Go text/template files of their own, so a guild can change
+// their tone and content without changing the code.
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultDir is where template files are looked for when templates_dir is not set.
+const DefaultDir = "templates"
+
+var registry struct {
+	sync.Mutex
+	dir      string
+	defaults map[string]string // File name -> built-in template
+}
+
+// Register adds a built-in template under a file name, e.g. "discord_run.tmpl", and
+// returns the name. A file of that name in the template folder replaces it.
+func Register(name, src string) string {
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.defaults == nil {
+		registry.defaults = make(map[string]string)
+	}
+	registry.defaults[name] = src
+	return name
+}
+
+// SetDir sets the folder template files are read from. Empty uses DefaultDir.
+func SetDir(dir string) {
+	registry.Lock()
+	registry.dir = dir
+	registry.Unlock()
+}
+
+// Source returns the template for name: the user's file if there is one, otherwise
+// the built-in template. Files are read on every call, so edits apply to the next
+// report without a restart.
+func Source(name string) (string, error) {
+	registry.Lock()
+	dir := registry.dir
+	src, ok := registry.defaults[name]
+	registry.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown template %q", name)
+	}
+	if dir == "" {
+		dir = DefaultDir
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return src, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// Parse reads and parses a text template with Funcs and the extra functions.
+func Parse(name string, extra template.FuncMap) (*template.Template, error) {
+	src, err := Source(name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(name).Funcs(Funcs).Funcs(extra).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// Render executes the template for name with data.
+func Render(name string, data any, extra template.FuncMap) (string, error) {
+	t, err := Parse(name, extra)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+// WriteDefaults writes the built-in templates to dir as a starting point for custom
+// ones. Files that already exist are kept.
+func WriteDefaults(dir string) ([]string, error) {
+	registry.Lock()
+	names := make([]string, 0, len(registry.defaults))
+	for name := range registry.defaults {
+		names = append(names, name)
+	}
+	defaults := registry.defaults
+	registry.Unlock()
+	sort.Strings(names)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(defaults[name]), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// Funcs are available in every template:
+//
+//	inc      1-based position from a range index: {{inc $i}}
+//	pad      left-align to a width: {{pad 20 .Name}}
+//	lpad     right-align to a width: {{lpad 9 .Damage}}
+//	join     join strings: {{join .Drivers " → "}}
+//	csv      quote a CSV field when it needs it: {{csv .Name}}
+var Funcs = template.FuncMap{
+	"inc":  func(i int) int { return i + 1 },
+	"pad":  func(width int, v any) string { return fmt.Sprintf("%-*v", width, v) },
+	"lpad": func(width int, v any) string { return fmt.Sprintf("%*v", width, v) },
+	"join": strings.Join,
+	"csv":  csvField,
+}
+
+// csvField quotes a field when encoding/csv would.
+func csvField(v any) string {
+	s := fmt.Sprint(v)
+	if s == "" {
+		return s
+	}
+	first, _ := utf8.DecodeRuneInString(s)
+	if s != `\.` && !strings.ContainsAny(s, ",\"\r\n") && !unicode.IsSpace(first) {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,28 @@
+// Package tray shows a notification area icon with the app's status and a small menu,
+// for when the console window is minimized or hidden.
+package tray
+
+import "errors"
+
+// ErrUnsupported is returned on platforms without a notification area icon.
+var ErrUnsupported = errors.New("the tray icon is only available on Windows")
+
+// Menu holds the actions behind the tray menu. Show brings the console window to the
+// front and is handled by the tray itself.
+type Menu struct {
+	Paused      func() bool // Whether watching is paused, for the menu label
+	SetPaused   func(bool)
+	OpenArchive func()
+}
+
+// maxTip is the longest tooltip Windows shows, in UTF-16 units, without the NUL.
+const maxTip = 127
+
+// truncateTip shortens a tooltip to what fits.
+func truncateTip(s string) string {
+	r := []rune(s)
+	if len(r) > maxTip {
+		r = append(r[:maxTip-1], '…')
+	}
+	return string(r)
+}
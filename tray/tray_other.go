@@ -0,0 +1,12 @@
+//go:build !windows
+
+package tray
+
+// Run is not supported outside Windows.
+func Run(menu Menu) error { return ErrUnsupported }
+
+// SetStatus does nothing outside Windows.
+func SetStatus(status string) {}
+
+// Close does nothing outside Windows.
+func Close() {}
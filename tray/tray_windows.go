@@ -0,0 +1,296 @@
+//go:build windows
+
+package tray
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	shell32  = syscall.NewLazyDLL("shell32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procRegisterClassExW    = user32.NewProc("RegisterClassExW")
+	procCreateWindowExW     = user32.NewProc("CreateWindowExW")
+	procDefWindowProcW      = user32.NewProc("DefWindowProcW")
+	procGetMessageW         = user32.NewProc("GetMessageW")
+	procTranslateMessage    = user32.NewProc("TranslateMessage")
+	procDispatchMessageW    = user32.NewProc("DispatchMessageW")
+	procPostMessageW        = user32.NewProc("PostMessageW")
+	procPostQuitMessage     = user32.NewProc("PostQuitMessage")
+	procLoadIconW           = user32.NewProc("LoadIconW")
+	procCreatePopupMenu     = user32.NewProc("CreatePopupMenu")
+	procAppendMenuW         = user32.NewProc("AppendMenuW")
+	procTrackPopupMenu      = user32.NewProc("TrackPopupMenu")
+	procDestroyMenu         = user32.NewProc("DestroyMenu")
+	procGetCursorPos        = user32.NewProc("GetCursorPos")
+	procSetForegroundWindow = user32.NewProc("SetForegroundWindow")
+	procShowWindow          = user32.NewProc("ShowWindow")
+	procShellNotifyIconW    = shell32.NewProc("Shell_NotifyIconW")
+	procGetModuleHandleW    = kernel32.NewProc("GetModuleHandleW")
+	procGetConsoleWindow    = kernel32.NewProc("GetConsoleWindow")
+)
+
+const (
+	wmNull          = 0x0000
+	wmClose         = 0x0010
+	wmLButtonDblClk = 0x0203
+	wmRButtonUp     = 0x0205
+	wmTray          = 0x8000 + 1 // WM_APP + 1, the icon's callback message
+
+	nimAdd    = 0
+	nimModify = 1
+	nimDelete = 2
+
+	nifMessage = 0x1
+	nifIcon    = 0x2
+	nifTip     = 0x4
+
+	mfString    = 0x0
+	mfSeparator = 0x800
+
+	tpmReturnCmd = 0x100
+	tpmNoNotify  = 0x80
+
+	idiApplication = 32512
+	swRestore      = 9
+)
+
+// Menu item IDs.
+const (
+	cmdShow = iota + 1
+	cmdPause
+	cmdArchive
+)
+
+type wndClassEx struct {
+	Size       uint32
+	Style      uint32
+	WndProc    uintptr
+	ClsExtra   int32
+	WndExtra   int32
+	Instance   syscall.Handle
+	Icon       syscall.Handle
+	Cursor     syscall.Handle
+	Background syscall.Handle
+	MenuName   *uint16
+	ClassName  *uint16
+	IconSm     syscall.Handle
+}
+
+type notifyIconData struct {
+	Size            uint32
+	Wnd             uintptr
+	ID              uint32
+	Flags           uint32
+	CallbackMessage uint32
+	Icon            syscall.Handle
+	Tip             [128]uint16
+	State           uint32
+	StateMask       uint32
+	Info            [256]uint16
+	Version         uint32
+	InfoTitle       [64]uint16
+	InfoFlags       uint32
+	GUIDItem        [16]byte
+	BalloonIcon     syscall.Handle
+}
+
+type point struct{ X, Y int32 }
+
+type msg struct {
+	Wnd     uintptr
+	Message uint32
+	WParam  uintptr
+	LParam  uintptr
+	Time    uint32
+	Pt      point
+}
+
+// icon is the tray icon once Run has added it.
+var icon struct {
+	sync.Mutex
+	data  notifyIconData
+	added bool
+	menu  Menu
+}
+
+// Run adds the tray icon and handles its menu. It returns once the icon is showing;
+// the window behind it lives on its own locked thread.
+func Run(menu Menu) error {
+	errc := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		hwnd, err := createWindow()
+		if err != nil {
+			errc <- err
+			return
+		}
+		hIcon, _, _ := procLoadIconW.Call(0, idiApplication)
+
+		icon.Lock()
+		icon.menu = menu
+		icon.data = notifyIconData{
+			Wnd:             hwnd,
+			ID:              1,
+			Flags:           nifMessage | nifIcon | nifTip,
+			CallbackMessage: wmTray,
+			Icon:            syscall.Handle(hIcon),
+		}
+		icon.data.Size = uint32(unsafe.Sizeof(icon.data))
+		setTip(&icon.data, "GW2 Commanders Watch")
+		ok, _, err := procShellNotifyIconW.Call(nimAdd, uintptr(unsafe.Pointer(&icon.data)))
+		icon.added = ok != 0
+		icon.Unlock()
+		if ok == 0 {
+			errc <- fmt.Errorf("failed to add tray icon: %w", err)
+			return
+		}
+		errc <- nil
+
+		var m msg
+		for {
+			r, _, _ := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			if int32(r) <= 0 {
+				return
+			}
+			procTranslateMessage.Call(uintptr(unsafe.Pointer(&m)))
+			procDispatchMessageW.Call(uintptr(unsafe.Pointer(&m)))
+		}
+	}()
+	return <-errc
+}
+
+// SetStatus replaces the tooltip shown when hovering the icon.
+func SetStatus(status string) {
+	icon.Lock()
+	defer icon.Unlock()
+	if !icon.added {
+		return
+	}
+	setTip(&icon.data, status)
+	procShellNotifyIconW.Call(nimModify, uintptr(unsafe.Pointer(&icon.data)))
+}
+
+// Close removes the icon, which Windows otherwise leaves behind until hovered.
+func Close() {
+	icon.Lock()
+	defer icon.Unlock()
+	if !icon.added {
+		return
+	}
+	procShellNotifyIconW.Call(nimDelete, uintptr(unsafe.Pointer(&icon.data)))
+	icon.added = false
+	procPostMessageW.Call(icon.data.Wnd, wmClose, 0, 0)
+}
+
+func setTip(data *notifyIconData, text string) {
+	tip, err := syscall.UTF16FromString(truncateTip(text))
+	if err != nil {
+		return
+	}
+	data.Tip = [128]uint16{}
+	copy(data.Tip[:len(data.Tip)-1], tip)
+}
+
+func createWindow() (uintptr, error) {
+	className, _ := syscall.UTF16PtrFromString("GW2CommandersWatchTray")
+	instance, _, _ := procGetModuleHandleW.Call(0)
+	wc := wndClassEx{
+		WndProc:   syscall.NewCallback(wndProc),
+		Instance:  syscall.Handle(instance),
+		ClassName: className,
+	}
+	wc.Size = uint32(unsafe.Sizeof(wc))
+	if r, _, err := procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc))); r == 0 {
+		return 0, fmt.Errorf("failed to register tray window: %w", err)
+	}
+	// A hidden top-level window, not a message-only one, so the menu closes when
+	// clicking elsewhere.
+	hwnd, _, err := procCreateWindowExW.Call(0, uintptr(unsafe.Pointer(className)), uintptr(unsafe.Pointer(className)),
+		0, 0, 0, 0, 0, 0, 0, instance, 0)
+	if hwnd == 0 {
+		return 0, fmt.Errorf("failed to create tray window: %w", err)
+	}
+	return hwnd, nil
+}
+
+func wndProc(hwnd, message, wParam, lParam uintptr) uintptr {
+	switch message {
+	case wmTray:
+		switch lParam & 0xffff {
+		case wmLButtonDblClk:
+			showConsole()
+		case wmRButtonUp:
+			showMenu(hwnd)
+		}
+		return 0
+	case wmClose:
+		// Ends the GetMessage loop
+		procPostQuitMessage.Call(0)
+		return 0
+	}
+	r, _, _ := procDefWindowProcW.Call(hwnd, message, wParam, lParam)
+	return r
+}
+
+func showMenu(hwnd uintptr) {
+	icon.Lock()
+	menu := icon.menu
+	icon.Unlock()
+
+	h, _, _ := procCreatePopupMenu.Call()
+	if h == 0 {
+		return
+	}
+	defer procDestroyMenu.Call(h)
+	paused := menu.Paused != nil && menu.Paused()
+	pauseLabel := "Pause watching"
+	if paused {
+		pauseLabel = "Resume watching"
+	}
+	appendItem(h, cmdShow, "Show window")
+	if menu.SetPaused != nil {
+		appendItem(h, cmdPause, pauseLabel)
+	}
+	procAppendMenuW.Call(h, mfSeparator, 0, 0)
+	if menu.OpenArchive != nil {
+		appendItem(h, cmdArchive, "Open archive")
+	}
+
+	var pt point
+	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	// Without this the menu does not close when clicking elsewhere
+	procSetForegroundWindow.Call(hwnd)
+	cmd, _, _ := procTrackPopupMenu.Call(h, tpmReturnCmd|tpmNoNotify, uintptr(pt.X), uintptr(pt.Y), 0, hwnd, 0)
+	procPostMessageW.Call(hwnd, wmNull, 0, 0)
+
+	switch cmd {
+	case cmdShow:
+		showConsole()
+	case cmdPause:
+		menu.SetPaused(!paused)
+	case cmdArchive:
+		go menu.OpenArchive()
+	}
+}
+
+func appendItem(menu uintptr, id int, label string) {
+	text, _ := syscall.UTF16PtrFromString(label)
+	procAppendMenuW.Call(menu, mfString, uintptr(id), uintptr(unsafe.Pointer(text)))
+}
+
+// showConsole restores the console window running the TUI and brings it to the front.
+func showConsole() {
+	hwnd, _, _ := procGetConsoleWindow.Call()
+	if hwnd == 0 {
+		return
+	}
+	procShowWindow.Call(hwnd, swRestore)
+	procSetForegroundWindow.Call(hwnd)
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/config"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleAnonymize switches player pseudonyms on or off, saves the setting and reloads
+// the current run so every card picks it up. EI's reports are left alone.
+func (m *model) toggleAnonymize() tea.Cmd {
+	m.config.Anonymize = !m.config.Anonymize
+	parser.SetAnonymize(m.config.Anonymize, m.config.AnonymizeSeed)
+	if m.config.Anonymize {
+		m.setStatus(i18n.T("status.anonymize_on"))
+	} else {
+		m.setStatus(i18n.T("status.anonymize_off"))
+	}
+
+	cfg := m.config
+	save := func() tea.Msg {
+		if err := config.SaveConfig(config.DefaultPath, &cfg); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save anonymize setting: %w", err)}
+		}
+		return nil
+	}
+	return tea.Batch(save, m.reloadRun())
+}
+
+// toggleNameDisplay goes from character names to account names to both, saves the
+// setting and reloads the current run.
+func (m *model) toggleNameDisplay() tea.Cmd {
+	switch parser.NameDisplay() {
+	case parser.NamesCharacter:
+		m.config.NameDisplay = parser.NamesAccount
+	case parser.NamesAccount:
+		m.config.NameDisplay = parser.NamesBoth
+	default:
+		m.config.NameDisplay = parser.NamesCharacter
+	}
+	parser.SetNameDisplay(m.config.NameDisplay)
+	m.setStatus(i18n.T("status.names_" + m.config.NameDisplay))
+
+	cfg := m.config
+	save := func() tea.Msg {
+		if err := config.SaveConfig(config.DefaultPath, &cfg); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save name display setting: %w", err)}
+		}
+		return nil
+	}
+	return tea.Batch(save, m.reloadRun())
+}
+
+// reloadRun parses the viewed run's fights again, keeping the selection, so a change
+// to how players are named reaches every card.
+func (m *model) reloadRun() tea.Cmd {
+	if m.viewMode != logsView || len(m.logList) == 0 {
+		return nil
+	}
+	if m.selectedIndex > 0 && m.selectedIndex <= len(m.logList) {
+		m.pendingSelectLog = m.logList[m.selectedIndex-1]
+	}
+	m.logs = make(map[string]*parser.ParsedLog)
+	m.logOrder = nil
+	m.loadingLogs = make(map[string]bool)
+	m.parsing = make(map[string]bool)
+	m.brokenLogs = make(map[string]error)
+	m.parseQueue = nil
+	m.logList = []string{}
+	m.povs = nil
+	m.logFullPaths = make(map[string]string)
+	m.summaries = make(map[string]analysis.FightSummary)
+	return loadLogsInRun(m.currentRunPath)
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/processor"
+	"gw2-cmd-watch/watcher"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// archiveChangedMsg reports run folders, or the fights in one, changed outside the app.
+type archiveChangedMsg struct{ Path string }
+
+// runReconciledMsg carries the fights now in the run being viewed.
+type runReconciledMsg runFilesMsg
+
+// archiveRewatchDelay is how long WatchArchive waits before watching the archive again
+// after it stopped, e.g. because the archive folder was removed.
+const archiveRewatchDelay = 30 * time.Second
+
+// WatchArchive refreshes the run and log lists when the archive is changed outside the
+// app, e.g. in a file explorer. When the archive can no longer be watched it says so
+// and tries again, recreating the archive folder if it was removed.
+func WatchArchive(p *tea.Program) {
+	changed := make(chan string)
+	go func() {
+		for path := range changed {
+			p.Send(archiveChangedMsg{Path: path})
+		}
+	}()
+	for retry := false; ; retry = true {
+		started := time.Now()
+		err := os.MkdirAll(processor.LogArchive, 0755)
+		if err == nil {
+			err = watcher.WatchArchive(processor.LogArchive, processor.IsFightFile, changed)
+		}
+		logger.Warn("not watching the archive for changes, trying again in %s: %v", archiveRewatchDelay, err)
+		// A retry failing again right away is only logged
+		if !retry || time.Since(started) >= archiveRewatchDelay {
+			p.Send(ErrMsg{Err: fmt.Errorf("not watching the archive for changes: %w", err)})
+		}
+		time.Sleep(archiveRewatchDelay)
+		// Whatever happened to the archive in the meantime went unseen
+		changed <- processor.LogArchive
+	}
+}
+
+// handleArchiveChanged reloads the run list, or the fights of the run being viewed. A
+// run deleted while it is viewed is left for the run list.
+func (m *model) handleArchiveChanged(msg archiveChangedMsg) tea.Cmd {
+	if m.viewMode == runsView {
+		return loadRuns
+	}
+	if _, err := os.Stat(m.currentRunPath); os.IsNotExist(err) {
+		m.viewMode = runsView
+		m.currentRunPath = ""
+		m.currentRunName = "Viewing Run Archives"
+		m.clearCurrentRun()
+		m.selectedIndex = 0
+		m.setStatus(i18n.T("status.run_removed"))
+		return loadRuns
+	}
+	if filepath.Clean(msg.Path) != filepath.Clean(m.currentRunPath) {
+		return nil
+	}
+	runPath := m.currentRunPath
+	return func() tea.Msg {
+		files, _, err := readRunFiles(runPath)
+		if err != nil {
+			return ErrMsg{Err: err}
+		}
+		return runReconciledMsg(files)
+	}
+}
+
+// handleRunReconciled drops fights that are gone from the run being viewed and lists
+// new ones, parsing those without a cached summary in the background.
+func (m *model) handleRunReconciled(msg runReconciledMsg) tea.Cmd {
+	if msg.RunPath != m.currentRunPath {
+		return nil
+	}
+	present := make(map[string]bool, len(msg.Cached)+len(msg.Parsing))
+	for path := range msg.Cached {
+		present[path] = true
+	}
+	for _, path := range msg.Parsing {
+		present[path] = true
+	}
+	removed, added := 0, 0
+	kept := m.logList[:0]
+	for _, name := range m.logList {
+		path := m.logFullPaths[name]
+		if present[path] {
+			kept = append(kept, name)
+			continue
+		}
+		removed++
+		delete(m.logFullPaths, name)
+		delete(m.summaries, path)
+		delete(m.logs, path)
+		delete(m.brokenLogs, path)
+		m.removeQueued(path)
+	}
+	m.logList = kept
+	listed := func(path string) bool {
+		_, ok := m.logFullPaths[processor.DisplayName(path)]
+		return ok
+	}
+	// Fights the app is archiving itself are listed once they are in place
+	ownWrite := func(path string) bool {
+		_, ok := m.arriving[processor.DisplayName(path)]
+		return ok
+	}
+	for path, s := range msg.Cached {
+		if listed(path) || ownWrite(path) {
+			continue
+		}
+		processor.DisplaySummary(&s)
+		m.summaries[path] = s
+		m.logFullPaths[processor.DisplayName(path)] = path
+		m.logList = append(m.logList, processor.DisplayName(path))
+		added++
+	}
+	for _, path := range msg.Parsing {
+		if listed(path) || ownWrite(path) {
+			continue
+		}
+		m.logFullPaths[processor.DisplayName(path)] = path
+		m.logList = append(m.logList, processor.DisplayName(path))
+		m.parseQueue = append(m.parseQueue, path)
+		added++
+	}
+	if removed == 0 && added == 0 {
+		return nil
+	}
+	m.sortLogList()
+	m.selectedIndex = min(m.selectedIndex, len(m.logList))
+	m.ensureSelectionVisible()
+	m.setStatus(i18n.T("status.run_changed", added, removed))
+	if len(m.parseQueue) > 0 {
+		m.runLoading = true
+	}
+	return tea.Batch(m.loadSelectedLog(), m.nextParses())
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"errors"
+	"gw2-cmd-watch/apperr"
+	"gw2-cmd-watch/backend"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// remoteArchivedMsg is a fight archived by the instance this TUI is attached to.
+type remoteArchivedMsg struct{ FullPath string }
+
+// Attach feeds a backend's events into the program until its stream is closed.
+func Attach(p *tea.Program, b backend.Backend) {
+	for e := range b.Events() {
+		if msg := eventMsg(e); msg != nil {
+			p.Send(msg)
+		}
+	}
+}
+
+func eventMsg(e backend.Event) tea.Msg {
+	switch e.Kind {
+	case backend.KindStatus:
+		return StatusMsg(e.Text)
+	case backend.KindToast:
+		return ToastMsg(e.Text)
+	case backend.KindError:
+		err := errors.New(e.Text)
+		if e.ErrKind != "" {
+			err = apperr.New(apperr.Kind(e.ErrKind), err)
+		}
+		return ErrMsg{Err: err}
+	case backend.KindProcessed:
+		return TempLogProcessedMsg{TempPath: e.Path, LogPath: e.Text}
+	case backend.KindArchived:
+		return remoteArchivedMsg{FullPath: filepath.Join(processor.LogArchive, e.Path)}
+	case backend.KindMap:
+		return MapStateMsg{Online: e.Online, Map: e.Map, InWvW: e.InWvW}
+	case backend.KindUpdate:
+		return UpdateAvailableMsg{URL: e.Text}
+	case backend.KindParsing, backend.KindParsed:
+		return parseProgressMsg{ID: e.ParseID, Label: e.Text, Line: e.Line, Done: e.Kind == backend.KindParsed}
+	}
+	return nil
+}
+
+// handleRemoteArchived shows a fight the attached instance archived: it is added like
+// a local one if its run is open, otherwise the run list is refreshed.
+func (m *model) handleRemoteArchived(msg remoteArchivedMsg) tea.Cmd {
+	runPath := filepath.Dir(msg.FullPath)
+	if m.viewMode == runsView {
+		return loadRuns
+	}
+	if runPath != m.currentRunPath {
+		m.setStatus(i18n.T("status.remote_fight", processor.RunLabel(filepath.Base(runPath))))
+		return nil
+	}
+	return func() tea.Msg {
+		log, err := parser.ParseLog(msg.FullPath)
+		if err != nil {
+			return ErrMsg{Err: err}
+		}
+		return LogfileArchivedMsg{Log: log, FullPath: msg.FullPath}
+	}
+}
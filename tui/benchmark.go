@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/config"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// saveBenchmark stores the current run's per-fight averages as the squad benchmark.
+func (m *model) saveBenchmark() tea.Cmd {
+	if m.viewMode != logsView {
+		return nil
+	}
+	fights := m.runSummaries()
+	if len(fights) == 0 {
+		m.setStatus(i18n.T("status.benchmark_empty"))
+		return nil
+	}
+	b := &config.Benchmark{Source: m.currentRunName}
+	for _, s := range fights {
+		b.SquadDmg += float64(s.SquadDmg)
+		b.SquadDps += float64(s.SquadDps)
+		b.SquadDowns += float64(s.SquadDowns)
+		b.SquadDeaths += float64(s.SquadDeaths)
+		b.EnemyDowns += float64(s.EnemyDowns)
+		b.EnemyDeaths += float64(s.EnemyDeaths)
+	}
+	n := float64(len(fights))
+	for _, v := range []*float64{&b.SquadDmg, &b.SquadDps, &b.SquadDowns, &b.SquadDeaths, &b.EnemyDowns, &b.EnemyDeaths} {
+		*v = math.Round(*v/n*10) / 10
+	}
+	m.config.Benchmark = b
+	m.setStatus(i18n.T("status.benchmark_saved", processor.RunLabel(m.currentRunName), len(fights)))
+
+	// Only the benchmark changes on disk, keeping edits made to config.json since startup
+	return func() tea.Msg {
+		cfg, err := config.LoadConfig(config.DefaultPath)
+		if err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save benchmark: %w", err)}
+		}
+		cfg.Benchmark = b
+		if err := config.SaveConfig(config.DefaultPath, &cfg); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save benchmark: %w", err)}
+		}
+		return nil
+	}
+}
+
+// renderBenchmark compares a fight's squad totals with the benchmark, as percentages
+// colored by whether they are better or worse. It is empty without a benchmark.
+func (m *model) renderBenchmark(s analysis.FightSummary) string {
+	b := m.config.Benchmark
+	if b == nil {
+		return ""
+	}
+	var parts []string
+	add := func(label string, value int, base float64, lowerIsBetter bool) {
+		if base <= 0 {
+			return
+		}
+		pct := int(math.Round(float64(value) / base * 100))
+		text := fmt.Sprintf("%s %d%%", label, pct)
+		better, worse := pct > 100, pct < 100
+		if lowerIsBetter {
+			better, worse = worse, better
+		}
+		switch {
+		case better:
+			text = lipgloss.NewStyle().Foreground(m.colors.Good).Render(text)
+		case worse:
+			text = lipgloss.NewStyle().Foreground(m.colors.Bad).Render(text)
+		}
+		parts = append(parts, text)
+	}
+	add(i18n.T("col.dmg"), s.SquadDmg, b.SquadDmg, false)
+	add(i18n.T("col.dps"), s.SquadDps, b.SquadDps, false)
+	add(i18n.T("bench.enemy_downs"), s.EnemyDowns, b.EnemyDowns, false)
+	add(i18n.T("bench.kills"), s.EnemyDeaths, b.EnemyDeaths, false)
+	add(i18n.T("bench.downed"), s.SquadDowns, b.SquadDowns, true)
+	add(i18n.T("col.deaths"), s.SquadDeaths, b.SquadDeaths, true)
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%-5s %s", i18n.T("row.benchmark"), strings.Join(parts, "  "))
+}
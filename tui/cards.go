@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"sort"
+	"strings"
+	"time"
+)
+
+// formatFightTime renders milliseconds from fight start as H:M:S like the death card.
+func formatFightTime(ms int) string {
+	d := time.Duration(ms) * time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// ansiReset ends every style lipgloss renders.
+const ansiReset = "\x1b[0m"
+
+// writeRow appends a card row, striping every other one. Colored cells end in a
+// reset that would clear the stripe for the rest of the row, so a striped row opens
+// the stripe again after each of them.
+func (m *model) writeRow(sb *strings.Builder, i int, rowStr string) {
+	if i%2 == 0 {
+		sb.WriteString(rowStr + "\n")
+		return
+	}
+	if stripe, _, ok := strings.Cut(m.styles.StripedRow.Render(" "), " "); ok && stripe != "" {
+		rowStr = strings.ReplaceAll(rowStr, ansiReset, ansiReset+stripe)
+	}
+	sb.WriteString(m.styles.StripedRow.Render(rowStr) + "\n")
+}
+
+func (m *model) buildWipeCard(log *parser.ParsedLog) string {
+	report := analysis.AnalyzeWipes(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-11s %-7s %-6s %-4s %s", i18n.T("card.wipe"), i18n.T("col.deaths"), i18n.T("col.spike"), i18n.T("col.cc"), i18n.T("col.stab"))) + "\n")
+	if len(report.Clusters) == 0 {
+		sb.WriteString(i18n.T("wipe.none") + "\n")
+		return sb.String()
+	}
+	if !report.HasTimelines {
+		sb.WriteString(m.styles.ErrorText.Render(i18n.T("wipe.no_timeline")) + "\n")
+	}
+	for i, c := range report.Clusters {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		var spikes, cc, stab int
+		for _, d := range c.Deaths {
+			if d.DamageSpike {
+				spikes++
+			}
+			if d.Controlled {
+				cc++
+			}
+			if d.LostStability {
+				stab++
+			}
+		}
+		rowStr := fmt.Sprintf("%-14s %-7d %-6d %-4d %d", formatFightTime(c.StartMs), len(c.Deaths), spikes, cc, stab)
+		m.writeRow(&sb, i, rowStr)
+		if m.showAllRows {
+			for _, d := range c.Deaths {
+				sb.WriteString(fmt.Sprintf("  %s %s dmg:%s\n", m.playerCell(log, d.Player), formatFightTime(d.TimeMs), m.locale.Number(d.WindowDamage)))
+			}
+		}
+	}
+	sb.WriteString(i18n.T("wipe.total", report.TotalDeaths, len(report.Clusters), report.LikelyCause()))
+	return sb.String()
+}
+
+func (m *model) buildEnemyPressureCard(log *parser.ParsedLog) string {
+	threats := analysis.EnemyPressure(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-10s %-6s %s", m.nameWidth(), i18n.T("card.enemies"), i18n.T("col.dmg"), i18n.T("col.dps"), i18n.T("col.downs"))) + "\n")
+	m.writeThreats(&sb, threats)
+
+	// The enemies who downed the most of the squad, which needn't be the top damage
+	var downers []analysis.EnemyThreat
+	for _, t := range threats {
+		if t.Downs > 0 {
+			downers = append(downers, t)
+		}
+	}
+	if len(downers) == 0 {
+		return sb.String()
+	}
+	sort.SliceStable(downers, func(i, j int) bool { return downers[i].Downs > downers[j].Downs })
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-10s %-6s %s", m.nameWidth(), i18n.T("enemies.by_downs"), i18n.T("col.dmg"), i18n.T("col.dps"), i18n.T("col.downs"))) + "\n")
+	m.writeThreats(&sb, downers)
+	return sb.String()
+}
+
+// writeThreats lists enemies by their profession, the top five unless all rows are shown.
+func (m *model) writeThreats(sb *strings.Builder, threats []analysis.EnemyThreat) {
+	for i, t := range threats {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		name := t.Name
+		if t.Profession != "" {
+			name = t.Profession
+		}
+		rowStr := fmt.Sprintf("%s %-10s %-6s %d", m.specCell(t.Profession, name), m.locale.Number(t.Damage), m.locale.Number(t.Dps), t.Downs)
+		m.writeRow(sb, i, rowStr)
+	}
+}
+
+func (m *model) buildDownstateCard(log *parser.ParsedLog) string {
+	d := analysis.AnalyzeDownstate(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-15s %-6s %-7s %-7s %s", i18n.T("card.downstate"), i18n.T("col.downs"), i18n.T("col.killed"), i18n.T("col.rallied"), i18n.T("col.rate"))) + "\n")
+	m.writeRow(&sb, 0, fmt.Sprintf("%-15s %-6d %-7d %-7d %.0f%%", i18n.T("downstate.enemy"), d.EnemyDowns, d.EnemyKills, d.EnemyPickups, d.CleanupRate()*100))
+	m.writeRow(&sb, 1, fmt.Sprintf("%-15s %-6d %-7d %-7d %.0f%%", i18n.T("downstate.squad"), d.SquadDowns, d.SquadDeaths, d.SquadRecovered, d.RecoveryRate()*100))
+	if !d.FromReplay {
+		sb.WriteString(m.styles.HelpBar.Render(i18n.T("downstate.estimated")))
+	}
+	return sb.String()
+}
+
+func (m *model) buildIncomingStripsCard(log *parser.ParsedLog) string {
+	players := analysis.IncomingStrips(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-12s %-7s %s", m.nameWidth(), i18n.T("card.stripped"), i18n.T("col.prof"), i18n.T("col.strips"), i18n.T("col.stab_lost"))) + "\n")
+	total := 0
+	for i, p := range players {
+		total += p.Strips
+		if !m.showAllRows && i >= 5 {
+			continue
+		}
+		rowStr := fmt.Sprintf("%s %-12s %-7d %d", m.specCell(p.Profession, p.Name), p.Profession, p.Strips, p.StabLost)
+		m.writeRow(&sb, i, rowStr)
+	}
+	sb.WriteString(i18n.T("stripped.total", m.locale.Number(total)))
+	return sb.String()
+}
+
+func (m *model) buildModifiersCard(log *parser.ParsedLog) string {
+	players := analysis.DamageModifiers(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-10s %s", m.nameWidth(), i18n.T("card.modifiers"), i18n.T("col.gain"), i18n.T("col.top_modifier"))) + "\n")
+	if len(log.DamageModMap) == 0 {
+		sb.WriteString(m.styles.ErrorText.Render(i18n.T("modifiers.none")) + "\n")
+		return sb.String()
+	}
+	for i, p := range players {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		top := "-"
+		if len(p.Modifiers) > 0 {
+			top = fmt.Sprintf("%s %.0f%%", p.Modifiers[0].Name, p.Modifiers[0].Uptime*100)
+		}
+		rowStr := fmt.Sprintf("%s %-10s %s", m.specCell(p.Profession, p.Name), m.locale.Number(p.TotalGain), top)
+		m.writeRow(&sb, i, rowStr)
+		if m.showAllRows {
+			for _, mod := range p.Modifiers {
+				sb.WriteString(fmt.Sprintf("  %-30s %4.0f%% %s\n", mod.Name, mod.Uptime*100, m.locale.Number(mod.DamageGain)))
+			}
+		}
+	}
+	return sb.String()
+}
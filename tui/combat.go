@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const shareBarWidth = 10
+
+// shareBar draws a share between 0 and 1 as a bar of shareBarWidth cells.
+func shareBar(share float64) string {
+	filled := min(max(int(share*shareBarWidth+0.5), 0), shareBarWidth)
+	return strings.Repeat("█", filled) + strings.Repeat("░", shareBarWidth-filled)
+}
+
+// Gini levels of the damage spread verdict.
+const (
+	spreadEven     = 0.35
+	spreadTopHeavy = 0.55
+)
+
+func (m *model) buildSpreadCard(log *parser.ParsedLog) string {
+	s := analysis.AnalyzeDamageSpread(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-18s %-11s %s", i18n.T("card.spread"), "", i18n.T("col.share"))) + "\n")
+	if s.Total == 0 {
+		sb.WriteString(i18n.T("spread.none") + "\n")
+		return sb.String()
+	}
+	rows := []struct {
+		label string
+		share float64
+	}{
+		{i18n.T("spread.top5"), s.Top5},
+		{i18n.T("spread.top10"), s.Top10},
+		{i18n.T("spread.rest", max(s.Players-10, 0)), 1 - s.Top10},
+	}
+	for i, r := range rows {
+		m.writeRow(&sb, i, fmt.Sprintf("%-18s %s %3.0f%%", r.label, shareBar(r.share), r.share*100))
+	}
+
+	verdict, color := i18n.T("spread.even"), m.colors.Good
+	switch {
+	case s.Gini >= spreadTopHeavy:
+		verdict, color = i18n.T("spread.carried"), m.colors.Bad
+	case s.Gini >= spreadEven:
+		verdict, color = i18n.T("spread.top_heavy"), m.colors.Warning
+	}
+	sb.WriteString(i18n.T("spread.gini", s.Gini, s.DpsPlayers) + " " +
+		lipgloss.NewStyle().Foreground(color).Render(verdict) + "\n")
+	return sb.String()
+}
+
+func (m *model) buildFocusCard(log *parser.ParsedLog) string {
+	f := analysis.AnalyzeTargetFocus(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-24s %-12s %s", i18n.T("card.focus"), i18n.T("col.damage"), i18n.T("col.share"))) + "\n")
+	if f.Total == 0 {
+		sb.WriteString(i18n.T("focus.none") + "\n")
+		return sb.String()
+	}
+	for i, t := range f.Targets {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		m.writeRow(&sb, i, fmt.Sprintf("%-24.24s %-12s %3.0f%%", t.Name, m.locale.Number(t.Damage), t.Share*100))
+	}
+	color := m.colors.Good
+	switch {
+	case f.Score < 0.5:
+		color = m.colors.Bad
+	case f.Score < 0.7:
+		color = m.colors.Warning
+	}
+	score := lipgloss.NewStyle().Foreground(color).Render(i18n.T("focus.score", f.Score*100))
+	sb.WriteString(i18n.T("focus.summary", f.FocusedOn, len(f.Targets)) + " " + score + "\n")
+	return sb.String()
+}
+
+// burstNames is how many squad members are named under each burst window.
+const burstNames = 3
+
+func (m *model) buildBurstCard(log *parser.ParsedLog) string {
+	b := analysis.AnalyzeBursts(log)
+	var sb strings.Builder
+	title := i18n.T("card.burst", analysis.BurstWindowSeconds)
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-16s %-17s %-10s %s", title, i18n.T("col.when"), i18n.T("col.damage"), i18n.T("col.dps"))) + "\n")
+	if !b.Found {
+		sb.WriteString(i18n.T("burst.none") + "\n")
+		return sb.String()
+	}
+	rows := []struct {
+		label  string
+		window analysis.BurstWindow
+		color  lipgloss.Color
+		names  string
+	}{
+		{i18n.T("burst.squad"), b.Squad, m.colors.Good, "burst.squad_led"},
+		{i18n.T("burst.enemy"), b.Enemy, m.colors.Bad, "burst.enemy_focus"},
+	}
+	for i, r := range rows {
+		when := formatFightTime(r.window.StartMs) + "-" + formatFightTime(r.window.EndMs)
+		label := lipgloss.NewStyle().Foreground(r.color).Render(fmt.Sprintf("%-16s", r.label))
+		m.writeRow(&sb, i, fmt.Sprintf("%s %-17s %-10s %s", label, when, m.locale.Number(r.window.Damage), m.locale.Number(r.window.Dps())))
+	}
+	for _, r := range rows {
+		var names []string
+		for _, p := range r.window.Players[:min(len(r.window.Players), burstNames)] {
+			names = append(names, p.Name)
+		}
+		if len(names) > 0 {
+			sb.WriteString(i18n.T(r.names, strings.Join(names, ", ")) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Slices of the downs timeline on the dashboard and in the expanded card.
+const (
+	downsSlices     = 24
+	downsSlicesFull = 60
+)
+
+func (m *model) buildDownsTimelineCard(log *parser.ParsedLog) string {
+	slices := downsSlices
+	if m.showAllRows {
+		slices = downsSlicesFull
+	}
+	t := analysis.AnalyzeDownsTimeline(log, slices)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(i18n.T("card.downs_timeline")) + "\n")
+	if !t.Found {
+		sb.WriteString(i18n.T("downs_timeline.none") + "\n")
+		return sb.String()
+	}
+	enemy, squad := make([]float64, slices), make([]float64, slices)
+	maxV, enemyTotal, squadTotal := 0.0, 0, 0
+	for i := range slices {
+		enemy[i], squad[i] = float64(t.Enemy[i]), float64(t.Squad[i])
+		maxV = max(maxV, enemy[i], squad[i])
+		enemyTotal += t.Enemy[i]
+		squadTotal += t.Squad[i]
+	}
+	label := func(s string) string { return fmt.Sprintf("%-6s", s) }
+	sb.WriteString(label(i18n.T("downs_timeline.enemy")) + lipgloss.NewStyle().Foreground(m.colors.Good).Render(drawSparkline(enemy, maxV)) + fmt.Sprintf(" %d\n", enemyTotal))
+	sb.WriteString(label(i18n.T("downs_timeline.squad")) + lipgloss.NewStyle().Foreground(m.colors.Bad).Render(drawSparkline(squad, maxV)) + fmt.Sprintf(" %d\n", squadTotal))
+	end := formatFightTime(t.LengthMs)
+	sb.WriteString(label("") + formatFightTime(0) + strings.Repeat(" ", max(slices-2*len(end), 1)) + end + "\n")
+	return sb.String()
+}
+
+func (m *model) buildFirstPushCard(log *parser.ParsedLog) string {
+	f := analysis.AnalyzeFirstPush(log)
+	var sb strings.Builder
+	title := i18n.T("card.firstpush", analysis.FirstPushSeconds)
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-14s %-11s %-11s %s", title, i18n.T("col.opening"), i18n.T("col.fight"), i18n.T("col.share"))) + "\n")
+	if !f.Found {
+		sb.WriteString(i18n.T("firstpush.none") + "\n")
+		return sb.String()
+	}
+	rows := []struct {
+		label          string
+		opening, fight int
+	}{
+		{i18n.T("firstpush.damage"), f.Opening.Damage, f.Fight.Damage},
+		{i18n.T("firstpush.taken"), f.Opening.DamageTaken, f.Fight.DamageTaken},
+		{i18n.T("firstpush.enemy_downs"), f.Opening.EnemyDowns, f.Fight.EnemyDowns},
+		{i18n.T("firstpush.squad_downs"), f.Opening.SquadDowns, f.Fight.SquadDowns},
+		{i18n.T("firstpush.enemy_deaths"), f.Opening.EnemyDeaths, f.Fight.EnemyDeaths},
+		{i18n.T("firstpush.squad_deaths"), f.Opening.SquadDeaths, f.Fight.SquadDeaths},
+		{i18n.T("firstpush.stab_lost"), f.Opening.StabLost, f.Fight.StabLost},
+	}
+	for i, r := range rows {
+		share := "-"
+		if r.fight > 0 {
+			share = fmt.Sprintf("%.0f%%", float64(r.opening)/float64(r.fight)*100)
+		}
+		m.writeRow(&sb, i, fmt.Sprintf("%-14s %-11s %-11s %s", r.label, m.locale.Number(r.opening), m.locale.Number(r.fight), share))
+	}
+	return sb.String()
+}
+
+// buildSubgroupsCard ranks the squad's subgroups by damage per player, with their
+// cleanses, strips and deaths, so a weak party shows.
+func (m *model) buildSubgroupsCard(log *parser.ParsedLog) string {
+	groups := analysis.Subgroups(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-10s %-4s %-11s %-8s %-7s %s", i18n.T("card.subgroups"),
+		i18n.T("col.size"), i18n.T("col.dmg_player"), i18n.T("col.cleanse"), i18n.T("col.strips"), i18n.T("col.deaths"))) + "\n")
+	if len(groups) == 0 {
+		sb.WriteString(i18n.T("subgroups.none") + "\n")
+		return sb.String()
+	}
+	for i, g := range groups {
+		name := i18n.T("subgroups.group", g.Group)
+		if g.Group == 0 {
+			name = i18n.T("subgroups.unknown")
+		}
+		m.writeRow(&sb, i, fmt.Sprintf("%-10s %-4d %-11s %-8s %-7s %d", name, g.Players, m.locale.Number(g.DamagePerPlayer()),
+			m.locale.Number(g.Cleanses), m.locale.Number(g.Strips), g.Deaths))
+	}
+	return sb.String()
+}
+
+// renderDeathRecaps goes through the last seconds before every squad death, for the
+// expanded deaths card: what hit them hardest, CC, stability and distance from tag.
+func (m *model) renderDeathRecaps(log *parser.ParsedLog, tag *parser.Player) string {
+	recaps := analysis.DeathRecaps(log, tag)
+	if len(recaps) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n" + m.styles.CardTitle.Render(i18n.T("recap.title", len(recaps))) + "\n")
+	for i, r := range recaps {
+		var facts []string
+		if r.HasPos {
+			dist := CalculateDistance(Point{X: r.Pos[0], Y: r.Pos[1]}, Point{X: r.TagPos[0], Y: r.TagPos[1]})
+			facts = append(facts, i18n.T("recap.dist", m.locale.Number(int(dist))))
+		}
+		switch {
+		case r.StabAtEnd:
+			facts = append(facts, i18n.T("recap.stab_held"))
+		case r.Stability:
+			facts = append(facts, i18n.T("recap.stab_lost"))
+		default:
+			facts = append(facts, i18n.T("recap.no_stab"))
+		}
+		if len(r.CC) > 0 {
+			names := make([]string, len(r.CC))
+			for j, cc := range r.CC {
+				names[j] = i18n.T("cc." + cc)
+			}
+			facts = append(facts, i18n.T("recap.cc", strings.Join(names, ", ")))
+		}
+		m.writeRow(&sb, i, fmt.Sprintf("%s %-9s %s", m.specCell(r.Profession, r.Player), formatFightTime(r.TimeMs), strings.Join(facts, " · ")))
+		if r.Damage == 0 {
+			continue // No damage timeline in the log
+		}
+
+		hits := make([]string, len(r.Hits))
+		for j, h := range r.Hits {
+			hits[j] = i18n.T("recap.hit", r.TimeMs/1000-h.Second, m.locale.Number(h.Damage))
+		}
+		line := "  " + i18n.T("recap.taken", m.locale.Number(r.Damage))
+		if len(hits) > 0 {
+			line += " · " + i18n.T("recap.hardest", strings.Join(hits, ", "))
+		}
+		m.writeRow(&sb, i, line)
+	}
+	return sb.String()
+}
+
+// buildFinishersCard ranks killing blows, with each player's share of the squad's
+// kills, so stompers and cleavers get a leaderboard of their own.
+func (m *model) buildFinishersCard(log *parser.ParsedLog) string {
+	credits := analysis.KillCredits(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-6s %-6s %s", m.nameWidth(), i18n.T("card.finishers"),
+		i18n.T("col.kills"), i18n.T("col.downs"), i18n.T("col.share"))) + "\n")
+	if len(credits) == 0 {
+		sb.WriteString(i18n.T("finishers.none") + "\n")
+		return sb.String()
+	}
+	var kills int
+	for _, c := range credits {
+		kills += c.Kills
+	}
+	for i, c := range credits {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		share := "-"
+		if kills > 0 {
+			share = fmt.Sprintf("%.0f%%", float64(c.Kills)/float64(kills)*100)
+		}
+		m.writeRow(&sb, i, fmt.Sprintf("%s %-6d %-6d %s", m.specCell(c.Profession, c.Name), c.Kills, c.Downs, share))
+	}
+	return sb.String()
+}
@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RunRenamedMsg is sent after the current run folder was renamed on disk.
+type RunRenamedMsg struct {
+	OldPath string
+	NewPath string
+}
+
+// findCommander picks the squad member that counts as commander for a log.
+// Preferred accounts are tried in order (a manual override, then the configured
+// "my account"), falling back to the first tagged squad member. When several
+// players are tagged, a preferred account that is tagged wins over the others.
+func findCommander(log *parser.ParsedLog, preferred ...string) *parser.Player {
+	for _, account := range preferred {
+		if account == "" {
+			continue
+		}
+		for i := range log.Players {
+			// Either may be a pseudonym, depending on when the override was picked
+			if !log.Players[i].NotInSquad && strings.EqualFold(parser.RealAccount(log.Players[i].Account), parser.RealAccount(account)) {
+				return &log.Players[i]
+			}
+		}
+	}
+	for i := range log.Players {
+		if log.Players[i].HasCommanderTag && !log.Players[i].NotInSquad {
+			return &log.Players[i]
+		}
+	}
+	for i := range log.Players {
+		if log.Players[i].HasCommanderTag {
+			return &log.Players[i]
+		}
+	}
+	return nil
+}
+
+// commanderFor resolves the commander for a log using the run override and config.
+func (m *model) commanderFor(log *parser.ParsedLog) *parser.Player {
+	return findCommander(log, m.commanderOverride, m.config.MyAccount)
+}
+
+// commanderCandidates lists tagged squad members first, then everyone else in squad.
+func commanderCandidates(log *parser.ParsedLog) []string {
+	var tagged, others []string
+	for _, p := range log.Players {
+		if p.NotInSquad {
+			continue
+		}
+		if p.HasCommanderTag {
+			tagged = append(tagged, p.Account)
+		} else {
+			others = append(others, p.Account)
+		}
+	}
+	return append(tagged, others...)
+}
+
+// cycleCommander moves the commander override to the next candidate in the selected log.
+func (m *model) cycleCommander() {
+	log := m.selectedLog()
+	if log == nil {
+		return
+	}
+	candidates := commanderCandidates(log)
+	if len(candidates) == 0 {
+		return
+	}
+	current := ""
+	if c := m.commanderFor(log); c != nil {
+		current = c.Account
+	}
+	next := candidates[0]
+	for i, account := range candidates {
+		if account == current {
+			next = candidates[(i+1)%len(candidates)]
+			break
+		}
+	}
+	m.commanderOverride = next
+	m.setStatus(i18n.T("status.commander", next))
+}
+
+// selectedLog returns the parsed log highlighted in logsView, if any.
+func (m *model) selectedLog() *parser.ParsedLog {
+	if m.viewMode != logsView || m.selectedIndex == 0 || m.selectedIndex > len(m.logList) {
+		return nil
+	}
+	return m.logs[m.logFullPaths[m.logList[m.selectedIndex-1]]]
+}
+
+// renameRunForCommander renames the current run folder so its name starts with the
+// commander account, keeping the timestamp part.
+func (m *model) renameRunForCommander() tea.Cmd {
+	log := m.selectedLog()
+	if log == nil || m.currentRunPath == "" {
+		return nil
+	}
+	commander := m.commanderFor(log)
+	if commander == nil {
+		return nil
+	}
+	oldPath := m.currentRunPath
+	parts := strings.SplitN(filepath.Base(oldPath), "_", 2)
+	account := parser.RealAccount(commander.Account)
+	if len(parts) != 2 || parts[0] == account {
+		return nil
+	}
+	newPath := filepath.Join(filepath.Dir(oldPath), account+"_"+parts[1])
+	return func() tea.Msg {
+		if _, err := os.Stat(newPath); err == nil {
+			return ErrMsg{Err: fmt.Errorf("cannot rename run: %s already exists", filepath.Base(newPath))}
+		}
+		if err := os.Rename(oldPath, newPath); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to rename run: %w", err)}
+		}
+		return RunRenamedMsg{OldPath: oldPath, NewPath: newPath}
+	}
+}
+
+// applyRunRename re-keys the in-memory maps after the run folder moved.
+func (m *model) applyRunRename(msg RunRenamedMsg) {
+	if m.liveRunPath == msg.OldPath {
+		m.liveRunPath = msg.NewPath
+	}
+	if m.tally.runPath == msg.OldPath {
+		m.tally.runPath = msg.NewPath
+	}
+	m.renameRunEntry(filepath.Base(msg.OldPath), filepath.Base(msg.NewPath))
+	if m.currentRunPath != msg.OldPath {
+		return
+	}
+	m.currentRunPath = msg.NewPath
+	m.currentRunName = filepath.Base(msg.NewPath)
+	logs := make(map[string]*parser.ParsedLog, len(m.logs))
+	for path, log := range m.logs {
+		logs[filepath.Join(msg.NewPath, filepath.Base(path))] = log
+	}
+	m.logs = logs
+	for i, path := range m.logOrder {
+		m.logOrder[i] = filepath.Join(msg.NewPath, filepath.Base(path))
+	}
+	broken := make(map[string]error, len(m.brokenLogs))
+	for path, err := range m.brokenLogs {
+		broken[filepath.Join(msg.NewPath, filepath.Base(path))] = err
+	}
+	m.brokenLogs = broken
+	// Parses under the old path are dropped when done, so they start over
+	queue := make([]string, 0, len(m.parsing)+len(m.parseQueue))
+	for path := range m.parsing {
+		queue = append(queue, path)
+	}
+	sort.Strings(queue)
+	queue = append(queue, m.parseQueue...)
+	for i, path := range queue {
+		queue[i] = filepath.Join(msg.NewPath, filepath.Base(path))
+	}
+	m.parseQueue, m.parsing = queue, make(map[string]bool)
+	summaries := make(map[string]analysis.FightSummary, len(m.summaries))
+	for path, summary := range m.summaries {
+		summaries[filepath.Join(msg.NewPath, filepath.Base(path))] = summary
+	}
+	m.summaries = summaries
+	for name, path := range m.logFullPaths {
+		m.logFullPaths[name] = filepath.Join(msg.NewPath, filepath.Base(path))
+	}
+	m.setStatus(i18n.T("status.renamed", processor.RunLabel(m.currentRunName)))
+}
+
+// renameRunEntry points the run list at a renamed run folder, keeping its place and
+// selection, so the new name shows without reloading the list.
+func (m *model) renameRunEntry(oldName, newName string) {
+	info, ok := m.runInfo[oldName]
+	if !ok {
+		return
+	}
+	delete(m.runInfo, oldName)
+	info.commander = processor.ParseRunName(newName).Commander
+	m.runInfo[newName] = info
+	for i, run := range m.runList {
+		if run == oldName {
+			m.runList[i] = newName
+		}
+	}
+	for i, row := range m.runRows {
+		if row.run == oldName {
+			m.runRows[i].run = newName
+		}
+	}
+	m.sortRuns()
+}
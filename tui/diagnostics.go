@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/processor"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// parseRecord is one archived fight's parse stats, or a failed parse's.
+type parseRecord struct {
+	Run   string // Empty for a failed parse
+	Fight string // The log's file name for a failed parse
+	processor.ParseStats
+}
+
+// perLog is the share of the EI process's time spent on this log.
+func (r parseRecord) perLog() time.Duration {
+	return time.Duration(r.Seconds / float64(max(r.Batch, 1)) * float64(time.Second))
+}
+
+// diagnosticsMsg carries the parse stats of every run, slowest first.
+type diagnosticsMsg struct {
+	Parses []parseRecord
+	Err    error
+}
+
+func loadDiagnostics() tea.Msg {
+	entries, err := os.ReadDir(processor.LogArchive)
+	if err != nil {
+		return diagnosticsMsg{Err: err}
+	}
+	var parses []parseRecord
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		meta, err := processor.LoadRunMeta(filepath.Join(processor.LogArchive, e.Name()))
+		if err != nil {
+			continue
+		}
+		for fight, stats := range meta.ParseStats {
+			parses = append(parses, parseRecord{Run: e.Name(), Fight: fight, ParseStats: stats})
+		}
+	}
+	failed, err := processor.LoadFailedParses()
+	if err != nil {
+		logger.Warn("failed to read %s: %v", processor.FailedParsesFile, err)
+	}
+	for _, f := range failed {
+		parses = append(parses, parseRecord{Fight: f.Log, ParseStats: f.ParseStats})
+	}
+	sort.Slice(parses, func(i, j int) bool { return parses[i].perLog() > parses[j].perLog() })
+	return diagnosticsMsg{Parses: parses}
+}
+
+// toggleDiagnostics opens the slow-parse view, reading the stats fresh each time.
+func (m *model) toggleDiagnostics() tea.Cmd {
+	m.showDiagnostics = !m.showDiagnostics
+	if !m.showDiagnostics {
+		return nil
+	}
+	m.diagnostics = nil
+	return loadDiagnostics
+}
+
+func (m *model) handleDiagnostics(msg diagnosticsMsg) {
+	if msg.Err != nil {
+		m.showDiagnostics = false
+		m.setError(fmt.Errorf("failed to read parse stats: %w", msg.Err))
+		return
+	}
+	m.diagnostics = msg.Parses
+}
+
+func (m model) handleDiagnosticsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case keyQuit.matches(msg):
+		return m, tea.Quit
+	case keyCloseDiagnostics.matches(msg):
+		m.showDiagnostics = false
+	}
+	return m, nil
+}
+
+func (m *model) renderDiagnosticsView() string {
+	height := max(m.height-4, 3) // status bar, two help lines and the border
+
+	var content strings.Builder
+	content.WriteString(m.styles.CardTitle.Render(i18n.T("diag.title")) + "\n\n")
+
+	memory := i18n.T("diag.no_limit")
+	if m.config.EIMemoryLimitMB > 0 {
+		memory = fmt.Sprintf("%d MB", m.config.EIMemoryLimitMB)
+	}
+	timeout := processor.DefaultEITimeout
+	if m.config.EITimeoutSeconds > 0 {
+		timeout = time.Duration(m.config.EITimeoutSeconds) * time.Second
+	}
+	content.WriteString(i18n.T("diag.settings", memory, m.config.EISingleThreaded,
+		max(m.config.MaxEIProcesses, 1), timeout) + "\n")
+
+	switch {
+	case m.diagnostics == nil:
+		content.WriteString(i18n.T("diag.loading") + "\n")
+	case len(m.diagnostics) == 0:
+		content.WriteString(i18n.T("diag.none") + "\n")
+	default:
+		m.writeDiagnostics(&content, height-6)
+	}
+
+	return m.styles.RightPanel.Copy().
+		Width(m.width - m.styles.RightPanel.GetHorizontalFrameSize()).
+		Height(height).
+		BorderForeground(m.colors.Highlight).
+		Render(content.String())
+}
+
+// writeDiagnostics writes the parse time spread and as many of the slowest parses as fit
+// in rows lines.
+func (m *model) writeDiagnostics(sb *strings.Builder, rows int) {
+	var total time.Duration
+	for _, p := range m.diagnostics {
+		total += p.perLog()
+	}
+	n := len(m.diagnostics)
+	sb.WriteString(i18n.T("diag.summary", n, seconds(total/time.Duration(n)),
+		seconds(m.diagnostics[n/2].perLog()), seconds(m.diagnostics[0].perLog())) + "\n\n")
+
+	header := fmt.Sprintf("%-8s %-7s %8s %8s %8s %4s  %-18s %s", i18n.T("col.parse_time"), i18n.T("col.batch"),
+		i18n.T("col.log_size"), i18n.T("col.json_size"), i18n.T("col.html_size"), i18n.T("col.exit"),
+		i18n.T("col.fight"), i18n.T("col.run"))
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(header) + "\n")
+	for i, p := range m.diagnostics[:min(n, max(rows, 1))] {
+		batch := "-"
+		if p.Batch > 1 {
+			batch = fmt.Sprint(p.Batch)
+		}
+		run := processor.RunLabel(p.Run)
+		switch {
+		case p.TimedOut:
+			run = i18n.T("diag.timed_out")
+		case p.Run == "":
+			run = i18n.T("diag.failed")
+		}
+		row := fmt.Sprintf("%-8s %-7s %8s %8s %8s %4d  %-18.18s %s", seconds(p.perLog()), batch,
+			megabytes(p.LogBytes), megabytes(p.JSONBytes), megabytes(p.HTMLBytes), p.ExitCode,
+			p.Fight, run)
+		m.writeRow(sb, i, row)
+	}
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+func megabytes(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
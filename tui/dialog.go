@@ -0,0 +1,238 @@
+package tui
+
+import (
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"math/rand"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// dialog is a question shown in a box over the dimmed screen. It takes every key until
+// it is answered and starts on the No button, so a stray Enter cancels. Messages other
+// than keys are still handled behind it.
+type dialog struct {
+	title    string
+	details  []string // Lines under the title, e.g. what would be deleted
+	yes      string   // Label of the confirm button
+	danger   bool     // The action can't be undone
+	onYes    func(m *model) tea.Cmd
+	focusYes bool
+	token    string // If set, must be typed before the confirm button works
+	typed    string
+}
+
+// tokenChars are what confirmation codes are made of, leaving out look-alikes.
+const tokenChars = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// newToken is a random code of n characters to type before a big deletion.
+func newToken(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = tokenChars[rand.Intn(len(tokenChars))]
+	}
+	return string(b)
+}
+
+// unlocked is whether the confirm button works: no code is needed, or it was typed.
+func (d *dialog) unlocked() bool {
+	return d.token == "" || strings.EqualFold(d.typed, d.token)
+}
+
+// openDialog asks a question, replacing any open one.
+func (m *model) openDialog(d dialog) {
+	m.dialog = &d
+}
+
+// handleDialogKeys answers the dialog. While a code is asked for, letters go into
+// the code, so y and n don't answer it.
+func (m model) handleDialogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := *m.dialog
+	typing := d.token != ""
+	switch {
+	case keyDialogSwitch.matches(msg):
+		d.focusYes = !d.focusYes
+	case keyDialogChoose.matches(msg):
+		if !d.focusYes {
+			return m.cancelDialog()
+		}
+		if !d.unlocked() {
+			m.setStatus(i18n.T("status.type_token", d.token))
+			return m, nil
+		}
+		m.dialog = nil
+		return m, d.onYes(&m)
+	case typing && msg.Type == tea.KeyBackspace:
+		if d.typed != "" {
+			d.typed = d.typed[:len(d.typed)-1]
+		}
+	case typing && msg.Type == tea.KeyRunes:
+		if len(d.typed) < len(d.token) {
+			d.typed += string(msg.Runes)
+		}
+		d.focusYes = d.focusYes || d.unlocked()
+	case keyDialogYes.matches(msg):
+		m.dialog = nil
+		return m, d.onYes(&m)
+	case keyDialogNo.matches(msg):
+		return m.cancelDialog()
+	}
+	m.dialog = &d
+	return m, nil
+}
+
+func (m model) cancelDialog() (tea.Model, tea.Cmd) {
+	m.dialog = nil
+	m.setStatus(i18n.T("status.cancelled"))
+	return m, nil
+}
+
+// renderDialog draws the open dialog centered over a dimmed copy of screen.
+func (m *model) renderDialog(screen string) string {
+	d := m.dialog
+	accent := m.colors.Highlight
+	if d.danger {
+		accent = m.colors.Bad
+	}
+	button := lipgloss.NewStyle().Padding(0, 2).Foreground(m.colors.Muted)
+	focused := button.Copy().Bold(true).Foreground(m.colors.TextOnAccent).Background(m.colors.Highlight)
+	no, yes := focused.Render(i18n.T("dialog.no")), button.Render(d.yes)
+	if d.focusYes {
+		no, yes = button.Render(i18n.T("dialog.no")), focused.Copy().Background(accent).Render(d.yes)
+	}
+	if !d.unlocked() {
+		yes = button.Copy().Faint(true).Render(d.yes)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Foreground(accent).Render(d.title) + "\n\n")
+	for _, line := range d.details {
+		sb.WriteString(line + "\n")
+	}
+	if d.token != "" {
+		code := lipgloss.NewStyle().Bold(true).Foreground(accent).Render(d.token)
+		field := lipgloss.NewStyle().Foreground(m.colors.Highlight).
+			Render(d.typed + strings.Repeat("_", len(d.token)-len(d.typed)))
+		sb.WriteString("\n" + i18n.T("dialog.type_token", code) + " " + field + "\n")
+	}
+	sb.WriteString("\n" + lipgloss.JoinHorizontal(lipgloss.Top, no, "  ", yes))
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accent).
+		Padding(1, 2).
+		MaxWidth(m.width).
+		Render(sb.String())
+
+	dim := lipgloss.NewStyle().Foreground(m.colors.Muted)
+	top := max((lipgloss.Height(screen)-lipgloss.Height(box))/2, 0)
+	left := max((m.width-lipgloss.Width(box))/2, 0)
+	return placeOver(ansi.Strip(screen), box, top, left, dim.Render)
+}
+
+// placeOver draws box over screen with its top left corner at row top and column left.
+// The rest of the screen is passed through style, if any, e.g. to dim it.
+func placeOver(screen, box string, top, left int, style func(...string) string) string {
+	lines := strings.Split(screen, "\n")
+	boxLines := strings.Split(box, "\n")
+	boxWidth := lipgloss.Width(box)
+	if style == nil {
+		style = func(s ...string) string { return strings.Join(s, "") }
+	}
+	for len(lines) < top+len(boxLines) {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		if i < top || i >= top+len(boxLines) {
+			lines[i] = style(line)
+			continue
+		}
+		width := ansi.StringWidth(line)
+		line += strings.Repeat(" ", max(left-width, 0))
+		lines[i] = style(ansi.Cut(line, 0, left)) + boxLines[i-top] + style(ansi.Cut(line, left+boxWidth, width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// confirmDeleteRun asks before deleting the highlighted run and its fights. As a run
+// can hold a whole night of fights, a random code must be typed to confirm.
+func (m *model) confirmDeleteRun(runName string) {
+	details := []string{processor.RunLabel(runName)}
+	if info, ok := m.runInfo[runName]; ok {
+		if !info.started.IsZero() {
+			details = append(details, i18n.T("dialog.started", m.locale.DateTime(info.started)))
+		}
+		details = append(details, i18n.T("dialog.fights", info.fights))
+	}
+	details = append(details, "", i18n.T("dialog.delete_run_note"))
+	runPath := filepath.Join(processor.LogArchive, runName)
+	m.openDialog(dialog{
+		title:   i18n.T("dialog.delete_run"),
+		details: details,
+		yes:     i18n.T("dialog.delete"),
+		danger:  true,
+		token:   newToken(3),
+		onYes: func(m *model) tea.Cmd {
+			m.setStatus(i18n.T("status.deleting_run", processor.RunLabel(runName)))
+			return deleteRun(runPath)
+		},
+	})
+}
+
+// confirmDeleteLog asks before deleting a fight's JSON and HTML report.
+func (m *model) confirmDeleteLog(logName string) {
+	details := []string{logName}
+	if s, ok := m.summaries[m.logFullPaths[logName]]; ok {
+		if !s.Start.IsZero() {
+			details = append(details, i18n.T("dialog.started", m.locale.DateTime(s.Start)))
+		}
+		if where := m.logMap(logName, s); where != "" {
+			details = append(details, i18n.T("dialog.map", where))
+		}
+	}
+	details = append(details, "", i18n.T("dialog.delete_log_note"))
+	m.openDialog(dialog{
+		title:   i18n.T("dialog.delete_log"),
+		details: details,
+		yes:     i18n.T("dialog.delete"),
+		danger:  true,
+		onYes: func(m *model) tea.Cmd {
+			fullPath, ok := m.logFullPaths[logName]
+			if !ok {
+				return nil // Gone meanwhile
+			}
+			// Optimistically remove from UI
+			delete(m.logs, fullPath)
+			delete(m.summaries, fullPath)
+			delete(m.logFullPaths, logName)
+			for i, name := range m.logList {
+				if name == logName {
+					m.logList = append(m.logList[:i], m.logList[i+1:]...)
+					break
+				}
+			}
+			m.sortLogList() // Other points of view of the fight come back into the list
+			if m.selectedIndex >= len(m.logList)+1 {
+				m.selectedIndex = len(m.logList)
+			}
+			m.setStatus(i18n.T("status.deleted_log", logName))
+			return deleteLogFiles(fullPath)
+		},
+	})
+}
+
+// confirmAppUpdate offers to open the download page of a new version.
+func (m *model) confirmAppUpdate(url string) {
+	m.openDialog(dialog{
+		title:   i18n.T("dialog.update"),
+		details: []string{url},
+		yes:     i18n.T("dialog.open_download"),
+		onYes: func(m *model) tea.Cmd {
+			m.setStatus(i18n.T("status.opening_update"))
+			return m.openInBrowser(url, i18n.T("status.opening_update"))
+		},
+	})
+}
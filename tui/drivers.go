@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteHandoff records in run.json that the commander tag changed hands, if a fight
+// just added to the current run was led by someone other than the fight before it.
+func (m *model) noteHandoff(name string) tea.Cmd {
+	names, fights := m.summarizedFights()
+	i := -1
+	for j, n := range names {
+		if n == name {
+			i = j
+		}
+	}
+	if i < 1 {
+		return nil
+	}
+	drives := analysis.Drives(fights[:i+1])
+	last := drives[len(drives)-1]
+	if len(drives) < 2 || last.First != i {
+		return nil
+	}
+	for _, h := range m.runMeta.Handoffs {
+		if h.Fight == name {
+			return nil // Archived again
+		}
+	}
+	h := processor.Handoff{Time: fights[i].Start.UTC(), Fight: name, From: drives[len(drives)-2].Commander, To: last.Commander}
+	meta := m.updateRunMeta(m.currentRunPath, func(meta *processor.RunMeta) {
+		meta.Handoffs = append(meta.Handoffs, h)
+	})
+	return tea.Batch(meta, m.toast(i18n.T("toast.handoff", m.driverName(h.From), m.driverName(h.To))))
+}
+
+// driverName is a commander account for the driver list, naming untagged stretches.
+func (m *model) driverName(account string) string {
+	if account == "" {
+		return i18n.T("drivers.untagged")
+	}
+	return account
+}
+
+// renderDrivers lists who led which fights of the run when the commander tag changed
+// hands, and with split_by_driver each commander's share of the results. It is empty
+// when one commander led the whole run.
+func (m *model) renderDrivers() string {
+	names, fights := m.summarizedFights()
+	drives := analysis.Drives(fights)
+	if len(drives) < 2 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-18s %-18s %s", m.nameWidth(), i18n.T("drivers.title", len(drives)-1),
+		i18n.T("col.from"), i18n.T("col.to"), i18n.T("col.fights"))) + "\n")
+	for i, d := range drives {
+		rowStr := fmt.Sprintf("%s %-18s %-18s %d", m.nameCell(m.driverName(d.Commander)), names[d.First], names[d.Last], d.Fights())
+		m.writeRow(&sb, i, rowStr)
+	}
+	if !m.config.SplitByDriver {
+		return sb.String()
+	}
+
+	sb.WriteString("\n" + m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-7s %-12s %s", m.nameWidth(), i18n.T("drivers.split"),
+		i18n.T("col.fights"), i18n.T("col.result"), i18n.T("col.kd"))) + "\n")
+	split := analysis.SplitByDriver(fights, drives, m.config.OutnumberedRatio, m.config.ExcludeOutnumberedLosses)
+	for i, t := range split {
+		record := fmt.Sprintf("%dW %dL %dD", t.Record.Wins, t.Record.Losses, t.Record.Draws)
+		rowStr := fmt.Sprintf("%s %-7d %-12s %s / %s", m.nameCell(m.driverName(t.Commander)), t.Fights, record,
+			m.locale.Number(t.Kills), m.locale.Number(t.Deaths))
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
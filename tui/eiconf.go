@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// eiConfMsg carries the ELI3.conf options that are set wrong.
+type eiConfMsg struct {
+	Issues []processor.ConfIssue
+	Err    error
+}
+
+// eiConfFixedMsg reports that the wrong ELI3.conf options were corrected.
+type eiConfFixedMsg struct {
+	Fixed int
+	Err   error
+}
+
+func lintEIConf() tea.Msg {
+	issues, err := processor.LintEIConf()
+	return eiConfMsg{Issues: issues, Err: err}
+}
+
+// handleEIConf warns about ELI3.conf options that would make logs time out or come out
+// unreadable. The help bar offers the fix until it is applied.
+func (m *model) handleEIConf(msg eiConfMsg) {
+	if msg.Err != nil {
+		m.warnOnce(msg.Err.Error())
+		return
+	}
+	m.eiConfIssues = msg.Issues
+	if len(msg.Issues) == 0 {
+		return
+	}
+	names := make([]string, len(msg.Issues))
+	for i, issue := range msg.Issues {
+		names[i] = issue.String()
+	}
+	m.warnOnce(i18n.T("eiconf.warning", processor.EIConfPath, strings.Join(names, ", ")))
+}
+
+// fixEIConf sets the wrong ELI3.conf options to what the app needs.
+func (m *model) fixEIConf() tea.Cmd {
+	issues := m.eiConfIssues
+	return func() tea.Msg {
+		if err := processor.FixEIConf(issues); err != nil {
+			return eiConfFixedMsg{Err: fmt.Errorf("failed to fix %s: %w", processor.EIConfPath, err)}
+		}
+		return eiConfFixedMsg{Fixed: len(issues)}
+	}
+}
+
+func (m *model) handleEIConfFixed(msg eiConfFixedMsg) {
+	if msg.Err != nil {
+		m.setError(msg.Err)
+		return
+	}
+	m.eiConfIssues = nil
+	m.setStatus(i18n.T("status.eiconf_fixed", processor.EIConfPath, msg.Fixed))
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxEventLogEntries caps the scrollback so a long raid night doesn't grow memory forever.
+const maxEventLogEntries = 500
+
+type eventLevel int
+
+const (
+	eventInfo eventLevel = iota
+	eventWarn
+	eventError
+)
+
+// eventEntry is a single timestamped line in the event log panel.
+type eventEntry struct {
+	Time  time.Time
+	Level eventLevel
+	Text  string
+}
+
+// eventLog keeps a bounded history of status messages, warnings and errors.
+type eventLog struct {
+	entries []eventEntry
+	offset  int // Number of lines scrolled up from the newest entry
+}
+
+func (l *eventLog) add(level eventLevel, text string) {
+	if text == "" {
+		return
+	}
+	l.entries = append(l.entries, eventEntry{Time: time.Now(), Level: level, Text: text})
+	if len(l.entries) > maxEventLogEntries {
+		l.entries = l.entries[len(l.entries)-maxEventLogEntries:]
+	}
+	// Keep the user's scroll position anchored while new lines arrive
+	if l.offset > 0 {
+		l.offset++
+	}
+	l.clampOffset()
+}
+
+func (l *eventLog) scroll(delta int) {
+	l.offset += delta
+	l.clampOffset()
+}
+
+func (l *eventLog) clampOffset() {
+	if l.offset > len(l.entries)-1 {
+		l.offset = len(l.entries) - 1
+	}
+	if l.offset < 0 {
+		l.offset = 0
+	}
+}
+
+// setStatus replaces the status line and records it in the event log.
+// A new status also clears a previous error, which stays visible in the log history.
+func (m *model) setStatus(s string) {
+	m.status = s
+	m.err = nil
+	m.events.add(eventInfo, s)
+}
+
+// setError shows an error in the status bar and records it in the event log, with
+// the suggested fix if it is a known kind of error.
+func (m *model) setError(err error) {
+	if err == nil {
+		return
+	}
+	m.err = err
+	text := err.Error()
+	if fix := remedy(err); fix != "" {
+		text += " — " + fix
+	}
+	m.events.add(eventError, text)
+}
+
+// warnOnce shows a warning in the status bar and event log the first time it occurs,
+// so a whole run of logs with the same problem doesn't flood the history.
+func (m *model) warnOnce(text string) {
+	if m.warned[text] {
+		return
+	}
+	m.warned[text] = true
+	m.status = i18n.T("status.warning", text)
+	m.events.add(eventWarn, text)
+}
+
+func (m *model) renderEventLogView() string {
+	height := m.height - 4 // status bar, two help lines and the border
+	if height < 3 {
+		height = 3
+	}
+	rows := height - 2 // title and blank line
+
+	var content strings.Builder
+	title := i18n.T("eventlog.title", len(m.events.entries))
+	if m.events.offset > 0 {
+		title += i18n.T("eventlog.scrolled", m.events.offset)
+	}
+	content.WriteString(m.styles.CardTitle.Render(title) + "\n\n")
+
+	end := len(m.events.entries) - m.events.offset
+	start := end - rows
+	if start < 0 {
+		start = 0
+	}
+	for _, e := range m.events.entries[start:end] {
+		var style lipgloss.Style
+		tag := "INFO "
+		switch e.Level {
+		case eventWarn:
+			style = lipgloss.NewStyle().Foreground(m.colors.Warning)
+			tag = "WARN "
+		case eventError:
+			style = m.styles.ErrorText
+			tag = "ERROR"
+		default:
+			style = lipgloss.NewStyle().Foreground(m.colors.Text)
+		}
+		line := fmt.Sprintf("%s %s %s", m.locale.Time(e.Time), tag, e.Text)
+		content.WriteString(style.Render(line) + "\n")
+	}
+
+	return m.styles.RightPanel.Copy().
+		Width(m.width - m.styles.RightPanel.GetHorizontalFrameSize()).
+		Height(height).
+		BorderForeground(m.colors.Highlight).
+		Render(content.String())
+}
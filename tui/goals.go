@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/config"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Stats that can have goals in stat_thresholds.
+const (
+	statDps        = "dps"
+	statDownCont   = "down_contribution"
+	statCleanses   = "cleanses"
+	statStrips     = "strips"
+	statHealingHps = "healing"
+	statBarrierBps = "barrier"
+)
+
+// threshold returns the goal for a stat, preferring the role's own over "all".
+func (m *model) threshold(role, stat string) (config.Threshold, bool) {
+	if t, ok := m.config.StatThresholds[role][stat]; ok {
+		return t, true
+	}
+	t, ok := m.config.StatThresholds["all"][stat]
+	return t, ok
+}
+
+// goalCell renders a value padded to width, in red or the warning color when it is
+// below the goal for the player's role. roles comes from analysis.Roles.
+func (m *model) goalCell(roles map[string]string, name, stat string, value, width int) string {
+	text := fmt.Sprintf("%-*s", width, m.locale.Number(value))
+	t, ok := m.threshold(roles[name], stat)
+	if !ok {
+		return text
+	}
+	switch v := float64(value); {
+	case t.Bad > 0 && v < t.Bad:
+		return lipgloss.NewStyle().Foreground(m.colors.Bad).Render(text)
+	case t.Warn > 0 && v < t.Warn:
+		return lipgloss.NewStyle().Foreground(m.colors.Warning).Render(text)
+	}
+	return text
+}
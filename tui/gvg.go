@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleGvG switches the current run between open-field and GvG and saves the
+// choice to run.json.
+func (m *model) toggleGvG() tea.Cmd {
+	if m.viewMode != logsView || m.currentRunPath == "" {
+		return nil
+	}
+	runType := processor.RunTypeGvG
+	if m.runMeta.Type == processor.RunTypeGvG {
+		runType = processor.RunTypeOpenField
+		m.setStatus(i18n.T("status.open_field"))
+	} else {
+		m.setStatus(i18n.T("status.gvg"))
+	}
+	return m.updateRunMeta(m.currentRunPath, func(meta *processor.RunMeta) {
+		meta.Type = runType
+	})
+}
+
+// renderGvGRounds lists the matches of a GvG run with the running score after each round.
+func (m *model) renderGvGRounds() string {
+	var names []string
+	var fights []analysis.FightSummary
+	for _, name := range m.chronologicalLogs() {
+		if s, ok := m.summaries[m.logFullPaths[name]]; ok {
+			names = append(names, name)
+			fights = append(fights, s)
+		}
+	}
+
+	var sb strings.Builder
+	for i, match := range analysis.TrackGvG(fights) {
+		title := i18n.T("gvg.match", i+1, match.EnemyCount, match.Wins, match.Losses)
+		sb.WriteString(m.styles.CardTitle.Render(title) + "\n")
+		sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-6s %-18s %-8s %-8s %-8s %s", i18n.T("col.round"), i18n.T("col.fight"), i18n.T("col.sq_dead"), i18n.T("col.en_dead"), i18n.T("col.result"), i18n.T("col.score"))) + "\n")
+		wins, losses := 0, 0
+		for r, round := range match.Rounds {
+			result := "-"
+			switch round.Result {
+			case analysis.Win:
+				wins++
+				result = i18n.T("gvg.win")
+			case analysis.Loss:
+				losses++
+				result = i18n.T("gvg.loss")
+			}
+			s := round.Summary
+			rowStr := fmt.Sprintf("%-6d %-18s %-8s %-8s %-8s %d-%d", r+1, names[round.Index],
+				fmt.Sprintf("%d/%d", s.SquadDeaths, s.SquadCount), fmt.Sprintf("%d/%d", s.EnemyDeaths, s.EnemyCount),
+				result, wins, losses)
+			m.writeRow(&sb, r, rowStr)
+		}
+		sb.WriteString("\n")
+	}
+	if len(fights) == 0 {
+		sb.WriteString(i18n.T("gvg.none") + "\n")
+	}
+	return sb.String()
+}
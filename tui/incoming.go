@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"gw2-cmd-watch/i18n"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxRunLogs is how many fights a run takes before the next one starts a new run.
+const maxRunLogs = 30
+
+// fightArchiveFailedMsg reports a parsed fight that could not be moved into its run.
+type fightArchiveFailedMsg struct {
+	Name string // Display name
+	Err  error
+}
+
+// newFightStartsRun is whether the next new fight starts a run rather than joining
+// the run being viewed.
+func (m *model) newFightStartsRun() bool {
+	return m.newRunPending || m.viewMode == runsView || len(m.logList) >= maxRunLogs
+}
+
+func (m *model) handleFightArchiveFailed(msg fightArchiveFailedMsg) {
+	delete(m.arriving, msg.Name)
+	m.setError(msg.Err)
+}
+
+// incomingRows are placeholder rows at the end of the log list for new fights on
+// their way into the viewed run: logs EI is parsing, then fights being archived.
+// The fight's own row replaces them once it is archived.
+func (m *model) incomingRows() []string {
+	if m.viewMode != logsView {
+		return nil
+	}
+	var names []string
+	if !m.newFightStartsRun() {
+		for _, p := range m.parses {
+			names = append(names, strings.TrimSuffix(p.label, filepath.Ext(p.label)))
+		}
+	}
+	var arriving []string
+	for name, runPath := range m.arriving {
+		if runPath == m.currentRunPath {
+			arriving = append(arriving, name)
+		}
+	}
+	sort.Strings(arriving)
+	names = append(names, arriving...)
+
+	// The spinner pulses between the highlight and muted colors
+	color := m.colors.Highlight
+	if m.spinnerFrame >= len(spinnerFrames)/2 {
+		color = m.colors.Muted
+	}
+	spinner := lipgloss.NewStyle().Foreground(color).Render(spinnerFrames[m.spinnerFrame])
+	text := lipgloss.NewStyle().Foreground(m.colors.Muted).Italic(true)
+	rows := make([]string, len(names))
+	for i, name := range names {
+		rows[i] = spinner + " " + text.Render(clip(i18n.T("list.incoming", name), logsPanelWidth-4))
+	}
+	return rows
+}
@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"gw2-cmd-watch/i18n"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyBinding is a key, or keys doing the same thing, with its description. The key
+// handlers match against bindings and the help bar and ? overlay list them, so the
+// help always shows what the keys do.
+type keyBinding struct {
+	keys []string // As tea.KeyMsg.String() reports them
+	help string   // i18n key of the description
+	hint bool     // Also shown in the help bar
+}
+
+func (b keyBinding) matches(msg tea.KeyMsg) bool {
+	return slices.Contains(b.keys, msg.String())
+}
+
+// label is how the keys are written in the help.
+func (b keyBinding) label() string {
+	names := make([]string, len(b.keys))
+	for i, k := range b.keys {
+		switch k {
+		case " ":
+			k = "space"
+		case "up":
+			k = "↑"
+		case "down":
+			k = "↓"
+		case "left":
+			k = "←"
+		case "right":
+			k = "→"
+		}
+		names[i] = k
+	}
+	return strings.Join(names, "/")
+}
+
+// Bindings shared by several contexts.
+var (
+	keyQuit     = keyBinding{keys: []string{"q", "ctrl+c"}, help: "key.quit"}
+	keyHelp     = keyBinding{keys: []string{"?"}, help: "key.help", hint: true}
+	keyUp       = keyBinding{keys: []string{"w", "up", "k"}, help: "key.up"}
+	keyDown     = keyBinding{keys: []string{"s", "down", "j"}, help: "key.down"}
+	keyPageUp   = keyBinding{keys: []string{"pgup"}, help: "key.page_up"}
+	keyPageDown = keyBinding{keys: []string{"pgdown"}, help: "key.page_down"}
+	keyHome     = keyBinding{keys: []string{"home"}, help: "key.home"}
+	keyEnd      = keyBinding{keys: []string{"end"}, help: "key.end"}
+	keyFolder   = keyBinding{keys: []string{"f"}, help: "key.folder"}
+	keyJSON     = keyBinding{keys: []string{"J"}, help: "key.json", hint: true}
+	keyReport   = keyBinding{keys: []string{"o"}, help: "key.report", hint: true}
+)
+
+// Keys that work everywhere outside the overlays.
+var (
+	keyEventLog     = keyBinding{keys: []string{"e"}, help: "key.event_log", hint: true}
+	keyDiagnostics  = keyBinding{keys: []string{"i"}, help: "key.diagnostics"}
+	keyAnonymize    = keyBinding{keys: []string{"n"}, help: "key.anonymize"}
+	keyNameDisplay  = keyBinding{keys: []string{"A"}, help: "key.name_display"}
+	keyCancelParse  = keyBinding{keys: []string{"ctrl+x"}, help: "key.cancel_parse"}
+	keyTroubleshoot = keyBinding{keys: []string{"H"}, help: "key.troubleshoot"}
+	keyFixEIConf    = keyBinding{keys: []string{"F"}, help: "key.fix_eiconf"}
+)
+
+// Left panel keys, in the run list and a run's log list.
+var (
+	keyFocusRight  = keyBinding{keys: []string{"d", "right", "l"}, help: "key.focus_right"}
+	keySelect      = keyBinding{keys: []string{"enter", " "}, help: "key.select", hint: true}
+	keyDeleteRun   = keyBinding{keys: []string{"ctrl+d"}, help: "key.delete_run", hint: true}
+	keyDeleteLog   = keyBinding{keys: []string{"ctrl+d"}, help: "key.delete_log", hint: true}
+	keySort        = keyBinding{keys: []string{"o"}, help: "key.sort", hint: true}
+	keyReverseSort = keyBinding{keys: []string{"O"}, help: "key.reverse_sort"}
+	keyGroupRuns   = keyBinding{keys: []string{"g"}, help: "key.group_runs", hint: true}
+	keyPeriod      = keyBinding{keys: []string{"r"}, help: "key.period_report", hint: true}
+	keyGvG         = keyBinding{keys: []string{"g"}, help: "key.gvg", hint: true}
+	keyBenchmark   = keyBinding{keys: []string{"b"}, help: "key.benchmark"}
+	keyReparse     = keyBinding{keys: []string{"R"}, help: "key.reparse"}
+)
+
+// Report dashboard keys.
+var (
+	keyFocusLeft  = keyBinding{keys: []string{"a", "left", "h"}, help: "key.focus_left", hint: true}
+	keyCardUp     = keyBinding{keys: []string{"w", "up", "k"}, help: "key.card_up"}
+	keyCardDown   = keyBinding{keys: []string{"s", "down", "j"}, help: "key.card_down"}
+	keyZoom       = keyBinding{keys: []string{"enter", " "}, help: "key.zoom", hint: true}
+	keyArrange    = keyBinding{keys: []string{"m"}, help: "key.arrange", hint: true}
+	keyPhase      = keyBinding{keys: []string{"p"}, help: "key.phase", hint: true}
+	keyCommander  = keyBinding{keys: []string{"c"}, help: "key.commander", hint: true}
+	keyNPCDamage  = keyBinding{keys: []string{"v"}, help: "key.npc_damage"}
+	keyReplay     = keyBinding{keys: []string{"t"}, help: "key.replay", hint: true}
+	keyPositions  = keyBinding{keys: []string{"X"}, help: "key.export_positions"}
+	keyRenameRun  = keyBinding{keys: []string{"R"}, help: "key.rename_run"}
+	keyUnzoom     = keyBinding{keys: []string{"esc", "enter", " ", "a", "left", "h"}, help: "key.unzoom", hint: true}
+	keyArrangeEnd = keyBinding{keys: []string{"m", "esc", "enter"}, help: "key.arrange_done", hint: true}
+	keyMoveUp     = keyBinding{keys: []string{"w", "up", "k"}, help: "key.move_earlier", hint: true}
+	keyMoveDown   = keyBinding{keys: []string{"s", "down", "j"}, help: "key.move_later", hint: true}
+	keyMovePrev   = keyBinding{keys: []string{"a", "left", "h"}, help: "key.move_prev_row", hint: true}
+	keyMoveNext   = keyBinding{keys: []string{"d", "right", "l"}, help: "key.move_next_row", hint: true}
+	keyHideCard   = keyBinding{keys: []string{"x"}, help: "key.hide_card", hint: true}
+	keyUnhideCard = keyBinding{keys: []string{"u"}, help: "key.unhide_card", hint: true}
+	keyResetCards = keyBinding{keys: []string{"r"}, help: "key.reset_layout"}
+)
+
+// Replay scrubber keys.
+var (
+	keyStepBack    = keyBinding{keys: []string{"a", "left", "h"}, help: "key.step_back", hint: true}
+	keyStepForward = keyBinding{keys: []string{"d", "right", "l"}, help: "key.step_forward", hint: true}
+	keyJumpBack    = keyBinding{keys: []string{"pgup", "shift+left"}, help: "key.jump_back"}
+	keyJumpForward = keyBinding{keys: []string{"pgdown", "shift+right"}, help: "key.jump_forward"}
+	keyCloseReplay = keyBinding{keys: []string{"t", "esc"}, help: "key.close", hint: true}
+)
+
+// Overlay keys.
+var (
+	keyCloseEventLog    = keyBinding{keys: []string{"e", "esc"}, help: "key.close", hint: true}
+	keyCloseDiagnostics = keyBinding{keys: []string{"i", "esc"}, help: "key.close", hint: true}
+	keyCloseHelp        = keyBinding{keys: []string{"?", "esc"}, help: "key.close", hint: true}
+	keyOldest           = keyBinding{keys: []string{"home"}, help: "key.oldest"}
+	keyNewest           = keyBinding{keys: []string{"end"}, help: "key.newest"}
+)
+
+// Dialog keys. Only an explicit y or choosing the confirm button says yes. Dialogs
+// asking for a code take letters as the code, so only Esc cancels them.
+var (
+	keyDialogSwitch = keyBinding{keys: []string{"tab", "shift+tab", "left", "right"}, help: "key.dialog_switch", hint: true}
+	keyDialogChoose = keyBinding{keys: []string{"enter", " "}, help: "key.dialog_choose", hint: true}
+	keyDialogYes    = keyBinding{keys: []string{"y", "Y"}, help: "key.dialog_yes", hint: true}
+	keyDialogNo     = keyBinding{keys: []string{"n", "N", "esc"}, help: "key.dialog_no", hint: true}
+	keyDialogEsc    = keyBinding{keys: []string{"esc"}, help: "key.dialog_no", hint: true}
+)
+
+// keyContext is a set of bindings active together, e.g. in the run list.
+type keyContext struct {
+	title    string // i18n key
+	bindings []keyBinding
+}
+
+var globalKeys = keyContext{"keys.global", []keyBinding{keyHelp, keyEventLog, keyDiagnostics, keyAnonymize,
+	keyNameDisplay, keyCancelParse, keyTroubleshoot, keyFixEIConf, keyQuit}}
+
+var listKeys = []keyBinding{keyUp, keyDown, keyPageUp, keyPageDown, keyHome, keyEnd, keySelect, keyFocusRight}
+
+// activeKeys is the context of the keys the user can press now. Outside the overlays
+// the global keys work as well, and quitting works everywhere.
+func (m *model) activeKeys() keyContext {
+	switch {
+	case m.dialog != nil && m.dialog.token != "":
+		return keyContext{"keys.dialog", []keyBinding{keyDialogSwitch, keyDialogChoose, keyDialogEsc}}
+	case m.dialog != nil:
+		return keyContext{"keys.dialog", []keyBinding{keyDialogSwitch, keyDialogChoose, keyDialogYes, keyDialogNo}}
+	case m.showHelp:
+		return keyContext{"keys.help", []keyBinding{keyCloseHelp}}
+	case m.showEventLog:
+		return keyContext{"keys.event_log", []keyBinding{keyUp, keyDown, keyPageUp, keyPageDown, keyOldest, keyNewest,
+			keyCloseEventLog, keyQuit}}
+	case m.showDiagnostics:
+		return keyContext{"keys.diagnostics", []keyBinding{keyCloseDiagnostics, keyQuit}}
+	case m.zoomed:
+		return keyContext{"keys.zoom", []keyBinding{keyUp, keyDown, keyPageUp, keyPageDown, keyHome, keyEnd,
+			keyUnzoom, keyReport, keyJSON}}
+	case m.replaying:
+		return keyContext{"keys.replay", []keyBinding{keyStepBack, keyStepForward, keyJumpBack, keyJumpForward,
+			keyHome, keyEnd, keyCloseReplay, keyQuit}}
+	case m.arranging:
+		return keyContext{"keys.arrange", []keyBinding{keyMoveUp, keyMoveDown, keyMovePrev, keyMoveNext,
+			keyHideCard, keyUnhideCard, keyResetCards, keyArrangeEnd}}
+	case m.focusedPanel == rightPanel:
+		return keyContext{"keys.dashboard", []keyBinding{keyFocusLeft, keyCardUp, keyCardDown, keyZoom, keyReport,
+			keyJSON, keyFolder, keyArrange, keyPhase, keyCommander, keyNPCDamage, keyReplay, keyPositions, keyRenameRun}}
+	case m.viewMode == logsView:
+		return keyContext{"keys.logs", append(slices.Clone(listKeys), keyDeleteLog, keySort, keyReverseSort, keyGvG,
+			keyBenchmark, keyReparse, keyFolder, keyJSON)}
+	}
+	return keyContext{"keys.runs", append(slices.Clone(listKeys), keyDeleteRun, keySort, keyReverseSort,
+		keyGroupRuns, keyPeriod, keyFolder)}
+}
+
+// hints formats bindings for the help bar.
+func hints(bindings []keyBinding) string {
+	var parts []string
+	for _, b := range bindings {
+		parts = append(parts, b.label()+": "+i18n.T(b.help))
+	}
+	return strings.Join(parts, " • ")
+}
+
+func (m *model) renderHelpBar() string {
+	if m.dialog != nil {
+		// The dialog takes every key
+		return lipgloss.JoinVertical(lipgloss.Left, "", m.styles.HelpBar.Render(hints(m.activeKeys().bindings)))
+	}
+	global := []keyBinding{keyHelp}
+	if len(m.parses) > 0 {
+		global = append(global, keyCancelParse)
+	}
+	if remedy(m.err) != "" {
+		global = append(global, keyTroubleshoot)
+	}
+	if len(m.eiConfIssues) > 0 {
+		global = append(global, keyFixEIConf)
+	}
+	global = append(global, keyQuit)
+	var context []keyBinding
+	for _, b := range m.activeKeys().bindings {
+		if b.hint {
+			context = append(context, b)
+		}
+	}
+	width := m.width - m.styles.HelpBar.GetHorizontalFrameSize()
+	return lipgloss.JoinVertical(lipgloss.Left, m.styles.HelpBar.Render(clip(hints(global), width)),
+		m.styles.HelpBar.Render(clip(hints(context), width)))
+}
+
+func (m model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case keyQuit.matches(msg):
+		return m, tea.Quit
+	case keyCloseHelp.matches(msg):
+		m.showHelp = false
+	}
+	return m, nil
+}
+
+// renderHelpView lists every key of the screen the overlay was opened on, next to the
+// keys that work everywhere.
+func (m *model) renderHelpView() string {
+	height := max(m.height-4, 3) // status bar, two help lines and the border
+	m.showHelp = false
+	contexts := []keyContext{m.activeKeys()}
+	if !m.showEventLog && !m.showDiagnostics {
+		contexts = append(contexts, globalKeys)
+	}
+	m.showHelp = true
+
+	var columns []string
+	for _, c := range contexts {
+		var sb strings.Builder
+		sb.WriteString(m.styles.CardTitle.Render(i18n.T(c.title)) + "\n")
+		labelWidth := 0
+		for _, b := range c.bindings {
+			labelWidth = max(labelWidth, lipgloss.Width(b.label()))
+		}
+		for i, b := range c.bindings {
+			label := b.label() + strings.Repeat(" ", labelWidth-lipgloss.Width(b.label()))
+			m.writeRow(&sb, i, label+"  "+i18n.T(b.help))
+		}
+		columns = append(columns, lipgloss.NewStyle().PaddingRight(4).Render(sb.String()))
+	}
+	return m.styles.RightPanel.Copy().
+		Width(m.width - m.styles.RightPanel.GetHorizontalFrameSize()).
+		Height(height).
+		BorderForeground(m.colors.Highlight).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+}
@@ -0,0 +1,277 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/config"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cardDef describes a dashboard card that can be placed in the layout.
+type cardDef struct {
+	ID    string
+	Title string
+	Build func(m *model, log *parser.ParsedLog) string
+}
+
+// cardRegistry lists every card the right panel can show. IDs are what users put in
+// card_layout in config.json.
+var cardRegistry = []cardDef{
+	{ID: "summary", Title: "Fight Balance", Build: (*model).buildSummaryCard},
+	{ID: "banner", Title: "Location / Duration", Build: (*model).buildBannerInfoCard},
+	{ID: "damage", Title: "Damage Top 5", Build: (*model).buildDamageCard},
+	{ID: "downs", Title: "Downs Top 5", Build: (*model).buildDownContributionCard},
+	{ID: "cleanses", Title: "Cleanses", Build: (*model).buildCleansesCard},
+	{ID: "strips", Title: "Boon Strips", Build: (*model).buildStripsCard},
+	{ID: "deaths", Title: "First 5 To Die", Build: (*model).buildDeathCard},
+	{ID: "healing", Title: "Healing Top 5", Build: (*model).buildHealingCard},
+	{ID: "barrier", Title: "Barrier Top 5", Build: (*model).buildBarrierCard},
+	{ID: "wipe", Title: "What Killed Us", Build: (*model).buildWipeCard},
+	{ID: "enemies", Title: "Enemy Pressure", Build: (*model).buildEnemyPressureCard},
+	{ID: "downstate", Title: "Downstate", Build: (*model).buildDownstateCard},
+	{ID: "stripped", Title: "Stripped Most", Build: (*model).buildIncomingStripsCard},
+	{ID: "modifiers", Title: "Dmg Modifiers", Build: (*model).buildModifiersCard},
+	{ID: "boss", Title: "Boss HP", Build: (*model).buildBossCard},
+	{ID: "phasetimes", Title: "Phase Times", Build: (*model).buildPhaseTimesCard},
+	{ID: "mechanics", Title: "Mechanics", Build: (*model).buildMechanicsCard},
+	{ID: "groupdps", Title: "Group DPS", Build: (*model).buildGroupDpsCard},
+	{ID: "objectives", Title: "Objective Flips", Build: (*model).buildObjectivesCard},
+	{ID: "trends", Title: "Player Trends", Build: (*model).buildTrendsCard},
+	{ID: "spread", Title: "Damage Spread", Build: (*model).buildSpreadCard},
+	{ID: "focus", Title: "Target Focus", Build: (*model).buildFocusCard},
+	{ID: "burst", Title: "Burst Windows", Build: (*model).buildBurstCard},
+	{ID: "downtimeline", Title: "Downs Over Time", Build: (*model).buildDownsTimelineCard},
+	{ID: "firstpush", Title: "First Push", Build: (*model).buildFirstPushCard},
+	{ID: "subgroups", Title: "Subgroups", Build: (*model).buildSubgroupsCard},
+	{ID: "rallybot", Title: "Rallybots", Build: (*model).buildRallybotCard},
+	{ID: "finishers", Title: "Finishers", Build: (*model).buildFinishersCard},
+}
+
+// DefaultCardLayout is the original hardcoded dashboard arrangement.
+func DefaultCardLayout() [][]string {
+	return [][]string{
+		{"summary", "banner"},
+		{"damage", "downs"},
+		{"cleanses", "strips", "deaths"},
+		{"healing", "barrier"},
+	}
+}
+
+func findCard(id string) (cardDef, bool) {
+	for _, c := range cardRegistry {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return cardDef{}, false
+}
+
+// cardLayout is the ordered rows of card IDs shown in the right panel.
+type cardLayout [][]string
+
+// newCardLayout validates a configured layout. Unknown and duplicate IDs are dropped,
+// empty rows removed, and an empty result falls back to def.
+func newCardLayout(rows [][]string, def [][]string) (cardLayout, []string) {
+	var warnings []string
+	seen := make(map[string]bool)
+	var layout cardLayout
+	for _, row := range rows {
+		var clean []string
+		for _, id := range row {
+			id = strings.ToLower(strings.TrimSpace(id))
+			if _, ok := findCard(id); !ok {
+				warnings = append(warnings, fmt.Sprintf("unknown card %q in card_layout", id))
+				continue
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			clean = append(clean, id)
+		}
+		if len(clean) > 0 {
+			layout = append(layout, clean)
+		}
+	}
+	if len(layout) == 0 {
+		layout = def
+	}
+	return layout, warnings
+}
+
+// pveMode reports whether the dashboard is showing a raid, strike or fractal log.
+func (m *model) pveMode() bool {
+	log := m.selectedLog()
+	return log != nil && !analysis.IsWvW(log)
+}
+
+// activeLayout is the WvW or PvE layout, depending on the selected log.
+func (m *model) activeLayout() cardLayout {
+	if m.pveMode() {
+		return m.pveLayout
+	}
+	return m.cardLayout
+}
+
+// flat returns the card IDs in reading order, which is also the navigation order.
+func (l cardLayout) flat() []string {
+	var ids []string
+	for _, row := range l {
+		ids = append(ids, row...)
+	}
+	return ids
+}
+
+// position returns the row and column of the n-th card in reading order.
+func (l cardLayout) position(n int) (int, int) {
+	for r, row := range l {
+		if n < len(row) {
+			return r, n
+		}
+		n -= len(row)
+	}
+	return -1, -1
+}
+
+// index is the inverse of position.
+func (l cardLayout) index(row, col int) int {
+	n := 0
+	for r := 0; r < row; r++ {
+		n += len(l[r])
+	}
+	return n + col
+}
+
+func (l cardLayout) clone() cardLayout {
+	c := make(cardLayout, len(l))
+	for i, row := range l {
+		c[i] = append([]string(nil), row...)
+	}
+	return c
+}
+
+// hidden returns registered cards that are not in the layout.
+func (l cardLayout) hidden() []string {
+	shown := make(map[string]bool)
+	for _, id := range l.flat() {
+		shown[id] = true
+	}
+	var ids []string
+	for _, c := range cardRegistry {
+		if !shown[c.ID] {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// compact removes empty rows.
+func (l cardLayout) compact() cardLayout {
+	var out cardLayout
+	for _, row := range l {
+		if len(row) > 0 {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// --- Arrange mode ---
+
+// handleArrangeKeys edits the card layout in place. The selected card is moved rather
+// than the selection.
+func (m model) handleArrangeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	layout := m.activeLayout().clone()
+	row, col := layout.position(m.selectedCard)
+
+	switch {
+	case keyQuit.matches(msg):
+		return m, tea.Quit
+	case keyArrangeEnd.matches(msg):
+		m.arranging = false
+		m.setStatus(i18n.T("status.layout_saved"))
+		return m, m.saveCardLayout()
+	case keyMoveUp.matches(msg):
+		// Move one place earlier in reading order
+		if col > 0 {
+			layout[row][col-1], layout[row][col] = layout[row][col], layout[row][col-1]
+			m.selectedCard--
+		}
+	case keyMoveDown.matches(msg):
+		if row >= 0 && col < len(layout[row])-1 {
+			layout[row][col+1], layout[row][col] = layout[row][col], layout[row][col+1]
+			m.selectedCard++
+		}
+	case keyMovePrev.matches(msg):
+		// Move to the end of the previous row
+		if row > 0 {
+			id := layout[row][col]
+			layout[row] = append(layout[row][:col:col], layout[row][col+1:]...)
+			layout[row-1] = append(layout[row-1], id)
+			newRow := row - 1
+			newCol := len(layout[newRow]) - 1
+			layout = layout.compact()
+			m.selectedCard = layout.index(newRow, newCol)
+		}
+	case keyMoveNext.matches(msg):
+		// Move to the start of the next row, creating one if needed
+		if row >= 0 {
+			id := layout[row][col]
+			layout[row] = append(layout[row][:col:col], layout[row][col+1:]...)
+			if row == len(layout)-1 {
+				layout = append(layout, []string{})
+			}
+			layout[row+1] = append([]string{id}, layout[row+1]...)
+			newRow := row + 1
+			if len(layout[row]) == 0 {
+				newRow = row
+			}
+			layout = layout.compact()
+			m.selectedCard = layout.index(newRow, 0)
+		}
+	case keyHideCard.matches(msg):
+		// Hide the selected card, keeping at least one visible
+		if row >= 0 && len(layout.flat()) > 1 {
+			layout[row] = append(layout[row][:col:col], layout[row][col+1:]...)
+			layout = layout.compact()
+			if m.selectedCard >= len(layout.flat()) {
+				m.selectedCard = len(layout.flat()) - 1
+			}
+		}
+	case keyUnhideCard.matches(msg):
+		// Unhide the first hidden card as a new last row
+		if hidden := layout.hidden(); len(hidden) > 0 {
+			layout = append(layout, []string{hidden[0]})
+			m.selectedCard = len(layout.flat()) - 1
+		}
+	case keyResetCards.matches(msg):
+		layout = DefaultCardLayout()
+		if m.pveMode() {
+			layout = DefaultPvECardLayout()
+		}
+		m.selectedCard = 0
+	}
+	if m.pveMode() {
+		m.pveLayout = layout
+	} else {
+		m.cardLayout = layout
+	}
+	return m, nil
+}
+
+// saveCardLayout persists the current layouts to config.json.
+func (m *model) saveCardLayout() tea.Cmd {
+	m.config.CardLayout = m.cardLayout.clone()
+	m.config.PvECardLayout = m.pveLayout.clone()
+	cfg := m.config
+	return func() tea.Msg {
+		if err := config.SaveConfig(config.DefaultPath, &cfg); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save card layout: %w", err)}
+		}
+		return nil
+	}
+}
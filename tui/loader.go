@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"os"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// maxLoadedLogs bounds how many fully parsed fights are kept in memory. The list
+	// and run overview only need summaries; others are parsed again when selected.
+	maxLoadedLogs = 20
+	// backgroundParsers is how many fights without a cached summary are parsed at
+	// once while a run loads. The selected fight is parsed on top of them.
+	backgroundParsers = 2
+)
+
+// LogParseFailedMsg reports a fight JSON that could not be parsed.
+type LogParseFailedMsg struct {
+	FullPath string
+	Err      error
+}
+
+// runFilesMsg starts loading a run: the fights with a current summary in the run's
+// index, and the fights being parsed because they have none.
+type runFilesMsg struct {
+	RunPath string
+	Cached  map[string]analysis.FightSummary // By full path
+	Parsing []string
+}
+
+// handleRunFiles lists the fights of a run and starts parsing the selected fight and,
+// in the background, the fights with no cached summary, oldest first. Fights show
+// their name until parsed.
+func (m *model) handleRunFiles(msg runFilesMsg) tea.Cmd {
+	if msg.RunPath != m.currentRunPath {
+		return nil
+	}
+	for path, s := range msg.Cached {
+		processor.DisplaySummary(&s)
+		m.summaries[path] = s
+		m.logFullPaths[processor.DisplayName(path)] = path
+	}
+	for _, path := range msg.Parsing {
+		m.logFullPaths[processor.DisplayName(path)] = path
+	}
+	for name := range m.logFullPaths {
+		m.logList = append(m.logList, name)
+	}
+	m.sortLogList()
+	if len(m.logList) > 0 {
+		m.selectedIndex = 1 // Select the first log
+	}
+	for i, name := range m.logList {
+		if name == m.pendingSelectLog {
+			m.selectedIndex = i + 1
+			break
+		}
+	}
+	m.pendingSelectLog = ""
+	m.parseQueue = append([]string(nil), msg.Parsing...)
+	sort.Slice(m.parseQueue, func(i, j int) bool {
+		return processor.DisplayName(m.parseQueue[i]) < processor.DisplayName(m.parseQueue[j])
+	})
+	m.runLoading = true
+	return tea.Batch(m.loadSelectedLog(), m.nextParses())
+}
+
+// nextParses starts background parses until backgroundParsers are running. Once the
+// queue is empty and the last one is done, the run has finished loading.
+func (m *model) nextParses() tea.Cmd {
+	var cmds []tea.Cmd
+	for len(m.parsing) < backgroundParsers && len(m.parseQueue) > 0 {
+		path := m.parseQueue[0]
+		m.parseQueue = m.parseQueue[1:]
+		m.parsing[path] = true
+		cmds = append(cmds, parseSingleLog(path))
+	}
+	if m.runLoading && len(m.parsing) == 0 {
+		m.runLoading = false
+		cmds = append(cmds, func() tea.Msg { return AllLogsParsedMsg{} })
+	}
+	return tea.Batch(cmds...)
+}
+
+// parseDone clears a finished parse and starts the next one.
+func (m *model) parseDone(path string) tea.Cmd {
+	delete(m.loadingLogs, path)
+	if !m.parsing[path] {
+		return nil
+	}
+	delete(m.parsing, path)
+	return m.nextParses()
+}
+
+// rememberLog keeps a parsed fight in memory, forgetting the least recently used one
+// other than the selected fight when there are too many.
+func (m *model) rememberLog(path string, log *parser.ParsedLog) {
+	m.logs[path] = log
+	m.touchLog(path)
+	selected := m.selectedLogPath()
+	for i := 0; len(m.logs) > maxLoadedLogs && i < len(m.logOrder); {
+		if oldest := m.logOrder[i]; oldest != selected && oldest != path {
+			delete(m.logs, oldest)
+			m.logOrder = append(m.logOrder[:i], m.logOrder[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+// touchLog marks a fight as just used.
+func (m *model) touchLog(path string) {
+	for i, p := range m.logOrder {
+		if p == path {
+			m.logOrder = append(m.logOrder[:i], m.logOrder[i+1:]...)
+			break
+		}
+	}
+	m.logOrder = append(m.logOrder, path)
+}
+
+// selectedLogPath is the full path of the fight highlighted in the log list.
+func (m *model) selectedLogPath() string {
+	if m.viewMode != logsView || m.selectedIndex == 0 || m.selectedIndex > len(m.logList) {
+		return ""
+	}
+	return m.logFullPaths[m.logList[m.selectedIndex-1]]
+}
+
+// loadSelectedLog parses the highlighted fight if it is not in memory, ahead of the
+// background parses.
+func (m *model) loadSelectedLog() tea.Cmd {
+	path := m.selectedLogPath()
+	if path == "" {
+		return nil
+	}
+	if _, ok := m.logs[path]; ok {
+		m.touchLog(path)
+		return nil
+	}
+	if _, broken := m.brokenLogs[path]; broken || m.loadingLogs[path] || m.parsing[path] {
+		return nil
+	}
+	m.removeQueued(path)
+	m.loadingLogs[path] = true
+	return parseSingleLog(path)
+}
+
+// removeQueued takes a fight off the background parse queue.
+func (m *model) removeQueued(path string) {
+	for i, queued := range m.parseQueue {
+		if queued == path {
+			m.parseQueue = append(m.parseQueue[:i], m.parseQueue[i+1:]...)
+			return
+		}
+	}
+}
+
+// indexSummary queues a fight's summary for the run index. Summaries of anonymized
+// logs are left out, as the index keeps real names.
+func (m *model) indexSummary(path string, s analysis.FightSummary) {
+	if parser.Anonymizing() {
+		return
+	}
+	if m.pendingIndex == nil {
+		m.pendingIndex = make(map[string]analysis.FightSummary)
+	}
+	m.pendingIndex[path] = s
+}
+
+// saveIndex writes the queued summaries to the current run's index.
+func (m *model) saveIndex() tea.Cmd {
+	if len(m.pendingIndex) == 0 || m.currentRunPath == "" {
+		return nil
+	}
+	runPath, summaries := m.currentRunPath, m.pendingIndex
+	m.pendingIndex = nil
+	return func() tea.Msg {
+		if err := processor.UpdateIndex(runPath, summaries); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save the run index: %w", err)}
+		}
+		return nil
+	}
+}
+
+// readRunFiles lists the fights of a run, splitting them into those with a current
+// summary in the index and those that must be parsed. Online-only files are skipped.
+func readRunFiles(runPath string) (msg runFilesMsg, placeholders int, err error) {
+	files, err := processor.RunLogFiles(runPath)
+	if err != nil {
+		return msg, 0, err
+	}
+	idx := processor.LoadIndex(runPath)
+	msg = runFilesMsg{RunPath: runPath, Cached: make(map[string]analysis.FightSummary)}
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		// Reading an online-only file would stall until the sync client downloads it
+		if processor.IsCloudPlaceholder(info) {
+			placeholders++
+			continue
+		}
+		if s, ok := idx.Lookup(info); ok {
+			msg.Cached[path] = s
+			continue
+		}
+		msg.Parsing = append(msg.Parsing, path)
+	}
+	return msg, placeholders, nil
+}
+
+// renderLoadingFight is shown in the right panel while the selected fight is parsed.
+func (m *model) renderLoadingFight() string {
+	title := m.styles.CardTitle.Render(processor.DisplayName(m.selectedLogPath()))
+	return m.styles.RightPanel.Render(title + "\n\n" + i18n.T("dashboard.loading_fight"))
+}
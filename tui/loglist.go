@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Left panel widths. The log list is wider to fit its columns.
+const (
+	runsPanelWidth = 23
+	logsPanelWidth = 32
+)
+
+// logSortKey is the log list column the fights are ordered by.
+type logSortKey int
+
+const (
+	sortByTime logSortKey = iota
+	sortByDuration
+	sortByMap
+	sortBySquad
+	sortByKD
+	sortByResult
+	logSortKeys // Number of sortable columns
+)
+
+// logColumns are the header keys and widths of the log list, in logSortKey order.
+var logColumns = []struct {
+	key   string
+	width int // Negative is left-aligned
+}{
+	{"col.log_time", -5},
+	{"col.log_dur", 5},
+	{"col.log_map", -3},
+	{"col.log_squad", 2},
+	{"col.log_kd", 5},
+	{"col.log_result", -2},
+}
+
+// layoutPanels sizes the left panel for the current list and gives the right panel
+// the rest of the window.
+func (m *model) layoutPanels() {
+	width := runsPanelWidth
+	if m.viewMode == logsView {
+		width = logsPanelWidth
+	}
+	m.styles.LeftPanel = m.styles.LeftPanel.Width(width)
+	m.styles.RightPanel = m.styles.RightPanel.Width(m.width - width - m.styles.LeftPanel.GetHorizontalFrameSize())
+}
+
+// cycleLogSort orders the log list by the next column, or reverses the current order.
+func (m *model) cycleLogSort(reverse bool) {
+	if reverse {
+		m.logSortReversed = !m.logSortReversed
+	} else {
+		m.logSort = (m.logSort + 1) % logSortKeys
+		m.logSortReversed = false
+	}
+	m.sortLogList()
+	m.ensureSelectionVisible()
+}
+
+// sortLogList merges points of view of the same fight and orders the log list by the
+// chosen column, keeping the selected fight selected. Ties, and fights without a
+// summary, stay in time order.
+func (m *model) sortLogList() {
+	selected := ""
+	if m.selectedIndex > 0 && m.selectedIndex <= len(m.logList) {
+		selected = m.logList[m.selectedIndex-1]
+	}
+	m.mergePoVs()
+	selected = m.povPrimary(selected)
+	sort.Strings(m.logList)
+	if m.logSort != sortByTime {
+		sort.SliceStable(m.logList, func(i, j int) bool {
+			a, aok := m.fightSummary(m.logList[i])
+			b, bok := m.fightSummary(m.logList[j])
+			if !aok || !bok {
+				return aok
+			}
+			return m.logLess(m.logList[i], a, m.logList[j], b)
+		})
+	}
+	if m.logSortReversed {
+		for i, j := 0, len(m.logList)-1; i < j; i, j = i+1, j-1 {
+			m.logList[i], m.logList[j] = m.logList[j], m.logList[i]
+		}
+	}
+	for i, name := range m.logList {
+		if name == selected {
+			m.selectedIndex = i + 1
+		}
+	}
+}
+
+// logLess compares two fights by the sort column. Numbers sort biggest first, maps
+// by name and results wins first.
+func (m *model) logLess(aName string, a analysis.FightSummary, bName string, b analysis.FightSummary) bool {
+	switch m.logSort {
+	case sortByDuration:
+		return a.Duration() > b.Duration()
+	case sortByMap:
+		return m.logMap(aName, a) < m.logMap(bName, b)
+	case sortBySquad:
+		return a.SquadCount > b.SquadCount
+	case sortByKD:
+		return killRatio(a) > killRatio(b)
+	case sortByResult:
+		return resultRank(a.Outcome()) < resultRank(b.Outcome())
+	}
+	return false
+}
+
+func killRatio(s analysis.FightSummary) float64 {
+	return float64(s.EnemyDeaths) / float64(max(s.SquadDeaths, 1))
+}
+
+func resultRank(o analysis.Outcome) int {
+	switch o {
+	case analysis.Win:
+		return 0
+	case analysis.Draw:
+		return 1
+	}
+	return 2
+}
+
+// chronologicalLogs returns the fights of the current run in time order, whatever
+// order the list is sorted in.
+func (m *model) chronologicalLogs() []string {
+	names := append([]string(nil), m.logList...)
+	sort.Strings(names)
+	return names
+}
+
+// logMap is the map of a fight: the live map from MumbleLink if it was tagged,
+// otherwise the map in EI's fight name.
+func (m *model) logMap(name string, s analysis.FightSummary) string {
+	if tagged := m.runMeta.FightMaps[name]; tagged != "" {
+		return tagged
+	}
+	return s.Map
+}
+
+// logListHeader is the column header of the log list, with the sort column
+// highlighted and its direction.
+func (m *model) logListHeader() string {
+	var cells []string
+	for i, col := range logColumns {
+		cell := fmt.Sprintf("%*s", col.width, i18n.T(col.key))
+		if logSortKey(i) == m.logSort {
+			cell = lipgloss.NewStyle().Foreground(m.colors.Highlight).Bold(true).Render(cell)
+		}
+		cells = append(cells, cell)
+	}
+	// Durations, squad sizes and K/D start biggest first
+	descending := m.logSort == sortByDuration || m.logSort == sortBySquad || m.logSort == sortByKD
+	arrow := "▲"
+	if descending != m.logSortReversed {
+		arrow = "▼"
+	}
+	return strings.Join(cells, " ") + arrow
+}
+
+// logRow is a fight in the log list: start time, duration, map, squad size, enemy
+// kills/squad deaths and the result. Fights still loading show their name, and broken
+// fights are marked.
+func (m *model) logRow(name string) string {
+	s, ok := m.fightSummary(name)
+	if !ok {
+		if _, broken := m.brokenLogs[m.logFullPaths[name]]; broken {
+			return lipgloss.NewStyle().Foreground(m.colors.Bad).Render(brokenGlyph) + " " + name
+		}
+		return name
+	}
+	start := "--:--"
+	if !s.Start.IsZero() {
+		start = m.locale.In(s.Start).Format("15:04")
+	}
+	duration := "-"
+	if d := s.Duration(); d > 0 {
+		duration = fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	values := []string{start, duration, m.logMap(name, s), fmt.Sprint(s.SquadCount),
+		fmt.Sprintf("%d/%d", s.EnemyDeaths, s.SquadDeaths), m.logFlags(name)}
+	cells := make([]string, len(values))
+	for i, v := range values {
+		w := logColumns[i].width
+		cells[i] = fmt.Sprintf("%*.*s", w, max(w, -w), v)
+	}
+	return strings.Join(cells, " ")
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MapStateMsg reports the player's live map from MumbleLink.
+type MapStateMsg struct {
+	Online bool   // The game is running
+	Map    string // Short WvW map name, empty outside WvW
+	InWvW  bool
+}
+
+// wvwBreak is how long the player may be out of WvW (PvE, guild hall, a short logout)
+// before returning starts a new run.
+const wvwBreak = 30 * time.Minute
+
+// maxMapHistory bounds the map changes kept for tagging queued fights.
+const maxMapHistory = 200
+
+// mapStamp records that the player was on a map from At onwards.
+type mapStamp struct {
+	At  time.Time
+	Map string
+}
+
+// handleMapState starts and ends runs from the player's map changes.
+func (m *model) handleMapState(msg MapStateMsg) tea.Cmd {
+	now := time.Now()
+	m.mapHistory = append(m.mapHistory, mapStamp{At: now, Map: msg.Map})
+	if len(m.mapHistory) > maxMapHistory {
+		m.mapHistory = m.mapHistory[len(m.mapHistory)-maxMapHistory:]
+	}
+
+	var cmd tea.Cmd
+	switch {
+	case !msg.Online:
+		// A disconnect or character swap shouldn't end the run straight away
+		if m.gameOnline {
+			m.offlineSince = now
+			cmd = tea.Tick(logoffGrace, func(time.Time) tea.Msg { return logoffCheckMsg{} })
+		}
+		if m.inWvW {
+			m.leftWvWAt = now
+		}
+	case msg.InWvW && !m.inWvW:
+		if m.leftWvWAt.IsZero() || now.Sub(m.leftWvWAt) > wvwBreak {
+			if m.liveRunPath != "" {
+				cmd = m.closeRun(m.liveRunPath)
+			}
+			m.liveRunPath = ""
+			m.newRunPending = true
+			m.setStatus(i18n.T("status.entered_map", msg.Map))
+		}
+	case !msg.InWvW && m.inWvW:
+		m.leftWvWAt = now
+	}
+	m.inWvW = msg.InWvW && msg.Online
+	m.gameOnline = msg.Online
+	return cmd
+}
+
+// logoffCheckMsg fires logoffGrace after the game went away.
+type logoffCheckMsg struct{}
+
+// logoffGrace is how long the game must stay closed before the live run ends.
+const logoffGrace = 5 * time.Minute
+
+// handleLogoffCheck ends the live run if the game is still closed.
+func (m *model) handleLogoffCheck() tea.Cmd {
+	if m.gameOnline || time.Since(m.offlineSince) < logoffGrace || m.liveRunPath == "" {
+		return nil
+	}
+	cmd := m.closeRun(m.liveRunPath)
+	m.liveRunPath = ""
+	m.newRunPending = true
+	m.setStatus(i18n.T("status.game_closed"))
+	return cmd
+}
+
+// mapAt returns the WvW map the player was on at t, or "" if unknown.
+func (m *model) mapAt(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	current := ""
+	for _, s := range m.mapHistory {
+		if s.At.After(t) {
+			break
+		}
+		current = s.Map
+	}
+	return current
+}
+
+// tagFight records the live map and parse stats of a new fight in run.json, whichever
+// are known.
+func (m *model) tagFight(runPath, displayName, mapName string, stats *processor.ParseStats) tea.Cmd {
+	if mapName == "" && stats == nil {
+		return nil
+	}
+	return m.updateRunMeta(runPath, func(meta *processor.RunMeta) {
+		if mapName != "" {
+			if meta.FightMaps == nil {
+				meta.FightMaps = make(map[string]string)
+			}
+			meta.FightMaps[displayName] = mapName
+		}
+		if stats != nil {
+			if meta.ParseStats == nil {
+				meta.ParseStats = make(map[string]processor.ParseStats)
+			}
+			meta.ParseStats[displayName] = *stats
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/gw2api"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MatchupFetchedMsg carries the WvW matchup looked up when a run started.
+type MatchupFetchedMsg struct {
+	RunPath string
+	Matchup processor.Matchup
+}
+
+// fetchMatchup looks up the current matchup for a new run, if a world or API key is
+// configured.
+func (m *model) fetchMatchup(runPath string) tea.Cmd {
+	worldID, apiKey := m.config.GW2WorldID, m.config.GW2APIKey
+	if worldID == 0 && apiKey == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		home, err := gw2api.HomeWorld(worldID, apiKey)
+		if err != nil {
+			return ErrMsg{Err: fmt.Errorf("matchup lookup failed: %w", err)}
+		}
+		mu, err := gw2api.FetchMatchup(home)
+		if err != nil {
+			return ErrMsg{Err: fmt.Errorf("matchup lookup failed: %w", err)}
+		}
+		return MatchupFetchedMsg{RunPath: runPath, Matchup: mu}
+	}
+}
+
+// prefetchMatchup looks up the matchup at startup when new run names carry its tier,
+// so the first run of the session can be named right away.
+func (m *model) prefetchMatchup() tea.Cmd {
+	if !m.config.RunNameTier {
+		return nil
+	}
+	return m.fetchMatchup("")
+}
+
+// runNameTier is the tier for a new run's name, or 0 if it is not wanted or not known.
+// It comes from the last matchup looked up, usually for the previous run.
+func (m *model) runNameTier() int {
+	if !m.config.RunNameTier || m.matchup == nil {
+		return 0
+	}
+	return m.matchup.Tier()
+}
+
+// applyMatchup stores a fetched matchup in run.json.
+func (m *model) applyMatchup(msg MatchupFetchedMsg) tea.Cmd {
+	mu := msg.Matchup
+	m.matchup = &mu
+	if msg.RunPath == "" {
+		return nil // Looked up at startup
+	}
+	return m.updateRunMeta(msg.RunPath, func(meta *processor.RunMeta) {
+		meta.Matchup = &mu
+	})
+}
+
+// matchupLines is the opponents for the run header, one per line.
+func (m *model) matchupLines() []string {
+	if m.runMeta.Matchup == nil {
+		return nil
+	}
+	var lines []string
+	for _, opp := range m.runMeta.Matchup.Opponents() {
+		lines = append(lines, clip(i18n.T("matchup.vs", opp), m.styles.LeftPanel.GetWidth()-2))
+	}
+	return lines
+}
+
+// renderMatchup is the matchup line of the run overview.
+func (m *model) renderMatchup() string {
+	mu := m.runMeta.Matchup
+	if mu == nil {
+		return ""
+	}
+	return i18n.T("matchup.line", mu.MatchID, mu.Skirmish,
+		mu.Teams[mu.Color], mu.Color, strings.Join(mu.Opponents(), i18n.T("matchup.and")))
+}
@@ -2,14 +2,19 @@ package tui
 
 import (
 	"fmt"
+	"gw2-cmd-watch/analysis"
 	"gw2-cmd-watch/config"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/locale"
+	"gw2-cmd-watch/logger"
 	"gw2-cmd-watch/parser"
 	"gw2-cmd-watch/processor"
+	"gw2-cmd-watch/report"
+	"gw2-cmd-watch/state"
 	"math"
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -19,14 +24,22 @@ import (
 )
 
 // --- Message Types ---
-type TempLogProcessedMsg struct{ TempPath string } // From processor, contains path to temp JSON
-type LogfileArchivedMsg struct {                   // From self, after file is moved
+type TempLogProcessedMsg struct { // From processor, contains path to temp JSON
+	TempPath string
+	LogPath  string // The log EI parsed, for its parse stats
+}
+type LogfileArchivedMsg struct { // From self, after file is moved
 	Log      *parser.ParsedLog
 	FullPath string
+	Map      string // Live map from MumbleLink when the fight started, if known
+	Stats    *processor.ParseStats
 }
 type ErrMsg struct{ Err error }
 type StatusMsg string
-type RunsLoadedMsg struct{ Runs []string }
+type RunsLoadedMsg struct {
+	Runs []string
+	info map[string]runInfo
+}
 
 // New messages for concurrent parsing
 type SingleLogParsedMsg struct {
@@ -34,13 +47,16 @@ type SingleLogParsedMsg struct {
 	FullPath string
 }
 type AllLogsParsedMsg struct{}
+type RunMetaLoadedMsg struct {
+	RunPath string
+	Meta    processor.RunMeta
+}
 
 type UpdateAvailableMsg struct{ URL string }
 
 // --- TUI State Enums ---
 type panel int
 type logListViewMode int
-type confirmationMode int
 
 const (
 	leftPanel panel = iota
@@ -52,61 +68,162 @@ const (
 	logsView
 )
 
-const (
-	confirmDeleteRun confirmationMode = iota
-	confirmDeleteLog
-	confirmAppUpdate
-)
-
 // --- Model ---
 type model struct {
 	width  int
 	height int
 	theme  ShadesOfPurple
+	colors ColorRoles
 	styles Styles
 	config config.Config
+	locale locale.Format // Time zone and number style from the config
 
 	// Data
-	logs         map[string]*parser.ParsedLog // Map full path to parsed log
-	runList      []string                     // List of directory names in Log_Archive
-	logList      []string                     // List of file names in a selected run
-	logFullPaths map[string]string            // Map filename to full path for the current run
+	logs         map[string]*parser.ParsedLog     // Map full path to parsed log, at most maxLoadedLogs
+	logOrder     []string                         // Paths in logs, least recently used first
+	loadingLogs  map[string]bool                  // Paths being parsed because they were selected
+	parsing      map[string]bool                  // Paths being parsed in the background
+	brokenLogs   map[string]error                 // Paths whose JSON failed to parse
+	parseQueue   []string                         // Paths waiting for a background parse
+	runLoading   bool                             // Background parses of the run are not done
+	pendingIndex map[string]analysis.FightSummary // Summaries to save to the run index
+	runList      []string                         // List of directory names in Log_Archive
+	runInfo      map[string]runInfo               // Start, fights and kills per run for sorting
+	logList      []string                         // List of file names in a selected run
+	logFullPaths map[string]string                // Map filename to full path for the current run
+	summaries    map[string]analysis.FightSummary // Map full path to per-fight totals for run views
+	povs         map[string][]string              // Other points of view merged into a logList entry, by its name
 
 	// State
-	viewMode       logListViewMode
-	currentRunPath string
-	currentRunName string
-	selectedIndex  int
-	focusedPanel   panel
-	selectedCard   int
+	viewMode        logListViewMode
+	currentRunPath  string
+	currentRunName  string
+	liveRunPath     string // Run new logs were archived into this session, see OnRunClosed
+	selectedIndex   int
+	listOffset      int // First visible item in the left panel list
+	logSort         logSortKey
+	logSortReversed bool
+	runRows         []runRow // The run list as shown, with group headers
+	runSort         runSortKey
+	runSortReversed bool
+	runGroup        runGroupKey
+	collapsed       map[string]bool // Run list groups showing only their header
+	countingKills   bool
+	runPreviews     map[string]*runPreview // Of highlighted runs, nil while loading
+	focusedPanel    panel
+	selectedCard    int        // Index into cardLayout in reading order
+	cardLayout      cardLayout // Rows of card IDs shown in the right panel
+	pveLayout       cardLayout // Card rows used instead for PvE logs
+	arranging       bool       // In-TUI card arrange mode
+	zoomed          bool       // Selected card fills the right panel
+	zoomOffset      int        // First visible line of the zoomed card
+	showAllRows     bool       // Card builders list every player instead of the top 5
+	phase           int        // EI phase the cards show, 0 is the full fight
+	replaying       bool       // The replay scrubber replaces the cards
+	replaySecond    int        // Second of the fight the replay scrubber shows
+
+	// Session restore
+	restoringRun     bool   // The run from the last session is being loaded
+	pendingSelectLog string // Fight to select once the restored run has loaded
+
+	commanderOverride string             // Account chosen with the commander action for the current run
+	runMeta           processor.RunMeta  // Settings from run.json of the current run
+	matchup           *processor.Matchup // Last matchup looked up, for naming runs
+
+	// Live game state from MumbleLink, see handleMapState
+	gameOnline    bool
+	inWvW         bool
+	offlineSince  time.Time
+	leftWvWAt     time.Time
+	newRunPending bool // The next processed log starts a new run
+	mapHistory    []mapStamp
+
+	// Objective flips polled from the GW2 API for the live run
+	flipSeen  map[string]time.Time // Last flip time seen per objective ID
+	flipSince time.Time            // Flips before this are not recorded
+
+	// Score of the live run for the status bar, see tallyFight
+	tally        liveTally
+	tallyTicking bool // A tally tick is scheduled
+
+	// Period report shown in the runs view, see togglePeriodReport
+	periodReport  *report.Report
+	reportLoading bool
 
 	// Status
-	status           string
-	err              error
-	confirming       bool
-	confirmationType confirmationMode
-	itemToDelete     string // Can be a run path or a log display name
-	updateURL        string // URL for the new app version
+	status       string
+	err          error
+	dialog       *dialog // Open question, nil if none
+	toasts       []toast // Shown, oldest first
+	nextToastID  int
+	eiConfIssues []processor.ConfIssue // ELI3.conf options F would fix
+	parses       []parseProgress       // Running EI processes, oldest first
+	spinnerFrame int
+	spinning     bool              // A spinner tick is scheduled
+	arriving     map[string]string // Run path by display name of fights being archived
+
+	// Event log
+	events       eventLog
+	showEventLog bool
+	warned       map[string]bool // Warnings already shown, see warnOnce
+
+	// Key help overlay
+	showHelp bool
+
+	// Parse diagnostics
+	showDiagnostics bool
+	diagnostics     []parseRecord // nil while loading
 }
 
-func NewModel(cfg config.Config, initialRuns []string) model {
-	theme := NewShadesOfPurple()
-	return model{
+func NewModel(cfg config.Config, initialRuns []string, session state.State) model {
+	theme, themeErr := LoadTheme(cfg.Theme)
+	layout, layoutWarnings := newCardLayout(cfg.CardLayout, DefaultCardLayout())
+	pveLayout, pveWarnings := newCardLayout(cfg.PvECardLayout, DefaultPvECardLayout())
+	layoutWarnings = append(layoutWarnings, pveWarnings...)
+	loc, localeErr := locale.New(cfg.TimeZone, cfg.TimeFormat, cfg.DateFormat, cfg.NumberLocale)
+	loc.Abbreviate = cfg.AbbreviateNumbers
+	m := model{
 		theme:          theme,
+		colors:         theme.Roles(),
 		styles:         NewStyles(theme),
 		config:         cfg,
-		status:         "Select a run or wait for a new one.",
+		locale:         loc,
+		status:         i18n.T("status.idle"),
 		focusedPanel:   leftPanel,
 		viewMode:       runsView,
 		runList:        initialRuns,
+		runInfo:        make(map[string]runInfo),
 		logs:           make(map[string]*parser.ParsedLog),
+		loadingLogs:    make(map[string]bool),
+		parsing:        make(map[string]bool),
+		brokenLogs:     make(map[string]error),
 		logFullPaths:   make(map[string]string),
+		summaries:      make(map[string]analysis.FightSummary),
+		warned:         make(map[string]bool),
+		arriving:       make(map[string]string),
 		currentRunName: "Viewing Run Archives",
+		cardLayout:     layout,
+		pveLayout:      pveLayout,
 	}
+	if themeErr != nil {
+		m.setError(themeErr)
+	}
+	if localeErr != nil {
+		m.events.add(eventWarn, localeErr.Error())
+	}
+	for _, w := range layoutWarnings {
+		m.events.add(eventWarn, w)
+	}
+	m.restoreSession(session)
+	m.buildRunRows()
+	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return loadRuns // Initial command to load runs
+	if m.restoringRun {
+		return tea.Batch(loadRuns, lintEIConf, loadLogsInRun(m.currentRunPath), m.prefetchMatchup())
+	}
+	return tea.Batch(loadRuns, lintEIConf, m.prefetchMatchup()) // Initial commands to load runs
 }
 
 // --- Command Functions ---
@@ -117,53 +234,141 @@ func loadRuns() tea.Msg {
 	if err != nil {
 		if os.IsNotExist(err) {
 			_ = os.MkdirAll(processor.LogArchive, 0755)
-			return StatusMsg("Log_Archive directory created.")
+			return StatusMsg(i18n.T("status.archive_created", processor.LogArchive))
 		}
 		return ErrMsg{Err: err}
 	}
+	info := make(map[string]runInfo)
 	for _, file := range files {
 		if file.IsDir() {
 			runs = append(runs, file.Name())
+			info[file.Name()] = readRunInfo(file.Name())
 		}
 	}
-	sort.Sort(sort.Reverse(sort.StringSlice(runs))) // Sort newest first
-	return RunsLoadedMsg{Runs: runs}
+	return RunsLoadedMsg{Runs: runs, info: info}
 }
 
 func loadLogsInRun(runPath string) tea.Cmd {
 	return func() tea.Msg {
-		files, err := os.ReadDir(runPath)
+		files, placeholders, err := readRunFiles(runPath)
 		if err != nil {
 			return ErrMsg{Err: err}
 		}
-		var cmds []tea.Cmd
-		for _, file := range files {
-			if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
-				fullPath := filepath.Join(runPath, file.Name())
-				cmds = append(cmds, parseSingleLog(fullPath))
-			}
+		// Fights with a summary in the index are listed without parsing them, and the
+		// others are parsed in the background as the run's files are handled
+		cmds := []tea.Cmd{func() tea.Msg { return files }, loadRunMeta(runPath)}
+		if placeholders > 0 {
+			cmds = append(cmds, func() tea.Msg {
+				return ErrMsg{Err: fmt.Errorf("%d logs in this run are online-only and were skipped; make the folder available offline to load them", placeholders)}
+			})
+		}
+		return tea.Batch(cmds...)()
+	}
+}
+
+// updateRunMeta changes run.json of a run and saves it. The current run's settings are
+// changed in memory too. The file is changed through processor.UpdateRunMeta, so saves
+// from commands running at once don't undo each other.
+func (m *model) updateRunMeta(runPath string, change func(meta *processor.RunMeta)) tea.Cmd {
+	if runPath == m.currentRunPath {
+		change(&m.runMeta)
+	}
+	return func() tea.Msg {
+		if err := processor.UpdateRunMeta(runPath, change); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save run settings: %w", err)}
+		}
+		return nil
+	}
+}
+
+func loadRunMeta(runPath string) tea.Cmd {
+	return func() tea.Msg {
+		meta, err := processor.LoadRunMeta(runPath)
+		if err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to load run settings: %w", err)}
 		}
-		return tea.Sequence(tea.Batch(cmds...), func() tea.Msg { return AllLogsParsedMsg{} })()
+		return RunMetaLoadedMsg{RunPath: runPath, Meta: meta}
 	}
 }
 
 func parseSingleLog(path string) tea.Cmd {
 	return func() tea.Msg {
 		parsedLog, err := parser.ParseLog(path)
+		// A sync client may hold the file briefly on shared archives
+		for retry := 0; retry < 5 && processor.IsSharingViolation(err); retry++ {
+			time.Sleep(500 * time.Millisecond)
+			parsedLog, err = parser.ParseLog(path)
+		}
 		if err != nil {
-			return ErrMsg{Err: fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)}
+			return LogParseFailedMsg{FullPath: path, Err: fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)}
 		}
 		return SingleLogParsedMsg{Log: parsedLog, FullPath: path}
 	}
 }
 
-func archiveLogFile(tempJsonPath, finalRunPath string, log *parser.ParsedLog) tea.Cmd {
+// archiveHooks are called after each fight is archived. They run off the UI thread.
+var archiveHooks []func(jsonPath string, log *parser.ParsedLog)
+
+// OnFightArchived registers fn to be called with every newly archived fight. It must be
+// called before the program starts.
+func OnFightArchived(fn func(jsonPath string, log *parser.ParsedLog)) {
+	archiveHooks = append(archiveHooks, fn)
+}
+
+// logArchivedHooks are called with the arcdps log of each fight archived from the
+// pipeline. They run off the UI thread.
+var logArchivedHooks []func(logPath string)
+
+// OnLogArchived registers fn to be called once the fight of a processed log is archived.
+// It must be called before the program starts.
+func OnLogArchived(fn func(logPath string)) {
+	logArchivedHooks = append(logArchivedHooks, fn)
+}
+
+// runClosedHooks are called when a live run ends, either because a new run started or
+// because the app is closing.
+var runClosedHooks []func(runPath string)
+
+// OnRunClosed registers fn to be called with the folder of each finished run. It must
+// be called before the program starts.
+func OnRunClosed(fn func(runPath string)) {
+	runClosedHooks = append(runClosedHooks, fn)
+}
+
+// closeRun ends a live run: it records when in run.json and runs the OnRunClosed
+// hooks.
+func (m *model) closeRun(runPath string) tea.Cmd {
+	closed := time.Now().UTC()
+	stamp := m.updateRunMeta(runPath, func(meta *processor.RunMeta) {
+		meta.Closed = closed
+	})
+	return tea.Sequence(stamp, func() tea.Msg {
+		for _, fn := range runClosedHooks {
+			fn(runPath)
+		}
+		return nil
+	})
+}
+
+func archiveLogFile(tempJsonPath, logPath, finalRunPath string, log *parser.ParsedLog, mapName string) tea.Cmd {
 	return func() tea.Msg {
 		archivedPath, err := processor.ArchiveLogFiles(tempJsonPath, finalRunPath)
 		if err != nil {
-			return ErrMsg{Err: err}
+			return fightArchiveFailedMsg{Name: processor.DisplayName(tempJsonPath), Err: err}
+		}
+		for _, fn := range archiveHooks {
+			fn(archivedPath, log)
+		}
+		if logPath != "" {
+			for _, fn := range logArchivedHooks {
+				fn(logPath)
+			}
+		}
+		msg := LogfileArchivedMsg{Log: log, FullPath: archivedPath, Map: mapName}
+		if stats, ok := processor.TakeParseStats(logPath, archivedPath); ok {
+			msg.Stats = &stats
 		}
-		return LogfileArchivedMsg{Log: log, FullPath: archivedPath}
+		return msg
 	}
 }
 
@@ -180,10 +385,13 @@ func deleteLogFiles(jsonPath string) tea.Cmd {
 	return func() tea.Msg {
 		htmlPath := strings.Replace(jsonPath, ".json", ".html", 1)
 		if err := os.Remove(jsonPath); err != nil {
-			fmt.Printf("Warning: failed to delete JSON file %s: %v\n", jsonPath, err)
+			logger.Warn("failed to delete JSON file %s: %v", jsonPath, err)
 		}
 		if err := os.Remove(htmlPath); err != nil {
-			fmt.Printf("Warning: failed to delete HTML file %s: %v\n", htmlPath, err)
+			logger.Warn("failed to delete HTML file %s: %v", htmlPath, err)
+		}
+		if err := os.Remove(processor.SummaryPath(jsonPath)); err != nil && !os.IsNotExist(err) {
+			logger.Warn("failed to delete summary of %s: %v", jsonPath, err)
 		}
 		return nil // Fire and forget, no message needed on success
 	}
@@ -191,42 +399,63 @@ func deleteLogFiles(jsonPath string) tea.Cmd {
 
 func (m *model) clearCurrentRun() {
 	m.logs = make(map[string]*parser.ParsedLog)
+	m.logOrder = nil
+	m.loadingLogs = make(map[string]bool)
+	m.parsing = make(map[string]bool)
+	m.brokenLogs = make(map[string]error)
+	m.parseQueue = nil
+	m.runLoading = false
+	m.pendingIndex = nil
 	m.logList = []string{}
+	m.povs = nil
 	m.logFullPaths = make(map[string]string)
+	m.summaries = make(map[string]analysis.FightSummary)
 	m.selectedIndex = 0
+	m.listOffset = 0
 	m.selectedCard = 0
+	m.commanderOverride = ""
+	m.runMeta = processor.RunMeta{}
+	m.phase = 0
+	m.replaying = false
+}
+
+// runTimestamp is the time part of a run folder name, in the configured time zone.
+func (m *model) runTimestamp(t time.Time) string {
+	return m.locale.In(t).Format("2006-01-02_15-04-05")
 }
 
 // --- View Functions ---
 
 func (m model) View() string {
 	if m.width == 0 {
-		return "Initializing..."
-	}
-	if m.confirming {
-		return m.renderConfirmationView()
+		return i18n.T("status.initializing")
 	}
-
+	m.layoutPanels()
 	if m.focusedPanel == leftPanel {
-		m.styles.LeftPanel = m.styles.LeftPanel.BorderForeground(m.theme.AccentCyan)
-		m.styles.RightPanel = m.styles.RightPanel.BorderForeground(m.theme.Gray)
+		m.styles.LeftPanel = m.styles.LeftPanel.BorderForeground(m.colors.Highlight)
+		m.styles.RightPanel = m.styles.RightPanel.BorderForeground(m.colors.Muted)
 	} else {
-		m.styles.LeftPanel = m.styles.LeftPanel.BorderForeground(m.theme.Gray)
-		m.styles.RightPanel = m.styles.RightPanel.BorderForeground(m.theme.AccentCyan)
+		m.styles.LeftPanel = m.styles.LeftPanel.BorderForeground(m.colors.Muted)
+		m.styles.RightPanel = m.styles.RightPanel.BorderForeground(m.colors.Highlight)
 	}
 
-	left := m.renderLeftPanel()
-	right := m.renderRightPanel()
-	statusBar := m.renderStatusBar()
-	helpBar := m.renderHelpBar()
-
-	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
-	return lipgloss.JoinVertical(lipgloss.Left, mainContent, statusBar, helpBar)
-}
-
-func (m *model) renderConfirmationView() string {
-	// The confirmation question is already set in the model's status field.
-	return m.styles.ConfirmationPrompt.Render(m.status)
+	var mainContent string
+	switch {
+	case m.showHelp:
+		mainContent = m.renderHelpView()
+	case m.showEventLog:
+		mainContent = m.renderEventLogView()
+	case m.showDiagnostics:
+		mainContent = m.renderDiagnosticsView()
+	default:
+		mainContent = lipgloss.JoinHorizontal(lipgloss.Top, m.renderLeftPanel(), m.renderRightPanel())
+	}
+	screen := lipgloss.JoinVertical(lipgloss.Left, mainContent, m.renderStatusBar(), m.renderHelpBar())
+	screen = m.renderToasts(screen)
+	if m.dialog != nil {
+		return m.renderDialog(screen)
+	}
+	return screen
 }
 
 func (m *model) renderLeftPanel() string {
@@ -234,28 +463,46 @@ func (m *model) renderLeftPanel() string {
 	if m.viewMode == logsView {
 		items = append(items, "../")
 	} else {
-		items = append(items, "New Run")
+		items = append(items, i18n.T("list.new_run"))
 	}
 
+	var marks map[string]string
 	switch m.viewMode {
 	case runsView:
-		items = append(items, m.runList...)
+		for _, row := range m.runRows {
+			if row.run == "" {
+				items = append(items, runHeader(row, m.collapsed[row.group]))
+			} else {
+				items = append(items, processor.RunLabel(row.run))
+			}
+		}
 	case logsView:
-		items = append(items, m.logList...)
+		// Engagement brackets only make sense in time order
+		if m.logSort == sortByTime && !m.logSortReversed {
+			marks = m.engagementMarks()
+		}
+		for _, name := range m.logList {
+			mark := marks[name]
+			if mark == "" && len(marks) > 0 {
+				mark = "  "
+			}
+			items = append(items, mark+m.logRow(name))
+		}
 	}
 
 	var content strings.Builder
-	title := m.currentRunName
+	content.WriteString(m.styles.CardTitle.Render(m.leftPanelTitle()) + "\n\n")
 	if m.viewMode == logsView {
-		parts := strings.SplitN(m.currentRunName, "_", 2)
-		if len(parts) == 2 {
-			commanderName := strings.Split(parts[0], ".")[0]
-			title = commanderName + "\n" + parts[1]
+		indent := "  "
+		if len(marks) > 0 {
+			indent += "  "
 		}
+		content.WriteString(indent + m.logListHeader() + "\n")
 	}
-	content.WriteString(m.styles.CardTitle.Render(title) + "\n\n")
 
-	for i, item := range items {
+	first, last := m.visibleListRange()
+	for i := first; i <= last && i < len(items); i++ {
+		item := items[i]
 		style := m.styles.ListItem
 		prefix := "  "
 		if i == m.selectedIndex {
@@ -263,20 +510,22 @@ func (m *model) renderLeftPanel() string {
 			prefix = "> "
 		}
 
-		if m.viewMode == runsView && i >= 1 {
-			parts := strings.SplitN(item, "_", 2)
-			if len(parts) == 2 {
-				commanderName := strings.Split(parts[0], ".")[0]
+		if m.viewMode == runsView && i >= 1 && m.runRows[i-1].run == "" {
+			content.WriteString(style.Render(prefix) + m.styles.CardTitle.Render(clip(item, m.styles.LeftPanel.GetWidth()-2)) + "\n")
+		} else if m.viewMode == runsView && i >= 1 {
+			info := m.runInfo[m.runRows[i-1].run]
+			if info.commander != "" {
+				commanderName := strings.Split(processor.CommanderLabel(info.commander), ".")[0]
 				var commanderNameStyle lipgloss.Style
 				if i == m.selectedIndex {
-					commanderNameStyle = lipgloss.NewStyle().Foreground(m.theme.AccentYellowAlt).Bold(true)
+					commanderNameStyle = lipgloss.NewStyle().Foreground(m.colors.CommanderSelected).Bold(true)
 				} else {
-					commanderNameStyle = lipgloss.NewStyle().Foreground(m.theme.AccentOrange)
+					commanderNameStyle = lipgloss.NewStyle().Foreground(m.colors.Commander)
 				}
 				content.WriteString(style.Render(prefix))
 				content.WriteString(commanderNameStyle.Render(commanderName))
 				content.WriteString("\n")
-				line2 := "  " + parts[1]
+				line2 := "  " + clip(m.runSubtitle(info), m.styles.LeftPanel.GetWidth()-4)
 				content.WriteString(style.Render(line2))
 				content.WriteString("\n")
 			} else {
@@ -286,6 +535,14 @@ func (m *model) renderLeftPanel() string {
 			content.WriteString(style.Render(prefix+item) + "\n")
 		}
 	}
+	if last >= len(items)-1 {
+		for _, row := range m.incomingRows() {
+			content.WriteString("  " + row + "\n")
+		}
+	}
+	if first > 0 || last < len(items)-1 {
+		content.WriteString(m.styles.HelpBar.Render(m.listPositionIndicator(first, last, len(items))))
+	}
 	return m.styles.LeftPanel.Render(content.String())
 }
 
@@ -297,76 +554,100 @@ func (m *model) renderRightPanel() string {
 		selectedLog = m.logs[fullPath]
 	}
 
+	var npcHeader string
+	if selectedLog != nil {
+		selectedLog = selectedLog.ForPhase(m.phase)
+		npcHeader = m.npcHeader(selectedLog)
+		if m.config.ExcludeNPCDamage {
+			selectedLog = selectedLog.WithoutNPCDamage()
+		}
+	}
+
+	if selectedLog != nil && m.zoomed {
+		return m.renderZoomedCard(selectedLog)
+	}
+
+	if m.replaying && m.selectedLog() != nil {
+		return m.renderReplay(m.selectedLog())
+	}
+
+	if err, broken := m.brokenLogs[m.selectedLogPath()]; broken && selectedLog == nil {
+		return m.renderBrokenFight(err)
+	}
+
+	if selectedLog == nil && m.selectedLogPath() != "" {
+		return m.renderLoadingFight()
+	}
+
+	if selectedLog == nil && m.viewMode == logsView && len(m.logList) > 0 {
+		return m.styles.RightPanel.Render(m.renderRunOverview())
+	}
+
+	if m.viewMode == runsView && m.periodReport != nil {
+		return m.styles.RightPanel.Render(m.renderPeriodReport())
+	}
+
+	if run := m.selectedRun(); run != "" {
+		return m.renderRunPreview(run)
+	}
+
 	if selectedLog == nil {
-		dashText := `GW2 Commanders Watch - Report Dashboard
-
-No log selected.
-A new run is created or added to when a new log is detected in your arcDPS log folder.
-
-Quick Guide
-
-Move: Use WASD, JK, or Up/Down Arrows.
-D / Right Arrow: Go to Report Dashboard.
-A / Left Arrow: Go back to Log List.
-W/S / Up/Down Arrow: Move selection up and down.
-Select: Press Enter or Spacebar.
-Delete: Ctrl+D for Archives/Logs.
-Zoom: Ctrl+Plus/Minus (requires Windows Terminal).
-Quit: Ctrl+C or Q.
-
-Important Notes
-
-arcDPS Logs: Default location is 
-    (C:\Users\<USERNAME>\Documents\Guild Wars 2\addons\arcdps\arcdps.cbtlogs).
-App Data: GW2 Commanders Watch stores data in Log_Archive next to the executable.
-Detailed Reports: Press D (Report Dashboard), then Enter or Spacebar to open a log in your browser.
-Parser: This app uses the Gw2 Elite Insights Parser 
-    (https://github.com/baaron4/GW2-Elite-Insights-Parser).
-Feedback/Support for GW2 Commanders Watch: 
-    (https://github.com/theextendedname/GW2_Commanders_Watch)
-
-`
+		dashText := i18n.T("dashboard.guide")
 		return m.styles.RightPanel.Render(dashText)
 	}
 
-	bannerCard := m.buildBannerInfoCard(selectedLog)
-	summaryCard := m.buildSummaryCard(selectedLog)
-	damageCard := m.buildDamageCard(selectedLog)
-	downContribCard := m.buildDownContributionCard(selectedLog)
-	cleansesCard := m.buildCleansesCard(selectedLog)
-	stripsCard := m.buildStripsCard(selectedLog)
-	healingCard := m.buildHealingCard(selectedLog)
-	barrierCard := m.buildBarrierCard(selectedLog)
-	deathCard := m.buildDeathCard(selectedLog)
-
-	cardContents := map[int]string{0: summaryCard, 1: bannerCard, 2: damageCard, 3: downContribCard, 4: cleansesCard, 5: stripsCard, 6: deathCard, 7: healingCard, 8: barrierCard}
-	for i, content := range cardContents {
-		style := m.styles.Card
-		if m.focusedPanel == rightPanel && i == m.selectedCard {
-			style = m.styles.SelectedCard
+	var rows []string
+	n := 0
+	for _, row := range m.activeLayout() {
+		var cards []string
+		for _, id := range row {
+			card, _ := findCard(id)
+			style := m.styles.Card
+			if m.focusedPanel == rightPanel && n == m.selectedCard {
+				style = m.styles.SelectedCard
+				if m.arranging {
+					style = style.BorderForeground(m.colors.Warning)
+				}
+			}
+			cards = append(cards, style.Render(card.Build(m, selectedLog)))
+			n++
 		}
-		cardContents[i] = style.Render(content)
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cards...))
 	}
-
-	row1 := lipgloss.JoinHorizontal(lipgloss.Top, cardContents[0], cardContents[1])
-	row2 := lipgloss.JoinHorizontal(lipgloss.Top, cardContents[2], cardContents[3])
-	row3 := lipgloss.JoinHorizontal(lipgloss.Top, cardContents[4], cardContents[5], cardContents[6])
-	row4 := lipgloss.JoinHorizontal(lipgloss.Top, cardContents[7], cardContents[8])
-	finalLayout := lipgloss.JoinVertical(lipgloss.Left, row1, row2, row3, row4)
+	if npcHeader != "" {
+		rows = append([]string{npcHeader}, rows...)
+	}
+	if header := m.povHeader(m.logList[m.selectedIndex-1]); header != "" {
+		rows = append([]string{header}, rows...)
+	}
+	if header := m.phaseHeader(selectedLog); header != "" {
+		rows = append([]string{header}, rows...)
+	}
+	finalLayout := lipgloss.JoinVertical(lipgloss.Left, rows...)
 	return m.styles.RightPanel.Render(finalLayout)
 }
 
 func (m *model) renderStatusBar() string {
 	var statusText string
-	if m.err != nil {
-		statusText = m.styles.ErrorText.Render(fmt.Sprintf("Error: %v", m.err))
+	versionInfo := "v0.1.1" // This should be updated with each new release and remember to change currentVersion in updater.go line 12
+	w := lipgloss.Width
+	versionWidth := w(versionInfo)
+	if fix := remedy(m.err); fix != "" {
+		text := clip(i18n.T("status.error_fix", m.err, fix), m.width-versionWidth-m.styles.StatusBar.GetHorizontalFrameSize()-1)
+		statusText = m.styles.ErrorText.Render(text)
+	} else if m.err != nil {
+		statusText = m.styles.ErrorText.Render(i18n.T("status.error", m.err))
+	} else if len(m.parses) > 0 {
+		statusText = clip(m.parseStatus(), m.width-versionWidth-m.styles.StatusBar.GetHorizontalFrameSize()-1)
 	} else {
 		statusText = m.status
 	}
-	w := lipgloss.Width
+	// The live tally sits next to the version while there is room for it
+	if tally := m.tallyText(); tally != "" && w(statusText)+w(tally)+versionWidth+m.styles.StatusBar.GetHorizontalFrameSize()+4 <= m.width {
+		versionInfo = tally + "  " + versionInfo
+		versionWidth = w(versionInfo)
+	}
 	statusWidth := w(statusText)
-	versionInfo := "v0.1.1" // This should be updated with each new release and remember to change currentVersion in updater.go line 12
-	versionWidth := w(versionInfo)
 	padding := m.width - statusWidth - versionWidth - m.styles.StatusBar.GetHorizontalFrameSize()
 	if padding < 0 {
 		padding = 0
@@ -374,39 +655,6 @@ func (m *model) renderStatusBar() string {
 	return m.styles.StatusBar.Render(lipgloss.JoinHorizontal(lipgloss.Top, statusText, strings.Repeat(" ", padding), versionInfo))
 }
 
-func (m *model) renderHelpBar() string {
-	helpLine1 := "WSAD/Arrows: Navigate • Enter/Space: Select • q: Quit"
-	var helpLine2 string
-	if m.viewMode == logsView {
-		helpLine2 = "ctrl+d: Delete Log • ctrl+plus/minus: Zoom"
-	} else {
-		helpLine2 = "ctrl+d: Delete Run • ctrl+plus/minus: Zoom"
-	}
-	return lipgloss.JoinVertical(lipgloss.Left, m.styles.HelpBar.Render(helpLine1), m.styles.HelpBar.Render(helpLine2))
-}
-
-// formatNumber adds comma separators to an integer.
-func formatNumber(n int) string {
-	in := strconv.Itoa(n)
-	out := make([]byte, len(in)+(len(in)-1)/3)
-	if n < 0 {
-		in = in[1:]
-	}
-	for i, j, k := len(in)-1, len(out)-1, 0; ; i, j = i-1, j-1 {
-		out[j] = in[i]
-		if i == 0 {
-			if n < 0 {
-				return "-" + string(out)
-			}
-			return string(out)
-		}
-		if k++; k == 3 {
-			j, k = j-1, 0
-			out[j] = ','
-		}
-	}
-}
-
 // Card Builder Functions
 // Point represents a 2D coordinate
 type Point struct {
@@ -421,90 +669,52 @@ func CalculateDistance(p1, p2 Point) float64 {
 	return math.Sqrt(dx*dx+dy*dy) * 100 // Scale to match GW2 units
 }
 
-func (m *model) buildBannerInfoCard(log *parser.ParsedLog) string {
-	var location string
-	switch {
-	case strings.HasPrefix(log.FightName, "Detailed WvW - Blue"):
-		location = "BBL"
-	case strings.HasPrefix(log.FightName, "Detailed WvW - Red"):
-		location = "RBL"
-	case strings.HasPrefix(log.FightName, "Detailed WvW - Green"):
-		location = "GBL"
-	case strings.HasPrefix(log.FightName, "Detailed WvW - Eternal"):
-		location = "EBG"
-	default:
-		location = "PvE"
+// fightLocation returns the short map name of a fight: the live map from MumbleLink if
+// it was tagged, otherwise the map in EI's fight name.
+func (m *model) fightLocation(log *parser.ParsedLog) string {
+	if m.selectedIndex > 0 && m.selectedIndex <= len(m.logList) {
+		if tagged := m.runMeta.FightMaps[m.logList[m.selectedIndex-1]]; tagged != "" {
+			return tagged
+		}
 	}
+	return analysis.MapName(log.FightName)
+}
+
+func (m *model) buildBannerInfoCard(log *parser.ParsedLog) string {
+	location := m.fightLocation(log)
 	var startTime string
-	parts := strings.Split(log.TimeStart, " ")
-	if len(parts) > 1 {
+	if start := analysis.Summarize(log).Start; !start.IsZero() {
+		startTime = m.locale.Time(start)
+	} else if parts := strings.Split(log.TimeStart, " "); len(parts) > 1 {
 		startTime = parts[1]
 	}
 	var sb strings.Builder
-	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-9s %-14s %s", "Location", "Duration", "Fight Start")) + "\n")
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-9s %-14s %s", i18n.T("col.location"), i18n.T("col.duration"), i18n.T("col.fight_start"))) + "\n")
 	sb.WriteString(fmt.Sprintf("%-9s %-14s %s", location, log.Duration, startTime))
 	return sb.String()
 }
 
 func (m *model) buildSummaryCard(log *parser.ParsedLog) string {
-	var squadDmg, squadDps, squadDowns, squadDeaths, enemyCount, enemyDmg, enemyDps, enemyDowns, enemyDeaths int
-	var inSquadCount, notInSquadCount, zergCount int
-	for _, p := range log.Players {
-		if p.NotInSquad {
-			notInSquadCount++
-		} else {
-			inSquadCount++
-			if len(p.DpsTargets) > 0 {
-				for _, dpsT := range p.DpsTargets {
-					for _, dpsTarget := range dpsT {
-						squadDps += dpsTarget.Dps
-						squadDmg += dpsTarget.Damage
-					}
-				}
-			}
-			if len(p.Defenses) > 0 {
-				squadDeaths += p.Defenses[0].DeadCount
-				squadDowns += p.Defenses[0].DownCount
-			}
-			if len(p.StatsTargets) > 0 {
-				// Count downs and deaths for enemy players
-				// use StatsTargets
-				//this is the correct way to do it, don't change it
-				for _, ST := range p.StatsTargets {
-					for _, stAry := range ST {
-						enemyDowns += stAry.Downed
-						enemyDeaths += stAry.Killed
-					}
-				}
-			}
-		}
-	}
-
-	zergCount = inSquadCount + notInSquadCount
-	for _, t := range log.Targets {
-		if t.EnemyPlayer && !t.IsFakeTarget {
-			enemyCount++
-			if len(t.StatsAll) > 0 {
-				enemyDmg += t.StatsAll[0].Dmg
-			}
-			if len(t.DpsAll) > 0 {
-				enemyDps += t.DpsAll[0].Dps
-			}
-		}
-	}
+	s := analysis.Summarize(log)
 	var sb strings.Builder
-	rowStr := fmt.Sprintf("%-15s %-12s %-8s %-5s %s ", "Fight Balance", "DMG", "DPS", "Downs", "Deaths")
+	rowStr := fmt.Sprintf("%-15s %-12s %-8s %-5s %s ", i18n.T("card.summary"), i18n.T("col.dmg"), i18n.T("col.dps"), i18n.T("col.downs"), i18n.T("col.deaths"))
 	sb.WriteString(m.styles.CardTitle.Render(rowStr) + "\n")
-	sb.WriteString(fmt.Sprintf("Squad %-2d(%-2d/%-2d) %-12s %-8s %-5s %s", zergCount, inSquadCount, notInSquadCount, formatNumber(squadDmg), formatNumber(squadDps), formatNumber(squadDowns), formatNumber(squadDeaths)) + "\n")
-	sb.WriteString(fmt.Sprintf("Enemy %-9d %-12s %-8s %-5s %s", enemyCount, formatNumber(enemyDmg), formatNumber(enemyDps), formatNumber(enemyDowns), formatNumber(enemyDeaths)))
+	sb.WriteString(fmt.Sprintf("%-5s %-2d(%-2d/%-2d) %-12s %-8s %-5s %s", i18n.T("row.squad"), s.ZergCount(), s.SquadCount, s.NotInSquadCount, m.locale.Number(s.SquadDmg), m.locale.Number(s.SquadDps), m.locale.Number(s.SquadDowns), m.locale.Number(s.SquadDeaths)) + "\n")
+	sb.WriteString(fmt.Sprintf("%-5s %-9d %-12s %-8s %-5s %s", i18n.T("row.enemy"), s.EnemyCount, m.locale.Number(s.EnemyDmg), m.locale.Number(s.EnemyDps), m.locale.Number(s.EnemyDowns), m.locale.Number(s.EnemyDeaths)))
+	if bench := m.renderBenchmark(s); bench != "" {
+		sb.WriteString("\n" + bench)
+	}
 	return sb.String()
 }
 
 func (m *model) buildDamageCard(log *parser.ParsedLog) string {
 	type playerDamage struct {
-		name   string
-		damage int
-		dps    int
+		name         string
+		damage       int
+		dps          int
+		activeDps    int
+		minionDamage int
+		minions      []analysis.MinionShare
 	}
 	var players []playerDamage
 	for _, p := range log.Players {
@@ -518,22 +728,39 @@ func (m *model) buildDamageCard(log *parser.ParsedLog) string {
 				totalDps += dpsTarget.Dps
 			}
 		}
-		players = append(players, playerDamage{name: p.Name, damage: totalDmg, dps: totalDps})
+		minionDmg, minions := analysis.MinionDamage(p)
+		players = append(players, playerDamage{name: p.Name, damage: totalDmg, dps: totalDps, activeDps: analysis.ActiveDps(p, totalDmg), minionDamage: minionDmg, minions: minions})
 	}
 	sort.Slice(players, func(i, j int) bool {
 		return players[i].damage > players[j].damage
 	})
 	var sb strings.Builder
-	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-20s %-10s %s", "Damage Top 5", "T-DMG", "DPS")) + "\n")
+	if m.showAllRows {
+		// Expanded view splits each total into the player's own hits and minion damage
+		sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-10s %-8s %-8s %-10s %s", m.nameWidth(), i18n.T("card.damage_all"), i18n.T("col.tdmg"), i18n.T("col.dps"), i18n.T("col.active_dps"), i18n.T("col.player"), i18n.T("col.minions"))) + "\n")
+	} else {
+		sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-10s %-8s %s", m.nameWidth(), i18n.T("card.damage"), i18n.T("col.tdmg"), i18n.T("col.dps"), i18n.T("col.active_dps"))) + "\n")
+	}
+	roles := analysis.Roles(log)
 	for i, p := range players {
-		if i >= 5 {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
-		rowStr := fmt.Sprintf("%-20s %-10s %s", p.name, formatNumber(p.damage), formatNumber(p.dps))
-		if i%2 != 0 {
-			sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-		} else {
-			sb.WriteString(rowStr + "\n")
+		// Active DPS counts only the seconds the player was alive and present
+		activeDps := "-"
+		if p.activeDps > 0 {
+			activeDps = m.locale.Number(p.activeDps)
+		}
+		rowStr := fmt.Sprintf("%s %-10s %s %s", m.playerCell(log, p.name), m.locale.Number(p.damage), m.goalCell(roles, p.name, statDps, p.dps, 8), activeDps)
+		if m.showAllRows {
+			rowStr = fmt.Sprintf("%s %-10s %s %-8s %-10s %s", m.playerCell(log, p.name), m.locale.Number(p.damage), m.goalCell(roles, p.name, statDps, p.dps, 8), activeDps,
+				m.locale.Number(p.damage-p.minionDamage), m.locale.Number(p.minionDamage))
+		}
+		m.writeRow(&sb, i, rowStr)
+		if m.showAllRows {
+			for _, minion := range p.minions {
+				sb.WriteString(fmt.Sprintf("  %-40s %s\n", minion.Name, m.locale.Number(minion.Damage)))
+			}
 		}
 	}
 	return sb.String()
@@ -565,17 +792,14 @@ func (m *model) buildDownContributionCard(log *parser.ParsedLog) string {
 		return players[i].downCon > players[j].downCon
 	})
 	var sb strings.Builder
-	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-20s %-10s %s", "Downs Top 5", "Down-Cont", "Downs")) + "\n")
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-10s %s", m.nameWidth(), i18n.T("card.downs"), i18n.T("col.down_cont"), i18n.T("col.downs"))) + "\n")
+	roles := analysis.Roles(log)
 	for i, p := range players {
-		if i >= 5 {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
-		rowStr := fmt.Sprintf("%-20s %-10s %s", p.name, formatNumber(p.downCon), formatNumber(p.downs))
-		if i%2 != 0 {
-			sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-		} else {
-			sb.WriteString(rowStr + "\n")
-		}
+		rowStr := fmt.Sprintf("%s %s %s", m.playerCell(log, p.name), m.goalCell(roles, p.name, statDownCont, p.downCon, 10), m.locale.Number(p.downs))
+		m.writeRow(&sb, i, rowStr)
 	}
 	return sb.String()
 }
@@ -607,10 +831,11 @@ func (m *model) buildCleansesCard(log *parser.ParsedLog) string {
 	})
 
 	var sb strings.Builder
-	sb.WriteString(m.styles.CardTitle.Render("Cleanses") + "\n")
+	sb.WriteString(m.styles.CardTitle.Render(i18n.T("card.cleanses")) + "\n")
 
+	roles := analysis.Roles(log)
 	for i, p := range players {
-		if i >= 5 {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
 
@@ -623,12 +848,8 @@ func (m *model) buildCleansesCard(log *parser.ParsedLog) string {
 		totalCondiCleanse := playerCondiCleanse + playerCondiCleanseSelf
 
 		if totalCondiCleanse > 0 { // Only display if totalCondiCleanse is greater than 0
-			rowStr := fmt.Sprintf("%-20s %s", p.Name, formatNumber(totalCondiCleanse))
-			if i%2 != 0 {
-				sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-			} else {
-				sb.WriteString(rowStr + "\n")
-			}
+			rowStr := fmt.Sprintf("%s %s", m.specCell(p.Profession, p.Name), m.goalCell(roles, p.Name, statCleanses, totalCondiCleanse, 0))
+			m.writeRow(&sb, i, rowStr)
 		}
 	}
 	return sb.String()
@@ -648,18 +869,15 @@ func (m *model) buildStripsCard(log *parser.ParsedLog) string {
 		return players[i].Support[0].BoonStrips > players[j].Support[0].BoonStrips
 	})
 	var sb strings.Builder
-	sb.WriteString(m.styles.CardTitle.Render("Boon Strips") + "\n")
+	sb.WriteString(m.styles.CardTitle.Render(i18n.T("card.strips")) + "\n")
+	roles := analysis.Roles(log)
 	for i, p := range players {
-		if i >= 5 {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
 		if len(p.Support) > 0 && p.Support[0].BoonStrips > 0 {
-			rowStr := fmt.Sprintf("%-20s %s", p.Name, formatNumber(p.Support[0].BoonStrips))
-			if i%2 != 0 {
-				sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-			} else {
-				sb.WriteString(rowStr + "\n")
-			}
+			rowStr := fmt.Sprintf("%s %s", m.specCell(p.Profession, p.Name), m.goalCell(roles, p.Name, statStrips, p.Support[0].BoonStrips, 0))
+			m.writeRow(&sb, i, rowStr)
 		}
 	}
 	return sb.String()
@@ -675,13 +893,7 @@ func (m *model) buildDeathCard(log *parser.ParsedLog) string {
 	var deadPlayers []playerDeath
 
 	// Find the commander
-	var commander *parser.Player
-	for i := range log.Players {
-		if log.Players[i].HasCommanderTag {
-			commander = &log.Players[i]
-			break
-		}
-	}
+	commander := m.commanderFor(log)
 
 	pollingRate := log.CombatReplayMetaData.PollingRate
 
@@ -709,8 +921,8 @@ func (m *model) buildDeathCard(log *parser.ParsedLog) string {
 					}
 				}
 			}
-			// Fallback to old value if calculation failed
-			if distToCmd == -1.0 || p.HasCommanderTag {
+			// Fallback to EI's value if calculation failed
+			if (distToCmd == -1.0 || p.HasCommanderTag) && len(p.StatsAll) > 0 {
 				distToCmd = float64(p.StatsAll[0].DistToCommander)
 			}
 
@@ -729,11 +941,11 @@ func (m *model) buildDeathCard(log *parser.ParsedLog) string {
 	})
 
 	var sb strings.Builder
-	title := fmt.Sprintf("%-20s %-11s %-12s %s", "First 5 To Die", "Time(H:m:s)", "DistToTag", "CC")
+	title := fmt.Sprintf("%-*s %-11s %-12s %s", m.nameWidth(), i18n.T("card.deaths"), i18n.T("col.time_hms"), i18n.T("col.dist_to_tag"), i18n.T("col.cc"))
 	sb.WriteString(m.styles.CardTitle.Render(title) + "\n")
 
 	for i, p := range deadPlayers {
-		if i >= 5 {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
 
@@ -755,172 +967,158 @@ func (m *model) buildDeathCard(log *parser.ParsedLog) string {
 			distStr = fmt.Sprintf("%.2f", p.distToCmd)
 		}
 
-		rowStr = fmt.Sprintf("%-20s %-11s %-12s %d", p.name, timeStr, distStr, p.incomingCC)
+		rowStr = fmt.Sprintf("%s %-11s %-12s %d", m.playerCell(log, p.name), timeStr, distStr, p.incomingCC)
 
-		if i%2 != 0 {
-			sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-		} else {
-			sb.WriteString(rowStr + "\n")
-		}
+		m.writeRow(&sb, i, rowStr)
+	}
+	if m.showAllRows {
+		sb.WriteString(m.renderDeathRecaps(log, commander))
 	}
 	return sb.String()
 }
 
-// Refactored buildHealingCard function
+// buildHealingCard ranks healers by healing to squadmates, so self-sustain builds
+// don't outrank squad healers. Self and downed healing are shown alongside.
 func (m *model) buildHealingCard(log *parser.ParsedLog) string {
-	type PlayerHealingData struct {
-		Name         string
-		TotalHealing int
-		TotalHPS     int
-	}
-	var playerHealingReports []PlayerHealingData
-
-	// Iterate through each player in the log to calculate their total healing and HPS.
-	for _, p := range log.Players {
-		// Only include players who are part of the squad.
-		if !p.NotInSquad {
-			totalHealing := 0
-			totalHPS := 0
-
-			// Loop through the multi-dimensional 'OutgoingHealingAllies' slice.
-			// The outer loop iterates over each inner slice (e.g., each source of healing data).
-			for _, healingSlice := range p.ExtHealingStats.OutgoingHealingAllies {
-				// The inner loop iterates over each 'Healing' struct within the current inner slice.
-				for _, healingData := range healingSlice {
-					totalHealing += healingData.Healing
-					totalHPS += healingData.Hps
-				}
-			}
-
-			// Append the aggregated data to our report slice.
-			playerHealingReports = append(playerHealingReports, PlayerHealingData{
-				Name:         p.Name,
-				TotalHealing: totalHealing,
-				TotalHPS:     totalHPS,
-			})
-		}
-	}
-
-	// Sort the 'playerHealingReports' slice by 'TotalHealing' in descending order.
-	// Players with higher total healing will appear first.
-	sort.Slice(playerHealingReports, func(i, j int) bool {
-		return playerHealingReports[i].TotalHealing > playerHealingReports[j].TotalHealing
-	})
+	healers := analysis.HealingBreakdown(log)
 
 	var sb strings.Builder // Use a strings.Builder for efficient string concatenation.
 
 	// Render the card title with appropriate formatting.
-	headerStr := fmt.Sprintf("%-20s %-10s %s ", "Healing Top 5", "Healing", "HPS")
+	headerStr := fmt.Sprintf("%-*s %-10s %-6s %-9s %s ", m.nameWidth(), i18n.T("card.healing"), i18n.T("col.allies"), i18n.T("col.hps"), i18n.T("col.self"), i18n.T("col.downed"))
 	sb.WriteString(m.styles.CardTitle.Render(headerStr) + "\n")
 
+	coverage := analysis.Healing(log)
+	if !coverage.Addon {
+		sb.WriteString(i18n.T("healing.no_addon") + "\n")
+		return sb.String()
+	}
+
 	// Iterate through the sorted players and build the report rows.
-	for i, report := range playerHealingReports {
+	roles := analysis.Roles(log)
+	for i, h := range healers {
 		// Limit the report to the top 5 players.
-		if i >= 5 {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
 
-		// Only display players who have contributed some healing or HPS.
-		if report.TotalHealing > 0 || report.TotalHPS > 0 {
-			rowStr := fmt.Sprintf("%-20s %-10s %s", report.Name, formatNumber(report.TotalHealing), formatNumber(report.TotalHPS))
-
-			// Apply alternating row styling for better readability.
-			if i%2 != 0 {
-				sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-			} else {
-				sb.WriteString(rowStr + "\n")
-			}
+		// Only display players who have contributed some healing.
+		if h.Allies > 0 || h.Self > 0 {
+			rowStr := fmt.Sprintf("%s %-10s %s %-9s %s", m.playerCell(log, h.Name), m.locale.Number(h.Allies), m.goalCell(roles, h.Name, statHealingHps, h.AlliesHps, 6), m.locale.Number(h.Self), m.locale.Number(h.Downed))
+			m.writeRow(&sb, i, rowStr)
 		}
 	}
+	sb.WriteString(m.healingCoverageNote(coverage))
 	return sb.String()
 }
 
 func (m *model) buildBarrierCard(log *parser.ParsedLog) string {
-	var players []parser.Player
-	for _, p := range log.Players {
-		if !p.NotInSquad {
-			players = append(players, p)
-		}
-	}
-	sort.Slice(players, func(i, j int) bool {
-		if len(players[i].ExtBarrierStats.OutgoingBarrier) == 0 || len(players[j].ExtBarrierStats.OutgoingBarrier) == 0 {
-			return false
-		}
-		return players[i].ExtBarrierStats.OutgoingBarrier[0].Barrier > players[j].ExtBarrierStats.OutgoingBarrier[0].Barrier
-	})
+	providers := analysis.BarrierEfficiency(log)
 	var sb strings.Builder
-	rowStr := fmt.Sprintf("%-20s %-10s %s ", "Barrier Top 5", "Barrier", "BPS")
+	rowStr := fmt.Sprintf("%-*s %-10s %-6s %-10s %s ", m.nameWidth(), i18n.T("card.barrier"), i18n.T("col.barrier"), i18n.T("col.bps"), i18n.T("col.absorbed"), i18n.T("col.waste"))
 	sb.WriteString(m.styles.CardTitle.Render(rowStr) + "\n")
-	for i, p := range players {
-		if i >= 5 {
+	coverage := analysis.Healing(log)
+	if !coverage.Addon {
+		sb.WriteString(i18n.T("healing.no_addon") + "\n")
+		return sb.String()
+	}
+	roles := analysis.Roles(log)
+	for i, p := range providers {
+		if !m.showAllRows && i >= 5 {
 			break
 		}
-		if len(p.ExtBarrierStats.OutgoingBarrier) > 0 {
-			rowStr := fmt.Sprintf("%-20s %-10s %s", p.Name, formatNumber(p.ExtBarrierStats.OutgoingBarrier[0].Barrier), formatNumber(p.ExtBarrierStats.OutgoingBarrier[0].Bps))
-			if i%2 != 0 {
-				sb.WriteString(lipgloss.NewStyle().Background(m.theme.AccentDarkPurple).Foreground(m.theme.Foreground).Render(rowStr) + "\n")
-			} else {
-				sb.WriteString(rowStr + "\n")
+		if p.Applied > 0 || p.Bps > 0 {
+			waste := "-"
+			if p.Applied > 0 && p.Tracked {
+				waste = fmt.Sprintf("%.0f%%", p.WasteRate()*100)
 			}
+			rowStr := fmt.Sprintf("%s %-10s %s %-10s %s", m.playerCell(log, p.Name), m.locale.Number(p.Applied), m.goalCell(roles, p.Name, statBarrierBps, p.Bps, 6), m.locale.Number(p.Absorbed), waste)
+			m.writeRow(&sb, i, rowStr)
 		}
 	}
+	sb.WriteString(m.healingCoverageNote(coverage))
 	return sb.String()
 }
 
+// healingCoverageNote warns under the healing and barrier cards when only some of the
+// squad ran the healing addon, so the numbers are known to be short.
+func (m *model) healingCoverageNote(c analysis.HealingCoverage) string {
+	if c.Complete() {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.colors.Warning).Render(i18n.T("healing.partial", c.Running, c.Squad)) + "\n"
+}
+
 type Styles struct {
-	LeftPanel          lipgloss.Style
-	RightPanel         lipgloss.Style
-	StatusBar          lipgloss.Style
-	HelpBar            lipgloss.Style
-	ListItem           lipgloss.Style
-	SelectedListItem   lipgloss.Style
-	ErrorText          lipgloss.Style
-	Card               lipgloss.Style
-	SelectedCard       lipgloss.Style
-	CardTitle          lipgloss.Style
-	ConfirmationPrompt lipgloss.Style
+	LeftPanel        lipgloss.Style
+	RightPanel       lipgloss.Style
+	StatusBar        lipgloss.Style
+	HelpBar          lipgloss.Style
+	ListItem         lipgloss.Style
+	SelectedListItem lipgloss.Style
+	ErrorText        lipgloss.Style
+	Card             lipgloss.Style
+	SelectedCard     lipgloss.Style
+	CardTitle        lipgloss.Style
+	StripedRow       lipgloss.Style
 }
 
 func NewStyles(theme ShadesOfPurple) Styles {
+	c := theme.Roles()
 	cardStyle := lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder()).
-		BorderForeground(theme.Gray).Padding(0, 0).Margin(0, 0, 0, 0)
+		BorderForeground(c.Muted).Padding(0, 0).Margin(0, 0, 0, 0)
 	return Styles{
 		LeftPanel: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(theme.Gray).Padding(0, 0).Width(23),
+			BorderForeground(c.Muted).Padding(0, 0).Width(runsPanelWidth),
 		RightPanel: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(theme.Gray).Padding(0, 0),
+			BorderForeground(c.Muted).Padding(0, 0),
 		Card: cardStyle,
 		SelectedCard: cardStyle.Copy().
 			Border(lipgloss.ThickBorder()).
-			BorderForeground(theme.AccentCyan),
+			BorderForeground(c.Highlight),
 		CardTitle: lipgloss.NewStyle().
-			Bold(true).Foreground(theme.AccentYellow),
+			Bold(true).Foreground(c.Title),
+		StripedRow: lipgloss.NewStyle().
+			Background(c.RowStripe).Foreground(c.Text),
 		StatusBar: lipgloss.NewStyle().
-			Foreground(theme.Foreground).Background(theme.AccentDarkPurple).Padding(0, 1),
+			Foreground(c.Text).Background(c.RowStripe).Padding(0, 1),
 		HelpBar: lipgloss.NewStyle().
-			Foreground(theme.Gray).Padding(0, 1),
+			Foreground(c.Muted).Padding(0, 1),
 		ListItem: lipgloss.NewStyle().
 			Padding(0, 0, 0, 0),
 		SelectedListItem: lipgloss.NewStyle().
-			Foreground(theme.AccentCyan).Bold(true),
+			Foreground(c.Highlight).Bold(true),
 		ErrorText: lipgloss.NewStyle().
-			Foreground(theme.AccentRed),
-		ConfirmationPrompt: lipgloss.NewStyle().
-			Background(theme.AccentGreen).
-			Foreground(theme.Background).
-			Padding(0, 1),
+			Foreground(c.Bad),
 	}
 }
 
-func openFile(path string) tea.Cmd {
+// openFile opens a file, folder or URL with the program the OS uses for it and shows
+// status once it has started.
+func openFile(path, status string) tea.Cmd {
 	return func() tea.Msg {
 		err := open.Run(path)
 		if err != nil {
 			return ErrMsg{Err: fmt.Errorf("could not open file: %w", err)}
 		}
-		return StatusMsg(fmt.Sprintf("Opening report: %s", path))
+		return StatusMsg(status)
+	}
+}
+
+// openInBrowser opens a report or URL with the configured browser, or like openFile
+// if none is set.
+func (m *model) openInBrowser(target, status string) tea.Cmd {
+	browser := m.config.Browser
+	if browser == "" {
+		return openFile(target, status)
+	}
+	return func() tea.Msg {
+		if err := open.RunWith(target, browser); err != nil {
+			return ErrMsg{Err: fmt.Errorf("could not open %s with %s: %w", target, browser, err)}
+		}
+		return StatusMsg(status)
 	}
 }
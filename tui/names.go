@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/parser"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// defaultNameWidth is the name column width when name_width is not set.
+	defaultNameWidth = 20
+	// minNameWidth keeps names readable when name_width is set very low.
+	minNameWidth = 8
+	// specCodeWidth is the width of the spec code in front of names, e.g. "SCG".
+	specCodeWidth = 3
+)
+
+// clip shortens s to width terminal cells, marking the cut with an ellipsis. Wide
+// characters count as two cells, so CJK names line up with Latin ones.
+func clip(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	return ansi.Truncate(s, width, "…")
+}
+
+// nameWidth is the width of the player name column on the cards.
+func (m *model) nameWidth() int {
+	if m.config.NameWidth <= 0 {
+		return defaultNameWidth
+	}
+	return max(m.config.NameWidth, minNameWidth)
+}
+
+// nameCell fits a player name to the name column: cut when too long and padded
+// to the column's width otherwise. fmt's %-20s counts runes rather than cells and
+// never cuts, which breaks the card's alignment.
+func (m *model) nameCell(name string) string {
+	w := m.nameWidth()
+	name = clip(name, w)
+	return name + strings.Repeat(" ", max(w-lipgloss.Width(name), 0))
+}
+
+// specCell is nameCell with the player's spec code in front, e.g. "FB  Name", in the
+// color of their profession. The code takes its room from the name column, so the
+// card's columns stay where they are. An unknown spec leaves the code blank.
+func (m *model) specCell(spec, name string) string {
+	w := m.nameWidth() - specCodeWidth - 1
+	name = clip(name, w)
+	code := fmt.Sprintf("%-*s", specCodeWidth, analysis.SpecCode(spec))
+	if c, ok := m.colors.Professions[analysis.CoreProfession(spec)]; ok {
+		code = lipgloss.NewStyle().Foreground(c).Render(code)
+	}
+	return code + " " + name + strings.Repeat(" ", max(w-lipgloss.Width(name), 0))
+}
+
+// playerCell is specCell for a squad member of log known only by name.
+func (m *model) playerCell(log *parser.ParsedLog, name string) string {
+	for _, p := range log.Players {
+		if p.Name == name {
+			return m.specCell(p.Profession, name)
+		}
+	}
+	return m.specCell("", name)
+}
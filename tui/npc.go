@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/config"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleNPCDamage switches damage to guards, lords, siege and gates in or out of the
+// fight cards and saves the setting.
+func (m *model) toggleNPCDamage() tea.Cmd {
+	m.config.ExcludeNPCDamage = !m.config.ExcludeNPCDamage
+	if m.config.ExcludeNPCDamage {
+		m.setStatus(i18n.T("status.npc_excluded"))
+	} else {
+		m.setStatus(i18n.T("status.npc_included"))
+	}
+	cfg := m.config
+	return func() tea.Msg {
+		if err := config.SaveConfig(config.DefaultPath, &cfg); err != nil {
+			return ErrMsg{Err: fmt.Errorf("failed to save NPC damage setting: %w", err)}
+		}
+		return nil
+	}
+}
+
+// npcHeader is the squad's PPT damage, to NPC targets, above the cards when there was
+// any, and whether the cards count it.
+func (m *model) npcHeader(log *parser.ParsedLog) string {
+	npc := analysis.SquadNPCDamage(log)
+	if npc.Damage == 0 {
+		return ""
+	}
+	state := i18n.T("npc.included")
+	if m.config.ExcludeNPCDamage {
+		state = i18n.T("npc.excluded")
+	}
+	return m.styles.CardTitle.Render(i18n.T("npc.header", m.locale.Number(npc.Damage), npc.Targets, state))
+}
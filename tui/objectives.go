@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/gw2api"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	objectivePollInterval = time.Minute
+	// flipLookback is how long before the run started a flip is still recorded, so the
+	// first fight of the night can be matched to what it was fighting over.
+	flipLookback = 10 * time.Minute
+	// A flip is related to a fight if it happened this long before it started or after
+	// it ended.
+	flipBeforeFight = 2 * time.Minute
+	flipAfterFight  = 5 * time.Minute
+)
+
+// objectiveTypes are the objectives worth relating fights to. Spawns, ruins and
+// mercenary camps are left out.
+var objectiveTypes = map[string]bool{"Camp": true, "Tower": true, "Keep": true, "Castle": true}
+
+// objectivesPolledMsg carries the owner of every objective at one poll.
+type objectivesPolledMsg struct {
+	RunPath string
+	World   int
+	States  []gw2api.ObjectiveState
+	Err     error
+}
+
+// objectivePollMsg asks for the next poll of a run's objectives.
+type objectivePollMsg struct {
+	RunPath string
+	World   int
+}
+
+// startObjectivePolling begins polling objective owners for a new run, if a world or
+// API key is configured. Polling stops when the run is no longer live.
+func (m *model) startObjectivePolling(runPath string) tea.Cmd {
+	if m.config.GW2WorldID == 0 && m.config.GW2APIKey == "" {
+		return nil
+	}
+	m.flipSeen = make(map[string]time.Time)
+	m.flipSince = time.Now().Add(-flipLookback)
+	return m.pollObjectives(runPath, 0)
+}
+
+// pollObjectives fetches objective owners. A zero world is resolved from the config
+// first.
+func (m *model) pollObjectives(runPath string, world int) tea.Cmd {
+	worldID, apiKey := m.config.GW2WorldID, m.config.GW2APIKey
+	return func() tea.Msg {
+		if world == 0 {
+			home, err := gw2api.HomeWorld(worldID, apiKey)
+			if err != nil {
+				return objectivesPolledMsg{RunPath: runPath, Err: err}
+			}
+			world = home
+		}
+		states, err := gw2api.Objectives(world)
+		return objectivesPolledMsg{RunPath: runPath, World: world, States: states, Err: err}
+	}
+}
+
+// handleObjectivesPolled records new flips in run.json and schedules the next poll.
+func (m *model) handleObjectivesPolled(msg objectivesPolledMsg) tea.Cmd {
+	if msg.RunPath != m.liveRunPath {
+		return nil
+	}
+	next := tea.Tick(objectivePollInterval, func(time.Time) tea.Msg {
+		return objectivePollMsg{RunPath: msg.RunPath, World: msg.World}
+	})
+	if msg.Err != nil {
+		// The API is flaky during resets; keep trying without flooding the status bar
+		logger.Warn("Objective poll failed: %v", msg.Err)
+		return next
+	}
+
+	var flips []processor.ObjectiveFlip
+	for _, s := range msg.States {
+		if !objectiveTypes[s.Type] || s.LastFlipped.IsZero() || s.LastFlipped.Before(m.flipSince) {
+			continue
+		}
+		if seen, ok := m.flipSeen[s.ID]; ok && seen.Equal(s.LastFlipped) {
+			continue
+		}
+		m.flipSeen[s.ID] = s.LastFlipped
+		flips = append(flips, processor.ObjectiveFlip{
+			Time:      s.LastFlipped,
+			Objective: s.Name,
+			Type:      s.Type,
+			Map:       s.Map,
+			Owner:     s.Owner,
+		})
+	}
+	if len(flips) == 0 {
+		return next
+	}
+	logger.Info("Recorded %d objective flips for %s", len(flips), msg.RunPath)
+	save := m.updateRunMeta(msg.RunPath, func(meta *processor.RunMeta) {
+		meta.Flips = append(meta.Flips, flips...)
+	})
+	return tea.Batch(save, next)
+}
+
+// handleObjectivePoll runs a scheduled poll while its run is still live.
+func (m *model) handleObjectivePoll(msg objectivePollMsg) tea.Cmd {
+	if msg.RunPath != m.liveRunPath {
+		return nil
+	}
+	return m.pollObjectives(msg.RunPath, msg.World)
+}
+
+// fightFlips returns the recorded flips on the fight's map around the time of the fight.
+func (m *model) fightFlips(log *parser.ParsedLog) []processor.ObjectiveFlip {
+	s := analysis.Summarize(log)
+	if s.Start.IsZero() {
+		return nil
+	}
+	location := m.fightLocation(log)
+	from, to := s.Start.Add(-flipBeforeFight), s.End.Add(flipAfterFight)
+	var out []processor.ObjectiveFlip
+	for _, f := range m.runMeta.Flips {
+		if f.Map == location && !f.Time.Before(from) && !f.Time.After(to) {
+			out = append(out, f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
+
+func (m *model) buildObjectivesCard(log *parser.ParsedLog) string {
+	var content strings.Builder
+	content.WriteString(m.styles.CardTitle.Render(i18n.T("card.objectives")) + "\n")
+
+	flips := m.fightFlips(log)
+	if len(flips) == 0 {
+		content.WriteString(i18n.T("objectives.none") + "\n")
+		return content.String()
+	}
+
+	s := analysis.Summarize(log)
+	for _, f := range flips {
+		if f.Time.After(s.End) && f.Type != "Camp" {
+			headline := i18n.T("objectives.headline", f.Objective)
+			content.WriteString(lipgloss.NewStyle().Foreground(m.colors.Warning).Render(headline) + "\n")
+			break
+		}
+	}
+	for _, f := range flips {
+		var when string
+		switch {
+		case f.Time.Before(s.Start):
+			when = "-" + s.Start.Sub(f.Time).Round(time.Second).String()
+		case f.Time.After(s.End):
+			when = "+" + f.Time.Sub(s.End).Round(time.Second).String()
+		default:
+			when = i18n.T("objectives.during")
+		}
+		content.WriteString(fmt.Sprintf("%-7s %-20.20s %-6s %s\n", when, f.Objective, f.Type, f.Owner))
+	}
+	return content.String()
+}
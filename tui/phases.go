@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+)
+
+// cyclePhase steps the dashboard through the selected log's EI phases, wrapping back
+// to the full fight.
+func (m *model) cyclePhase() {
+	log := m.selectedLog()
+	if log == nil || len(log.Phases) < 2 {
+		m.phase = 0
+		m.setStatus(i18n.T("status.no_phases"))
+		return
+	}
+	m.phase = (m.phase + 1) % len(log.Phases)
+	m.setStatus(i18n.T("status.phase", phaseName(log, m.phase)))
+}
+
+func phaseName(log *parser.ParsedLog, phase int) string {
+	if phase <= 0 || phase >= len(log.Phases) {
+		return i18n.T("phase.full")
+	}
+	if log.Phases[phase].Name != "" {
+		return log.Phases[phase].Name
+	}
+	return i18n.T("phase.n", phase)
+}
+
+// phaseHeader names the shown phase above the cards when the log has more than one.
+func (m *model) phaseHeader(log *parser.ParsedLog) string {
+	if log == nil || len(log.Phases) < 2 {
+		return ""
+	}
+	phase := m.phase
+	if phase >= len(log.Phases) {
+		phase = 0
+	}
+	p := log.Phases[phase]
+	return m.styles.CardTitle.Render(i18n.T("phase.header",
+		phase, len(log.Phases)-1, phaseName(log, phase), formatFightTime(int(p.Start)), formatFightTime(int(p.End))))
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/server"
+	"sort"
+	"strings"
+)
+
+// mergePoVs folds logs of the same fight, e.g. yours and one a squad member uploaded,
+// into one log list entry. The entry shows the preferred point of view; the others
+// are kept in m.povs under its name. Fights are regrouped from scratch, so a better
+// point of view arriving later takes over.
+func (m *model) mergePoVs() {
+	names := append([]string(nil), m.logList...)
+	for _, others := range m.povs {
+		for _, name := range others {
+			if _, ok := m.logFullPaths[name]; ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	m.povs = make(map[string][]string)
+	merged := make(map[string]bool)
+	var list []string
+	for i, name := range names {
+		if merged[name] {
+			continue
+		}
+		group := []string{name}
+		if s, ok := m.summaries[m.logFullPaths[name]]; ok {
+			for _, other := range names[i+1:] {
+				if o, ok := m.summaries[m.logFullPaths[other]]; ok && !merged[other] && analysis.SameFight(s, o) {
+					group = append(group, other)
+					merged[other] = true
+				}
+			}
+		}
+		sort.SliceStable(group, func(a, b int) bool { return m.preferPoV(group[a], group[b]) })
+		list = append(list, group[0])
+		if len(group) > 1 {
+			m.povs[group[0]] = group[1:]
+		}
+	}
+	m.logList = list
+}
+
+// preferPoV is whether fight a is the better point of view than b: with healing
+// addon data, then seeing more of the squad, then your own log over an upload.
+func (m *model) preferPoV(a, b string) bool {
+	sa, sb := m.summaries[m.logFullPaths[a]], m.summaries[m.logFullPaths[b]]
+	if sa.HealingStats != sb.HealingStats {
+		return sa.HealingStats
+	}
+	if sa.SquadCount != sb.SquadCount {
+		return sa.SquadCount > sb.SquadCount
+	}
+	return server.Uploader(a) == "" && server.Uploader(b) != ""
+}
+
+// povPrimary is the list entry a fight is shown under, itself if it isn't merged
+// into another point of view.
+func (m *model) povPrimary(name string) string {
+	for primary, others := range m.povs {
+		for _, other := range others {
+			if other == name {
+				return primary
+			}
+		}
+	}
+	return name
+}
+
+// fightSummary is a fight's summary with the squad members only its other points
+// of view saw added.
+func (m *model) fightSummary(name string) (analysis.FightSummary, bool) {
+	s, ok := m.summaries[m.logFullPaths[name]]
+	if !ok || len(m.povs[name]) == 0 {
+		return s, ok
+	}
+	var others []analysis.FightSummary
+	for _, other := range m.povs[name] {
+		if o, ok := m.summaries[m.logFullPaths[other]]; ok {
+			others = append(others, o)
+		}
+	}
+	return analysis.MergePoVs(s, others), true
+}
+
+// povHeader names whose point of view the cards show above them, and who else
+// recorded the fight, when several logs of it were merged.
+func (m *model) povHeader(name string) string {
+	others := m.povs[name]
+	if len(others) == 0 {
+		return ""
+	}
+	recorders := make([]string, len(others))
+	for i, other := range others {
+		recorders[i] = m.povRecorder(other)
+	}
+	return m.styles.CardTitle.Render(i18n.T("pov.header", m.povRecorder(name), strings.Join(recorders, ", ")))
+}
+
+// povRecorder is who recorded a log: the uploader, or you for your own logs, marked
+// when the log has healing addon data.
+func (m *model) povRecorder(name string) string {
+	who := server.Uploader(name)
+	if who == "" {
+		who = i18n.T("pov.you")
+	}
+	if m.summaries[m.logFullPaths[name]].HealingStats {
+		who += " " + i18n.T("pov.healing")
+	}
+	return who
+}
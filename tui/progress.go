@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// parseProgressMsg is a line of output from a running EI process, or its end.
+type parseProgressMsg struct {
+	ID    int64  // The EI process, see processor.Progress
+	Label string // The log, or how many logs are in a batch
+	Line  string
+	Done  bool
+}
+
+type spinnerTickMsg struct{}
+
+// parseProgress is an EI process shown in the status bar.
+type parseProgress struct {
+	id      int64
+	label   string
+	line    string
+	started time.Time
+}
+
+// cancelParseFn stops a running parse by its ID, see OnCancelParse.
+var cancelParseFn func(id int64) bool
+
+// OnCancelParse sets how the parse shown in the status bar is canceled. Without it,
+// e.g. when attached to another instance, parses cannot be canceled. It must be called
+// before the program starts.
+func OnCancelParse(fn func(id int64) bool) {
+	cancelParseFn = fn
+}
+
+// cancelParse stops the parse shown in the status bar.
+func (m *model) cancelParse() {
+	if len(m.parses) == 0 {
+		return
+	}
+	p := m.parses[0]
+	switch {
+	case cancelParseFn == nil:
+		m.setError(errors.New(i18n.T("status.cancel_unavailable")))
+	case cancelParseFn(p.id):
+		m.setStatus(i18n.T("status.canceling", p.label))
+	}
+}
+
+// handleParseProgress tracks running parses. The spinner ticks while any are left.
+func (m *model) handleParseProgress(msg parseProgressMsg) tea.Cmd {
+	for i, p := range m.parses {
+		if p.id != msg.ID {
+			continue
+		}
+		if msg.Done {
+			m.parses = append(m.parses[:i:i], m.parses[i+1:]...)
+		} else if msg.Line != "" {
+			m.parses[i].line = msg.Line
+		}
+		return nil
+	}
+	if msg.Done {
+		return nil
+	}
+	m.parses = append(m.parses, parseProgress{id: msg.ID, label: msg.Label, line: msg.Line, started: time.Now()})
+	return m.startSpinner()
+}
+
+// startSpinner animates the spinners until no log is parsed or archived.
+func (m *model) startSpinner() tea.Cmd {
+	if m.spinning {
+		return nil
+	}
+	m.spinning = true
+	return spinnerTick()
+}
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(time.Time) tea.Msg { return spinnerTickMsg{} })
+}
+
+func (m *model) handleSpinnerTick() tea.Cmd {
+	if len(m.parses) == 0 && len(m.arriving) == 0 {
+		m.spinning = false
+		return nil
+	}
+	m.spinnerFrame = (m.spinnerFrame + 1) % len(spinnerFrames)
+	return spinnerTick()
+}
+
+// parseStatus is the status bar text while EI runs: the oldest parse with its elapsed
+// time and last output line, and how many others are running.
+func (m *model) parseStatus() string {
+	p := m.parses[0]
+	elapsed := time.Since(p.started).Truncate(time.Second)
+	text := i18n.T("status.parsing", spinnerFrames[m.spinnerFrame], p.label,
+		fmt.Sprintf("%d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60))
+	if more := len(m.parses) - 1; more > 0 {
+		text += " " + i18n.T("status.parsing_more", more)
+	}
+	if p.line != "" {
+		text += " · " + p.line
+	}
+	return text
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"strings"
+)
+
+// DefaultPvECardLayout is the dashboard used for raid, strike and fractal logs.
+func DefaultPvECardLayout() [][]string {
+	return [][]string{
+		{"boss", "phasetimes"},
+		{"groupdps", "mechanics"},
+		{"healing", "barrier"},
+	}
+}
+
+func (m *model) buildBossCard(log *parser.ParsedLog) string {
+	var sb strings.Builder
+	result := i18n.T("boss.fail")
+	if log.Success {
+		result = i18n.T("boss.kill")
+	}
+	if log.IsCM {
+		result += " (CM)"
+	}
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-24s %-8s %s", log.FightName, i18n.T("col.burned"), result)) + "\n")
+	bosses := analysis.Bosses(log)
+	if len(bosses) == 0 {
+		sb.WriteString(i18n.T("boss.none") + "\n")
+	}
+	for i, b := range bosses {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		rowStr := fmt.Sprintf("%-24s %-8s %s / %s", b.Name, fmt.Sprintf("%.1f%%", b.BurnedPct), m.locale.Number(b.FinalHealth), m.locale.Number(b.TotalHealth))
+		m.writeRow(&sb, i, rowStr)
+	}
+	sb.WriteString(i18n.T("boss.duration", log.Duration))
+	return sb.String()
+}
+
+func (m *model) buildPhaseTimesCard(log *parser.ParsedLog) string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-24s %-10s %s", i18n.T("card.phasetimes"), i18n.T("col.start"), i18n.T("col.length"))) + "\n")
+	if len(log.Phases) == 0 {
+		sb.WriteString(i18n.T("phasetimes.none") + "\n")
+		return sb.String()
+	}
+	for i, p := range log.Phases {
+		if !m.showAllRows && i >= 8 {
+			break
+		}
+		rowStr := fmt.Sprintf("%-24s %-10s %s", phaseName(log, i), formatFightTime(int(p.Start)), formatFightTime(int(p.End-p.Start)))
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
+
+func (m *model) buildMechanicsCard(log *parser.ParsedLog) string {
+	mechs := analysis.Mechanics(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-14s %-6s %s", i18n.T("card.mechanics"), i18n.T("col.count"), i18n.T("col.most"))) + "\n")
+	if len(mechs) == 0 {
+		sb.WriteString(i18n.T("mechanics.none") + "\n")
+	}
+	for i, mf := range mechs {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		rowStr := fmt.Sprintf("%-14s %-6d %s (%d)", mf.Name, mf.Count, mf.TopActor, mf.TopCount)
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
+
+func (m *model) buildGroupDpsCard(log *parser.ParsedLog) string {
+	total, players := analysis.GroupDps(log)
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-12s %-8s %s", m.nameWidth(), i18n.T("card.groupdps"), i18n.T("col.prof"), i18n.T("col.dps"), i18n.T("col.share"))) + "\n")
+	for i, p := range players {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		share := 0.0
+		if total > 0 {
+			share = float64(p.Dps) / float64(total) * 100
+		}
+		rowStr := fmt.Sprintf("%s %-12s %-8s %.0f%%", m.specCell(p.Profession, p.Name), p.Profession, m.locale.Number(p.Dps), share)
+		m.writeRow(&sb, i, rowStr)
+	}
+	sb.WriteString(i18n.T("groupdps.total", m.locale.Number(total)))
+	return sb.String()
+}
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"gw2-cmd-watch/apperr"
+	"gw2-cmd-watch/i18n"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// helpURL is the troubleshooting section of the README, opened with H while an error
+// with a suggested fix is shown.
+const helpURL = "https://github.com/theextendedname/GW2_Commanders_Watch#troubleshooting"
+
+// remedy is the suggested fix for a classified pipeline error, or "" if there is none.
+func remedy(err error) string {
+	kind := apperr.KindOf(err)
+	if kind == "" {
+		return ""
+	}
+	return i18n.T("fix." + string(kind))
+}
+
+// openHelp opens the troubleshooting guide for the error in the status bar.
+func (m *model) openHelp() tea.Cmd {
+	if remedy(m.err) == "" {
+		return nil
+	}
+	return m.openInBrowser(helpURL, i18n.T("status.opening_help", helpURL))
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/apperr"
+	"gw2-cmd-watch/eicli"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"gw2-cmd-watch/watcher"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// brokenGlyph marks fights whose JSON could not be parsed in the log list.
+const brokenGlyph = "✗"
+
+// fightReparsedMsg reports the outcome of running EI again for a broken fight.
+type fightReparsedMsg struct {
+	FullPath string
+	Err      error
+}
+
+// handleLogParseFailed marks a fight as broken in the log list, leaving the status bar
+// to the rest of the run. The error is kept in the event log.
+func (m *model) handleLogParseFailed(msg LogParseFailedMsg) tea.Cmd {
+	next := m.parseDone(msg.FullPath)
+	if filepath.Dir(msg.FullPath) != m.currentRunPath {
+		return next
+	}
+	m.brokenLogs[msg.FullPath] = msg.Err
+	m.events.add(eventError, msg.Err.Error())
+	return next
+}
+
+// reparseSelectedFight runs EI again on the source log of the selected fight if its
+// JSON is broken.
+func (m *model) reparseSelectedFight() tea.Cmd {
+	path := m.selectedLogPath()
+	if _, broken := m.brokenLogs[path]; !broken {
+		return nil
+	}
+	if m.loadingLogs[path] {
+		return nil
+	}
+	m.loadingLogs[path] = true
+	m.setStatus(i18n.T("status.reparsing", processor.DisplayName(path)))
+	return reparseFight(path, m.config.WatchFolder)
+}
+
+// reparseFight finds the arcdps log of an archived fight in the watch folder by the
+// name EI gave the fight, and replaces the fight's JSON and report with a new parse.
+func reparseFight(jsonPath, watchFolder string) tea.Cmd {
+	return func() tea.Msg {
+		if !eicli.CheckCLIExists() {
+			return fightReparsedMsg{FullPath: jsonPath, Err: apperr.New(apperr.CLIMissing, fmt.Errorf("Elite Insights is not installed"))}
+		}
+		logs, err := watcher.Scan(watchFolder, []string{processor.LogArchive, processor.FightLogTemp}, time.Time{})
+		if err != nil {
+			return fightReparsedMsg{FullPath: jsonPath, Err: fmt.Errorf("could not list logs: %w", err)}
+		}
+		name := processor.DisplayName(jsonPath)
+		for _, logPath := range logs {
+			if strings.TrimSuffix(filepath.Base(logPath), filepath.Ext(logPath)) == name {
+				return fightReparsedMsg{FullPath: jsonPath, Err: processor.ReparseFight(jsonPath, logPath)}
+			}
+		}
+		return fightReparsedMsg{FullPath: jsonPath, Err: fmt.Errorf("the source log of %s is not in %s", name, watchFolder)}
+	}
+}
+
+// handleFightReparsed loads a repaired fight, or keeps it marked as broken.
+func (m *model) handleFightReparsed(msg fightReparsedMsg) tea.Cmd {
+	delete(m.loadingLogs, msg.FullPath)
+	if filepath.Dir(msg.FullPath) != m.currentRunPath {
+		return nil
+	}
+	if msg.Err != nil {
+		m.setError(fmt.Errorf("failed to parse %s again: %w", processor.DisplayName(msg.FullPath), msg.Err))
+		return nil
+	}
+	delete(m.brokenLogs, msg.FullPath)
+	m.setStatus(i18n.T("status.reparsed", processor.DisplayName(msg.FullPath)))
+	m.loadingLogs[msg.FullPath] = true
+	return parseSingleLog(msg.FullPath)
+}
+
+// renderBrokenFight is shown in the right panel for a fight whose JSON does not parse.
+func (m *model) renderBrokenFight(err error) string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(brokenGlyph+" "+processor.DisplayName(m.selectedLogPath())) + "\n\n")
+	sb.WriteString(i18n.T("broken.title") + "\n")
+	sb.WriteString(lipgloss.NewStyle().Foreground(m.colors.Bad).Render(err.Error()) + "\n\n")
+	if m.loadingLogs[m.selectedLogPath()] {
+		sb.WriteString(i18n.T("broken.reparsing"))
+	} else {
+		sb.WriteString(i18n.T("broken.hint"))
+	}
+	return m.styles.RightPanel.Render(sb.String())
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// replayJump is how many seconds page up and down move the replay scrubber.
+const replayJump = 10
+
+// openReplay shows the replay scrubber for the selected fight from its start.
+func (m *model) openReplay() {
+	log := m.selectedLog()
+	if log == nil {
+		return
+	}
+	if analysis.FightSeconds(log) <= 0 {
+		m.setStatus(i18n.T("status.no_replay"))
+		return
+	}
+	m.replaying = true
+	m.replaySecond = 0
+}
+
+func (m model) handleReplayKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case keyQuit.matches(msg):
+		return m, tea.Quit
+	case keyCloseReplay.matches(msg):
+		m.replaying = false
+	case keyStepBack.matches(msg):
+		m.replaySecond--
+	case keyStepForward.matches(msg):
+		m.replaySecond++
+	case keyJumpBack.matches(msg):
+		m.replaySecond -= replayJump
+	case keyJumpForward.matches(msg):
+		m.replaySecond += replayJump
+	case keyHome.matches(msg):
+		m.replaySecond = 0
+	case keyEnd.matches(msg):
+		m.replaySecond = math.MaxInt32 // Clamped to the fight's end below
+	}
+	if log := m.selectedLog(); log != nil {
+		m.replaySecond = min(max(m.replaySecond, 0), analysis.FightSeconds(log))
+	}
+	return m, nil
+}
+
+// renderReplay draws the scrubber: a bar over the fight's length and who is up,
+// downed and dead on both sides at the selected second.
+func (m *model) renderReplay(log *parser.ParsedLog) string {
+	length := analysis.FightSeconds(log)
+	second := min(m.replaySecond, length)
+	f := analysis.ReplayAt(log, second)
+	width := m.styles.RightPanel.GetWidth() - m.styles.SelectedCard.GetHorizontalFrameSize()
+
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(i18n.T("replay.title", formatFightTime(second*1000), formatFightTime(length*1000))) + "\n")
+	sb.WriteString(m.replayBar(second, length, width-m.styles.SelectedCard.GetHorizontalPadding()) + "\n\n")
+
+	counts := func(alive, downed, dead int) string {
+		return fmt.Sprintf("%s   %s   %s", i18n.T("replay.up", alive),
+			lipgloss.NewStyle().Foreground(m.colors.Warning).Render(i18n.T("replay.downed", downed)),
+			lipgloss.NewStyle().Foreground(m.colors.Bad).Render(i18n.T("replay.dead", dead)))
+	}
+	row := func(label, value string) {
+		sb.WriteString(fmt.Sprintf("%-10s %s\n", label, value))
+	}
+	row(i18n.T("replay.squad"), counts(f.SquadAlive, f.SquadDowned, f.SquadDead))
+	if f.AvgSpread > 0 {
+		row(i18n.T("replay.spread"), i18n.T("replay.spread_value", m.locale.Number(int(f.AvgSpread)), m.locale.Number(int(f.MaxSpread))))
+	} else {
+		row(i18n.T("replay.spread"), i18n.T("replay.no_positions"))
+	}
+	if f.HasEnemies {
+		row(i18n.T("replay.enemies"), counts(f.EnemyAlive, f.EnemyDowned, f.EnemyDead))
+	} else {
+		row(i18n.T("replay.enemies"), i18n.T("replay.no_enemies"))
+	}
+
+	card := m.styles.SelectedCard.Width(width).Render(strings.TrimRight(sb.String(), "\n"))
+	return m.styles.RightPanel.Render(card)
+}
+
+// replayBar is the scrubber's track with a knob at the selected second.
+func (m *model) replayBar(second, length, width int) string {
+	width = max(width, 10)
+	pos := 0
+	if length > 0 {
+		pos = second * (width - 1) / length
+	}
+	played := lipgloss.NewStyle().Foreground(m.colors.Highlight).Render(strings.Repeat("━", pos) + "●")
+	rest := lipgloss.NewStyle().Foreground(m.colors.Muted).Render(strings.Repeat("─", width-1-pos))
+	return played + rest
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/report"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultReportWindow is the period report's window when report_window is not set.
+const defaultReportWindow = "7d"
+
+// periodReportMsg carries a finished period report.
+type periodReportMsg struct {
+	Report report.Report
+	Err    error
+}
+
+// togglePeriodReport builds the report over the last report_window in the background,
+// or hides it if it is showing.
+func (m *model) togglePeriodReport() tea.Cmd {
+	if m.viewMode != runsView || m.reportLoading {
+		return nil
+	}
+	if m.periodReport != nil {
+		m.periodReport = nil
+		return nil
+	}
+	window := m.config.ReportWindow
+	if window == "" {
+		window = defaultReportWindow
+	}
+	d, err := report.ParseWindow(window)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+	m.reportLoading = true
+	m.setStatus(i18n.T("status.report_loading", window))
+	ratio, exclude := m.config.OutnumberedRatio, m.config.ExcludeOutnumberedLosses
+	return func() tea.Msg {
+		r, err := report.Build(time.Now().Add(-d), ratio, exclude)
+		return periodReportMsg{Report: r, Err: err}
+	}
+}
+
+func (m *model) handlePeriodReport(msg periodReportMsg) {
+	m.reportLoading = false
+	if msg.Err != nil {
+		m.setError(fmt.Errorf("failed to build report: %w", msg.Err))
+		return
+	}
+	m.periodReport = &msg.Report
+	m.setStatus(i18n.T("status.report_ready", msg.Report.Fights, msg.Report.Runs))
+}
+
+// renderPeriodReport shows fights, W/L, time in combat, top performers and
+// attendance over the report window.
+func (m *model) renderPeriodReport() string {
+	r := m.periodReport
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(i18n.T("report.title", m.locale.DateTime(r.Since), m.locale.DateTime(r.Until))) + "\n\n")
+	sb.WriteString(i18n.T("report.totals", r.Runs, r.Fights, r.Record.Wins, r.Record.Losses, r.Record.Draws,
+		m.locale.Number(r.Kills), m.locale.Number(r.Deaths), r.Combat.Round(time.Second)) + "\n\n")
+	if len(r.Players) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-15s %s", m.nameWidth(), i18n.T("report.top_damage"), i18n.T("col.tdmg"), i18n.T("col.fights"))) + "\n")
+	for i, p := range r.TopBy(5, report.ByDamage) {
+		m.writeRow(&sb, i, fmt.Sprintf("%s %-15s %d", m.nameCell(p.Name), m.locale.Number(p.Damage), p.Fights))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-15s %s", m.nameWidth(), i18n.T("report.top_dps"), i18n.T("col.avg_dps"), i18n.T("col.fights"))) + "\n")
+	for i, p := range r.TopBy(5, report.ByAvgDps) {
+		m.writeRow(&sb, i, fmt.Sprintf("%s %-15s %d", m.nameCell(p.Name), m.locale.Number(p.AvgDps()), p.Fights))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-8s %s", m.nameWidth(), i18n.T("report.attendance", len(r.Players)), i18n.T("col.runs"), i18n.T("col.fights"))) + "\n")
+	for i, p := range r.Players {
+		m.writeRow(&sb, i, fmt.Sprintf("%s %-8s %d", m.nameCell(p.Name), fmt.Sprintf("%d/%d", p.Runs, r.Runs), p.Fights))
+	}
+	return sb.String()
+}
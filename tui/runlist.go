@@ -0,0 +1,353 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/processor"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runSortKey is what the run list is ordered by.
+type runSortKey int
+
+const (
+	sortRunsByDate runSortKey = iota
+	sortRunsByCommander
+	sortRunsByFights
+	sortRunsByKills
+	runSortKeys // Number of orders
+)
+
+// runGroupKey is how the run list is split under headers.
+type runGroupKey int
+
+const (
+	groupRunsNone runGroupKey = iota
+	groupRunsByWeek
+	groupRunsByCommander
+	runGroupKeys // Number of groupings
+)
+
+// Names of the orders and groupings in state.json and their i18n keys.
+var (
+	runSortNames  = []string{"date", "commander", "fights", "kills"}
+	runGroupNames = []string{"none", "week", "commander"}
+)
+
+// runInfo is what the run list shows, sorts and groups by.
+type runInfo struct {
+	commander string    // Account, empty if neither run.json nor the name tells
+	started   time.Time // Zero if unknown
+	closed    time.Time // Zero while live or if unknown
+	mapName   string    // Map of the first fight
+	tier      int       // Matchup tier, 0 if unknown
+	tags      []string
+	notes     string
+	links     []string
+	fights    int
+	kills     int // -1 until counted
+}
+
+// runRow is a line of the run list: a run, or a group header when run is empty.
+type runRow struct {
+	run   string
+	group string
+	runs  int // Runs under a header
+}
+
+// runKillsMsg carries the totals counted for runs whose run.json had none.
+type runKillsMsg struct {
+	Totals map[string]processor.RunTotals // By run folder name
+}
+
+// readRunInfo reads the start and fight count of a run, and its kills if run.json has
+// current totals.
+func readRunInfo(runName string) runInfo {
+	runPath := filepath.Join(processor.LogArchive, runName)
+	meta, _ := processor.LoadRunMeta(runPath)
+	info := describeRun(runName, meta)
+	files, _ := processor.RunLogFiles(runPath)
+	info.fights = len(files)
+	if meta.Totals != nil && meta.Totals.Fights == info.fights {
+		info.kills = meta.Totals.Kills
+	}
+	return info
+}
+
+// describeRun is what run.json says about a run. Runs from older versions have little
+// in it, so the rest comes from the folder name, <commander>_<timestamp>[_map][_tier].
+func describeRun(runName string, meta processor.RunMeta) runInfo {
+	info := runInfo{
+		commander: meta.Commander,
+		started:   meta.Started,
+		closed:    meta.Closed,
+		mapName:   meta.Map,
+		tags:      meta.Tags,
+		notes:     meta.Notes,
+		links:     meta.Links,
+		kills:     -1,
+	}
+	name := processor.ParseRunName(runName)
+	if info.commander == "" && strings.Contains(runName, "_") {
+		info.commander = name.Commander
+	}
+	if info.started.IsZero() && name.Timestamp != "" {
+		info.started, _ = time.ParseInLocation("2006-01-02_15-04-05", name.Timestamp, time.Local)
+	}
+	if info.mapName == "" {
+		info.mapName = name.Map
+	}
+	info.tier = name.Tier
+	if meta.Matchup != nil {
+		info.tier = meta.Matchup.Tier()
+	}
+	return info
+}
+
+// runSubtitle is the line under a run's commander: the day it started, the first map,
+// the tier and its tags. The panel is narrow, so the time is left to the preview.
+func (m *model) runSubtitle(info runInfo) string {
+	var parts []string
+	if !info.started.IsZero() {
+		parts = append(parts, m.locale.Date(info.started))
+	}
+	if info.mapName != "" {
+		parts = append(parts, info.mapName)
+	}
+	if info.tier > 0 {
+		parts = append(parts, i18n.T("runs.tier", info.tier))
+	}
+	for _, tag := range info.tags {
+		parts = append(parts, "#"+tag)
+	}
+	return strings.Join(parts, " ")
+}
+
+// countRunKills parses the fights of runs to total their kills. Fights that fail to
+// parse count as none.
+func countRunKills(runs []string) tea.Cmd {
+	return func() tea.Msg {
+		totals := make(map[string]processor.RunTotals, len(runs))
+		for _, run := range runs {
+			files, err := processor.RunLogFiles(filepath.Join(processor.LogArchive, run))
+			if err != nil {
+				continue
+			}
+			t := processor.RunTotals{Fights: len(files)}
+			for _, f := range files {
+				if log, err := parser.ParseLog(f); err == nil {
+					t.Kills += analysis.Summarize(log).EnemyDeaths
+				}
+			}
+			totals[run] = t
+		}
+		return runKillsMsg{Totals: totals}
+	}
+}
+
+// handleRunKills fills in counted kills, re-sorts and caches them in each run.json.
+func (m *model) handleRunKills(msg runKillsMsg) tea.Cmd {
+	m.countingKills = false
+	var cmds []tea.Cmd
+	for run, t := range msg.Totals {
+		info := m.runInfo[run]
+		info.fights, info.kills = t.Fights, t.Kills
+		m.runInfo[run] = info
+		cmds = append(cmds, m.updateRunMeta(filepath.Join(processor.LogArchive, run), func(meta *processor.RunMeta) {
+			meta.Totals = &t
+		}))
+	}
+	m.sortRuns()
+	m.setStatus(i18n.T("status.kills_counted", len(msg.Totals)))
+	return tea.Batch(cmds...)
+}
+
+// cycleRunSort orders the run list by the next key, or reverses the current order.
+// Sorting by kills first counts them in runs whose run.json has no current totals.
+func (m *model) cycleRunSort(reverse bool) tea.Cmd {
+	if reverse {
+		m.runSortReversed = !m.runSortReversed
+	} else {
+		m.runSort = (m.runSort + 1) % runSortKeys
+		m.runSortReversed = false
+	}
+	m.sortRuns()
+	m.setStatus(i18n.T("status.run_sort", i18n.T("runs.sort_"+runSortNames[m.runSort])))
+	return m.countMissingKills()
+}
+
+// cycleRunGroup switches the run list grouping between none, week and commander.
+func (m *model) cycleRunGroup() {
+	m.runGroup = (m.runGroup + 1) % runGroupKeys
+	m.collapsed = nil
+	m.sortRuns()
+	if m.runGroup == groupRunsNone {
+		m.setStatus(i18n.T("status.run_ungrouped"))
+	} else {
+		m.setStatus(i18n.T("status.run_group", i18n.T("runs.group_"+runGroupNames[m.runGroup])))
+	}
+}
+
+func (m *model) countMissingKills() tea.Cmd {
+	if m.runSort != sortRunsByKills || m.countingKills {
+		return nil
+	}
+	var missing []string
+	for _, run := range m.runList {
+		if m.runInfo[run].kills < 0 {
+			missing = append(missing, run)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	m.countingKills = true
+	m.setStatus(i18n.T("status.counting_kills", len(missing)))
+	return countRunKills(missing)
+}
+
+// sortRuns orders the run list and rebuilds its rows, keeping the selected run
+// selected.
+func (m *model) sortRuns() {
+	selected := m.selectedRunRow()
+	less := func(a, b string) bool {
+		ia, ib := m.runInfo[a], m.runInfo[b]
+		switch m.runSort {
+		case sortRunsByCommander:
+			if ca, cb := m.runCommander(a), m.runCommander(b); ca != cb {
+				return strings.ToLower(ca) < strings.ToLower(cb)
+			}
+		case sortRunsByFights:
+			if ia.fights != ib.fights {
+				return ia.fights > ib.fights
+			}
+		case sortRunsByKills:
+			if ia.kills != ib.kills {
+				return ia.kills > ib.kills
+			}
+		}
+		if !ia.started.Equal(ib.started) {
+			return ia.started.After(ib.started) // Newest first
+		}
+		return a > b
+	}
+	sort.SliceStable(m.runList, func(i, j int) bool {
+		if m.runSortReversed {
+			return less(m.runList[j], m.runList[i])
+		}
+		return less(m.runList[i], m.runList[j])
+	})
+	m.buildRunRows()
+	for i, row := range m.runRows {
+		if row.run == selected.run && (row.run != "" || row.group == selected.group) {
+			m.selectedIndex = i + 1
+		}
+	}
+	m.ensureSelectionVisible()
+}
+
+// buildRunRows lays the sorted runs out under their group headers. Groups appear in
+// the order of their first run, and collapsed groups show only the header.
+func (m *model) buildRunRows() {
+	m.runRows = m.runRows[:0]
+	if m.runGroup == groupRunsNone {
+		for _, run := range m.runList {
+			m.runRows = append(m.runRows, runRow{run: run})
+		}
+		return
+	}
+	var groups []string
+	byGroup := make(map[string][]string)
+	for _, run := range m.runList {
+		g := m.runGroupOf(run)
+		if _, ok := byGroup[g]; !ok {
+			groups = append(groups, g)
+		}
+		byGroup[g] = append(byGroup[g], run)
+	}
+	for _, g := range groups {
+		runs := byGroup[g]
+		m.runRows = append(m.runRows, runRow{group: g, runs: len(runs)})
+		if m.collapsed[g] {
+			continue
+		}
+		for _, run := range runs {
+			m.runRows = append(m.runRows, runRow{run: run, group: g})
+		}
+	}
+}
+
+// runGroupOf is the header a run is listed under.
+func (m *model) runGroupOf(run string) string {
+	if m.runGroup == groupRunsByCommander {
+		return m.runCommander(run)
+	}
+	started := m.runInfo[run].started
+	if started.IsZero() {
+		return i18n.T("runs.undated")
+	}
+	day := m.locale.In(started)
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	return i18n.T("runs.week_of", m.locale.Date(monday))
+}
+
+// runCommander is who ran a run, or its whole name if that is unknown.
+func (m *model) runCommander(run string) string {
+	if commander := m.runInfo[run].commander; commander != "" {
+		return processor.CommanderLabel(commander)
+	}
+	return processor.RunLabel(run)
+}
+
+// selectedRunRow is the highlighted row of the run list, zero for "New Run".
+func (m *model) selectedRunRow() runRow {
+	if m.viewMode != runsView || m.selectedIndex < 1 || m.selectedIndex > len(m.runRows) {
+		return runRow{}
+	}
+	return m.runRows[m.selectedIndex-1]
+}
+
+// selectedRun is the highlighted run folder name, empty for "New Run" and headers.
+func (m *model) selectedRun() string {
+	return m.selectedRunRow().run
+}
+
+// toggleRunGroup collapses or expands the group under the highlighted header.
+func (m *model) toggleRunGroup(group string) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[group] = !m.collapsed[group]
+	m.buildRunRows()
+}
+
+// runHeader is the text of a group header row.
+func runHeader(row runRow, collapsed bool) string {
+	arrow := "▾"
+	if collapsed {
+		arrow = "▸"
+	}
+	return fmt.Sprintf("%s %s (%d)", arrow, row.group, row.runs)
+}
+
+// restoreRunOrder applies the run list order and grouping saved in state.json.
+func (m *model) restoreRunOrder(sortName string, reversed bool, groupName string) {
+	for i, name := range runSortNames {
+		if name == sortName {
+			m.runSort = runSortKey(i)
+		}
+	}
+	for i, name := range runGroupNames {
+		if name == groupName {
+			m.runGroup = runGroupKey(i)
+		}
+	}
+	m.runSortReversed = reversed
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runPreview sums up a run from the summaries in its index, so highlighting a run
+// in the run list never parses its fights.
+type runPreview struct {
+	fights    int
+	summaries []analysis.FightSummary // Of the fights in the index
+}
+
+// runPreviewMsg carries the preview of a run in the run list.
+type runPreviewMsg struct {
+	Run     string
+	Preview runPreview
+}
+
+func loadRunPreview(run string) tea.Cmd {
+	return func() tea.Msg {
+		runPath := filepath.Join(processor.LogArchive, run)
+		files, _ := processor.RunLogFiles(runPath)
+		idx := processor.LoadIndex(runPath)
+		p := runPreview{fights: len(files)}
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			s, ok := idx.Lookup(info)
+			if !ok {
+				continue
+			}
+			p.summaries = append(p.summaries, s)
+		}
+		return runPreviewMsg{Run: run, Preview: p}
+	}
+}
+
+// previewSelectedRun loads the preview of the highlighted run if it isn't yet.
+func (m *model) previewSelectedRun() tea.Cmd {
+	run := m.selectedRun()
+	if run == "" {
+		return nil
+	}
+	if _, ok := m.runPreviews[run]; ok {
+		return nil
+	}
+	if m.runPreviews == nil {
+		m.runPreviews = make(map[string]*runPreview)
+	}
+	m.runPreviews[run] = nil // Loading
+	return loadRunPreview(run)
+}
+
+// renderRunPreview shows the highlighted run in the right panel: when and by whom it
+// was run, its fights and its result.
+func (m *model) renderRunPreview(run string) string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(processor.RunLabel(run)) + "\n\n")
+	row := func(key string, value any) {
+		sb.WriteString(fmt.Sprintf("%-12s %v\n", i18n.T(key), value))
+	}
+	info := m.runInfo[run]
+	row("preview.commander", m.runCommander(run))
+	if !info.started.IsZero() {
+		row("preview.started", m.locale.DateTime(info.started))
+	}
+	if !info.closed.IsZero() {
+		row("preview.closed", m.locale.DateTime(info.closed))
+	}
+	if info.mapName != "" {
+		row("preview.map", info.mapName)
+	}
+	row("preview.fights", info.fights)
+	if len(info.tags) > 0 {
+		row("preview.tags", strings.Join(info.tags, ", "))
+	}
+	for _, link := range info.links {
+		row("preview.link", link)
+	}
+	p := m.runPreviews[run]
+	if p == nil {
+		sb.WriteString("\n" + i18n.T("preview.loading"))
+		return m.styles.RightPanel.Render(sb.String())
+	}
+	if len(p.summaries) > 0 {
+		var kills, deaths, squad int
+		for _, s := range p.summaries {
+			kills += s.EnemyDeaths
+			deaths += s.SquadDeaths
+			squad = max(squad, s.ZergCount())
+		}
+		r := analysis.Tally(p.summaries, m.config.OutnumberedRatio, m.config.ExcludeOutnumberedLosses)
+		if t := analysis.RunTime(p.summaries, 0); t.Span() > 0 {
+			row("preview.raid_time", formatSpan(t.Span()))
+			row("preview.duration", formatSpan(t.Combat))
+		}
+		row("preview.record", fmt.Sprintf("%dW %dL %dD", r.Wins, r.Losses, r.Draws))
+		row("preview.kd", fmt.Sprintf("%s / %s", m.locale.Number(kills), m.locale.Number(deaths)))
+		row("preview.max_squad", squad)
+	}
+	if missing := p.fights - len(p.summaries); missing > 0 {
+		sb.WriteString("\n" + i18n.T("preview.unindexed", missing) + "\n")
+	}
+	if info.notes != "" {
+		sb.WriteString("\n" + info.notes)
+	}
+	return m.styles.RightPanel.Render(sb.String())
+}
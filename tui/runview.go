@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// renderRunOverview shows aggregates for the whole run while "../" is highlighted.
+func (m *model) renderRunOverview() string {
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(i18n.T("run.overview", processor.RunLabel(m.currentRunName))) + "\n\n")
+	if mu := m.renderMatchup(); mu != "" {
+		sb.WriteString(mu + "\n\n")
+	}
+	if m.runMeta.Type == processor.RunTypeGvG {
+		sb.WriteString(m.renderGvGRounds() + "\n")
+	} else {
+		sb.WriteString(m.renderRunRecord() + "\n")
+		if t := m.renderRunTime(); t != "" {
+			sb.WriteString(t + "\n")
+		}
+		sb.WriteString("\n")
+		if eng := m.renderEngagements(); eng != "" {
+			sb.WriteString(eng + "\n")
+		}
+	}
+	if drivers := m.renderDrivers(); drivers != "" {
+		sb.WriteString(drivers + "\n")
+	}
+	sb.WriteString(m.renderSquadSizeTimeline())
+	if att := m.renderAttendance(); att != "" {
+		sb.WriteString("\n" + att)
+	}
+	return sb.String()
+}
+
+// renderAttendance shows when each squad member joined, was away and left over the
+// run, one mark per fight, e.g. "left after fight 7".
+func (m *model) renderAttendance() string {
+	const stripWidth = 30
+	fights := m.runSummaries()
+	presence := analysis.RunPresence(fights)
+	if len(fights) < 2 || len(presence) == 0 {
+		return ""
+	}
+	width := min(len(fights), stripWidth)
+	strip := func(in []bool) string {
+		var sb strings.Builder
+		for b := 0; b < width; b++ {
+			from, to := b*len(in)/width, (b+1)*len(in)/width
+			n := 0
+			for _, v := range in[from:to] {
+				if v {
+					n++
+				}
+			}
+			switch {
+			case n == to-from:
+				sb.WriteString("█")
+			case n > 0:
+				sb.WriteString("▒")
+			default:
+				sb.WriteString("·")
+			}
+		}
+		return sb.String()
+	}
+
+	timeline := i18n.T("col.timeline")
+	col := max(width, utf8.RuneCountInString(timeline))
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-7s %-*s %s", m.nameWidth(), i18n.T("run.attendance", len(presence)),
+		i18n.T("col.fights"), col, timeline, i18n.T("col.notes"))) + "\n")
+	for i, p := range presence {
+		rowStr := fmt.Sprintf("%s %-7s %s%s %s", m.nameCell(p.Account), fmt.Sprintf("%d/%d", p.Fights(), len(fights)),
+			strip(p.In), strings.Repeat(" ", col-width), attendanceNote(p))
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
+
+// attendanceNote says when someone joined, left or was away, by fight number.
+func attendanceNote(p analysis.Presence) string {
+	stints := p.Stints()
+	first, last := stints[0][0], stints[len(stints)-1][1]
+	var notes []string
+	if first > 0 {
+		notes = append(notes, i18n.T("attendance.joined", first+1))
+	}
+	for i := 1; i < len(stints); i++ {
+		from, to := stints[i-1][1]+2, stints[i][0]
+		if from == to {
+			notes = append(notes, i18n.T("attendance.away", from))
+		} else {
+			notes = append(notes, i18n.T("attendance.away_range", from, to))
+		}
+	}
+	if last < len(p.In)-1 {
+		notes = append(notes, i18n.T("attendance.left", last+1))
+	}
+	if len(notes) == 0 {
+		return i18n.T("attendance.whole")
+	}
+	return strings.Join(notes, ", ")
+}
+
+// renderSquadSizeTimeline lists squad, non-squad and enemy counts per fight with bars
+// so members bleeding off or being outnumbered stands out.
+func (m *model) renderSquadSizeTimeline() string {
+	const barWidth = 30
+	maxCount := 1
+	names := m.chronologicalLogs()
+	for _, name := range names {
+		s, _ := m.fightSummary(name)
+		if s.ZergCount() > maxCount {
+			maxCount = s.ZergCount()
+		}
+		if s.EnemyCount > maxCount {
+			maxCount = s.EnemyCount
+		}
+	}
+	bar := func(n int) string {
+		w := n * barWidth / maxCount
+		return strings.Repeat("█", w) + strings.Repeat(" ", barWidth-w)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-18s %-12s %-*s %-4s %s", i18n.T("col.squad_size"), i18n.T("col.sq_in_out"), barWidth, i18n.T("col.allies"), i18n.T("col.en"), i18n.T("col.enemies"))) + "\n")
+	for i, name := range names {
+		s, ok := m.fightSummary(name)
+		if !ok {
+			continue
+		}
+		allies := fmt.Sprintf("%-3d(%d/%d)", s.ZergCount(), s.SquadCount, s.NotInSquadCount)
+		rowStr := fmt.Sprintf("%-18s %-12s %s %-4d %s", name, allies, bar(s.ZergCount()), s.EnemyCount, bar(s.EnemyCount))
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
+
+// logFlags returns the short markers shown for a fight in the log list:
+// the W/L/D outcome and "!" when the squad was outnumbered.
+func (m *model) logFlags(name string) string {
+	s, ok := m.summaries[m.logFullPaths[name]]
+	if !ok {
+		return ""
+	}
+	flags := s.Outcome().String()
+	if s.Outnumbered(m.config.OutnumberedRatio) {
+		flags += "!"
+	}
+	return flags
+}
+
+// runSummaries returns the per-fight summaries of the current run in time order.
+func (m *model) runSummaries() []analysis.FightSummary {
+	var out []analysis.FightSummary
+	for _, name := range m.chronologicalLogs() {
+		if s, ok := m.fightSummary(name); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (m *model) renderRunRecord() string {
+	r := analysis.Tally(m.runSummaries(), m.config.OutnumberedRatio, m.config.ExcludeOutnumberedLosses)
+	line := i18n.T("run.record", r.Wins, r.Losses, r.Draws, r.OutnumberedFights)
+	if r.ExcludedLosses > 0 {
+		line += i18n.T("run.excluded", r.ExcludedLosses)
+	}
+	return line
+}
+
+// renderRunTime weighs the run's clock time against its time in combat, e.g.
+// "2h37m raid, 41m in combat", with the breaks long enough to count as time away.
+func (m *model) renderRunTime() string {
+	t := analysis.RunTime(m.runSummaries(), 0)
+	if t.Span() <= 0 {
+		return ""
+	}
+	line := i18n.T("run.time", formatSpan(t.Span()), formatSpan(t.Combat), t.Combat*100/t.Span())
+	if t.IdleBreaks > 0 {
+		line += i18n.T("run.idle", formatSpan(t.Idle), t.IdleBreaks, formatSpan(t.LongestBreak))
+	}
+	return line
+}
+
+// formatSpan writes a stretch of time to the minute, e.g. "41m" or "2h37m".
+func formatSpan(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// summarizedFights returns the summarized fights of the current run in time order,
+// with their display names.
+func (m *model) summarizedFights() (names []string, fights []analysis.FightSummary) {
+	for _, name := range m.chronologicalLogs() {
+		if s, ok := m.fightSummary(name); ok {
+			names = append(names, name)
+			fights = append(fights, s)
+		}
+	}
+	return names, fights
+}
+
+// runEngagements groups the fights of the current run into engagements. names holds
+// the display name of each summarized fight, which Engagement.First and Last index.
+func (m *model) runEngagements() (names []string, engagements []analysis.Engagement) {
+	names, fights := m.summarizedFights()
+	gap := time.Duration(m.config.EngagementGapSeconds) * time.Second
+	return names, analysis.GroupEngagements(fights, gap)
+}
+
+// engagementMarks brackets the fights of each multi-log engagement in the log list.
+func (m *model) engagementMarks() map[string]string {
+	names, engagements := m.runEngagements()
+	marks := make(map[string]string)
+	for _, e := range engagements {
+		if e.Fights() < 2 {
+			continue
+		}
+		for i := e.First; i <= e.Last; i++ {
+			switch i {
+			case e.First:
+				marks[names[i]] = "┌ "
+			case e.Last:
+				marks[names[i]] = "└ "
+			default:
+				marks[names[i]] = "│ "
+			}
+		}
+	}
+	return marks
+}
+
+// renderEngagements lists the run's engagements with their combined totals. It is
+// empty when no logs were merged, as it would only repeat the fights.
+func (m *model) renderEngagements() string {
+	names, engagements := m.runEngagements()
+	if len(engagements) == len(names) {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-18s %-7s %-9s %-8s %-8s %-8s %s", i18n.T("col.engagement"), i18n.T("col.fights"),
+		i18n.T("col.duration"), i18n.T("col.max_en"), i18n.T("col.en_dead"), i18n.T("col.sq_dead"), i18n.T("col.result"))) + "\n")
+	for i, e := range engagements {
+		t := e.Totals
+		rowStr := fmt.Sprintf("%-18s %-7d %-9s %-8d %-8d %-8d %s", names[e.First], e.Fights(),
+			formatFightTime(int(e.Duration().Milliseconds())), t.EnemyCount, t.EnemyDeaths, t.SquadDeaths, t.Outcome())
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
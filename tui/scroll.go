@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/processor"
+	"strings"
+)
+
+// leftPanelTitle returns the heading shown above the run or log list.
+func (m *model) leftPanelTitle() string {
+	title := processor.RunLabel(m.currentRunName)
+	if m.viewMode == logsView {
+		gvg := ""
+		if m.runMeta.Type == processor.RunTypeGvG {
+			gvg = " [GvG]"
+		}
+		title += gvg
+		if info := describeRun(m.currentRunName, m.runMeta); info.commander != "" {
+			commanderName := strings.Split(processor.CommanderLabel(info.commander), ".")[0]
+			title = commanderName + gvg + "\n" + clip(m.runSubtitle(info), m.styles.LeftPanel.GetWidth()-2)
+		}
+		for _, line := range m.matchupLines() {
+			title += "\n" + line
+		}
+	}
+	return title
+}
+
+// listItemHeight returns how many terminal lines item i of the left panel list takes.
+// Runs with a known commander are drawn on two lines.
+func (m *model) listItemHeight(i int) int {
+	if m.viewMode == runsView && i >= 1 && i-1 < len(m.runRows) {
+		if run := m.runRows[i-1].run; run != "" && m.runInfo[run].commander != "" {
+			return 2
+		}
+	}
+	return 1
+}
+
+// listViewportRows is the number of lines available for list items in the left panel.
+func (m *model) listViewportRows() int {
+	titleLines := strings.Count(m.leftPanelTitle(), "\n") + 2 // title plus blank line
+	if m.viewMode == logsView {
+		titleLines++ // Column header
+		titleLines += len(m.incomingRows())
+	}
+	rows := m.height - 5 - titleLines - 1 // -1 for the position indicator
+	if rows < 2 {
+		rows = 2
+	}
+	return rows
+}
+
+// visibleListRange returns the first and last item index that fit in the viewport
+// starting from listOffset.
+func (m *model) visibleListRange() (int, int) {
+	size := m.getCurrentListSize()
+	if m.height == 0 {
+		return 0, size - 1
+	}
+	rows := m.listViewportRows()
+	first := m.listOffset
+	if first >= size {
+		first = 0
+	}
+	last := first
+	used := m.listItemHeight(first)
+	for last+1 < size && used+m.listItemHeight(last+1) <= rows {
+		last++
+		used += m.listItemHeight(last)
+	}
+	return first, last
+}
+
+// ensureSelectionVisible scrolls the left panel list so the selected item is on screen.
+func (m *model) ensureSelectionVisible() {
+	size := m.getCurrentListSize()
+	if m.selectedIndex >= size {
+		m.selectedIndex = size - 1
+	}
+	if m.selectedIndex < 0 {
+		m.selectedIndex = 0
+	}
+	if m.listOffset > m.selectedIndex {
+		m.listOffset = m.selectedIndex
+	}
+	if m.height == 0 {
+		return
+	}
+	for {
+		_, last := m.visibleListRange()
+		if m.selectedIndex <= last || m.listOffset >= m.selectedIndex {
+			break
+		}
+		m.listOffset++
+	}
+}
+
+// listPageSize is how far page-up/page-down move the selection.
+func (m *model) listPageSize() int {
+	first, last := m.visibleListRange()
+	if page := last - first; page > 1 {
+		return page
+	}
+	return 1
+}
+
+func (m *model) listPositionIndicator(first, last, total int) string {
+	up, down := " ", " "
+	if first > 0 {
+		up = "▲"
+	}
+	if last < total-1 {
+		down = "▼"
+	}
+	return fmt.Sprintf("%s%s %d/%d", up, down, m.selectedIndex+1, total)
+}
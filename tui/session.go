@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"gw2-cmd-watch/logger"
+	"gw2-cmd-watch/processor"
+	"gw2-cmd-watch/state"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// restoreSession reopens the run, fight, panel and card from the previous launch, and
+// restores the run list order.
+// Runs that were deleted in the meantime are ignored.
+func (m *model) restoreSession(s state.State) {
+	if s.FocusedPanel == "right" {
+		m.focusedPanel = rightPanel
+	}
+	if s.SelectedCard >= 0 && s.SelectedCard < len(m.cardLayout.flat()) {
+		m.selectedCard = s.SelectedCard
+	}
+	m.restoreRunOrder(s.RunSort, s.RunSortReversed, s.RunGroup)
+	if s.LastRun == "" {
+		return
+	}
+	runPath := filepath.Join(processor.LogArchive, s.LastRun)
+	if info, err := os.Stat(runPath); err != nil || !info.IsDir() {
+		m.focusedPanel = leftPanel
+		return
+	}
+	m.viewMode = logsView
+	m.currentRunPath = runPath
+	m.currentRunName = s.LastRun
+	m.pendingSelectLog = s.SelectedLog
+	m.restoringRun = true
+}
+
+// SessionState captures what should be restored on the next launch from the final model
+// returned by tea.Program.Run.
+func SessionState(final tea.Model) (state.State, bool) {
+	m, ok := final.(model)
+	if !ok {
+		return state.State{}, false
+	}
+	s := state.State{SelectedCard: m.selectedCard, FocusedPanel: "left"}
+	if m.focusedPanel == rightPanel {
+		s.FocusedPanel = "right"
+	}
+	if m.runSort != sortRunsByDate {
+		s.RunSort = runSortNames[m.runSort]
+	}
+	if m.runGroup != groupRunsNone {
+		s.RunGroup = runGroupNames[m.runGroup]
+	}
+	s.RunSortReversed = m.runSortReversed
+	if m.viewMode == logsView && m.currentRunPath != "" {
+		s.LastRun = filepath.Base(m.currentRunPath)
+		if m.selectedIndex > 0 && m.selectedIndex <= len(m.logList) {
+			s.SelectedLog = m.logList[m.selectedIndex-1]
+		}
+	}
+	return s, true
+}
+
+// CloseLiveRun records the end of the run that was live when the program exited and
+// runs the OnRunClosed hooks for it. It blocks until they finish.
+func CloseLiveRun(final tea.Model) {
+	m, ok := final.(model)
+	if !ok || m.liveRunPath == "" {
+		return
+	}
+	closed := time.Now().UTC()
+	if err := processor.UpdateRunMeta(m.liveRunPath, func(meta *processor.RunMeta) {
+		meta.Closed = closed
+	}); err != nil {
+		logger.Warn("failed to save run settings: %v", err)
+	}
+	for _, fn := range runClosedHooks {
+		fn(m.liveRunPath)
+	}
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tallyTickInterval is how often the "last fight" age in the status bar is redrawn.
+const tallyTickInterval = time.Minute
+
+// liveTally is the running score of the live run, shown at the right of the status
+// bar so it stays visible whatever the panels show.
+type liveTally struct {
+	runPath string
+	fights  int
+	kills   int // Enemies killed by the squad
+	deaths  int // Squad deaths
+	last    time.Time
+}
+
+// tallyTickMsg redraws the status bar so the age of the last fight keeps up.
+type tallyTickMsg struct{}
+
+// tallyFight adds an archived fight to the tally if it went into the live run. When
+// the live run is the one being viewed the tally is recounted from its fights, so
+// points of view merged into one fight count once and a run made live again starts
+// from what it already has.
+func (m *model) tallyFight(runPath string, log *parser.ParsedLog) tea.Cmd {
+	if runPath != m.liveRunPath {
+		return nil
+	}
+	if runPath == m.currentRunPath {
+		m.tally = liveTally{runPath: runPath}
+		for _, s := range m.runSummaries() {
+			m.tally.add(s)
+		}
+	} else {
+		if m.tally.runPath != runPath {
+			m.tally = liveTally{runPath: runPath}
+		}
+		m.tally.add(analysis.Summarize(log))
+	}
+	if m.tallyTicking {
+		return nil
+	}
+	m.tallyTicking = true
+	return tallyTick()
+}
+
+func (t *liveTally) add(s analysis.FightSummary) {
+	t.fights++
+	t.kills += s.EnemyDeaths
+	t.deaths += s.SquadDeaths
+	end := s.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if end.After(t.last) {
+		t.last = end
+	}
+}
+
+func tallyTick() tea.Cmd {
+	return tea.Tick(tallyTickInterval, func(time.Time) tea.Msg { return tallyTickMsg{} })
+}
+
+// handleTallyTick keeps ticking while there is a live run to show.
+func (m *model) handleTallyTick() tea.Cmd {
+	if m.liveRunPath == "" {
+		m.tallyTicking = false
+		return nil
+	}
+	return tallyTick()
+}
+
+// tallyText is the live tally for the status bar, "" when no run is live.
+func (m *model) tallyText() string {
+	if m.liveRunPath == "" || m.tally.runPath != m.liveRunPath || m.tally.fights == 0 {
+		return ""
+	}
+	return i18n.T("tally.line", m.tally.fights, m.locale.Number(m.tally.kills), m.locale.Number(m.tally.deaths),
+		fightAge(time.Since(m.tally.last)))
+}
+
+// fightAge writes how long ago the last fight ended, e.g. "4m" or "1h05m".
+func fightAge(d time.Duration) string {
+	if d < time.Minute {
+		return i18n.T("tally.just_now")
+	}
+	return i18n.T("tally.ago", formatSpan(d))
+}
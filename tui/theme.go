@@ -1,6 +1,14 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // ShadesOfPurple is a Lipgloss color palette for the "Shades of Purple" theme.
 // It includes colors for various UI elements and code highlighting.
@@ -25,6 +33,23 @@ type ShadesOfPurple struct {
 	AccentLightPurple lipgloss.Color
 	AccentDarkPurple  lipgloss.Color
 	AccentTeal        lipgloss.Color
+
+	// Professions overrides the class colors of spec codes on the cards, by core
+	// profession, e.g. {"Guardian": "#72c1d9"}. Unset professions use the game's colors.
+	Professions map[string]lipgloss.Color
+}
+
+// professionColors are the class colors players know from the game and arcdps.
+var professionColors = map[string]lipgloss.Color{
+	"Guardian":     lipgloss.Color("#72c1d9"),
+	"Revenant":     lipgloss.Color("#d16e5a"),
+	"Warrior":      lipgloss.Color("#ffd166"),
+	"Engineer":     lipgloss.Color("#d09c59"),
+	"Ranger":       lipgloss.Color("#8cdc82"),
+	"Thief":        lipgloss.Color("#c08f95"),
+	"Elementalist": lipgloss.Color("#f68a87"),
+	"Mesmer":       lipgloss.Color("#b679d5"),
+	"Necromancer":  lipgloss.Color("#52a76f"),
 }
 
 // NewShadesOfPurple creates and returns a new ShadesOfPurple color palette.
@@ -52,3 +77,214 @@ func NewShadesOfPurple() ShadesOfPurple {
 		AccentTeal:        lipgloss.Color("#2ee2fa"),
 	}
 }
+
+// DefaultThemeName is used when the config does not name a theme.
+const DefaultThemeName = "shades-of-purple"
+
+// builtinThemes maps the names accepted in config.json to their palettes.
+var builtinThemes = map[string]func() ShadesOfPurple{
+	"shades-of-purple": NewShadesOfPurple,
+	"light":            NewLightTheme,
+	"high-contrast":    NewHighContrastTheme,
+	"midnight":         NewMidnightTheme,
+	"deuteranopia":     NewDeuteranopiaTheme,
+}
+
+// ThemeNames returns the built-in theme names in a stable order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewLightTheme is a palette for terminals with a light background.
+func NewLightTheme() ShadesOfPurple {
+	return ShadesOfPurple{
+		Background:        lipgloss.Color("#fafafa"),
+		Foreground:        lipgloss.Color("#1f1d2e"),
+		LightBlue:         lipgloss.Color("#3d5a98"),
+		AccentBlue:        lipgloss.Color("#2851a3"),
+		AccentPurple:      lipgloss.Color("#6f2dbd"),
+		AccentCyan:        lipgloss.Color("#00797c"),
+		AccentGreen:       lipgloss.Color("#2e7d32"),
+		AccentYellow:      lipgloss.Color("#9a6700"),
+		AccentRed:         lipgloss.Color("#c62828"),
+		Comment:           lipgloss.Color("#7b1fa2"),
+		Gray:              lipgloss.Color("#8a8799"),
+		GradientColor1:    lipgloss.Color("#4d21fc"),
+		GradientColor2:    lipgloss.Color("#3d5a98"),
+		GradientColor3:    lipgloss.Color("#c62828"),
+		AccentYellowAlt:   lipgloss.Color("#b25e00"),
+		AccentOrange:      lipgloss.Color("#c25100"),
+		AccentPink:        lipgloss.Color("#ad1457"),
+		AccentLightPurple: lipgloss.Color("#8e44ad"),
+		AccentDarkPurple:  lipgloss.Color("#e4dcfa"),
+		AccentTeal:        lipgloss.Color("#00838f"),
+		// The game's class colors are too pale on white
+		Professions: map[string]lipgloss.Color{
+			"Guardian":     lipgloss.Color("#1b7a96"),
+			"Revenant":     lipgloss.Color("#a23d2a"),
+			"Warrior":      lipgloss.Color("#9a7300"),
+			"Engineer":     lipgloss.Color("#8a5a1c"),
+			"Ranger":       lipgloss.Color("#2f7d27"),
+			"Thief":        lipgloss.Color("#8a4f58"),
+			"Elementalist": lipgloss.Color("#c0392b"),
+			"Mesmer":       lipgloss.Color("#7b3fa0"),
+			"Necromancer":  lipgloss.Color("#1e6b3b"),
+		},
+	}
+}
+
+// NewHighContrastTheme uses pure, saturated colors for maximum legibility.
+func NewHighContrastTheme() ShadesOfPurple {
+	return ShadesOfPurple{
+		Background:        lipgloss.Color("#000000"),
+		Foreground:        lipgloss.Color("#ffffff"),
+		LightBlue:         lipgloss.Color("#87cefa"),
+		AccentBlue:        lipgloss.Color("#5fafff"),
+		AccentPurple:      lipgloss.Color("#d787ff"),
+		AccentCyan:        lipgloss.Color("#00ffff"),
+		AccentGreen:       lipgloss.Color("#00ff00"),
+		AccentYellow:      lipgloss.Color("#ffff00"),
+		AccentRed:         lipgloss.Color("#ff0000"),
+		Comment:           lipgloss.Color("#ff00ff"),
+		Gray:              lipgloss.Color("#c0c0c0"),
+		GradientColor1:    lipgloss.Color("#0000ff"),
+		GradientColor2:    lipgloss.Color("#00ffff"),
+		GradientColor3:    lipgloss.Color("#ff0000"),
+		AccentYellowAlt:   lipgloss.Color("#ffff5f"),
+		AccentOrange:      lipgloss.Color("#ff8700"),
+		AccentPink:        lipgloss.Color("#ff5fd7"),
+		AccentLightPurple: lipgloss.Color("#d7afff"),
+		AccentDarkPurple:  lipgloss.Color("#303030"),
+		AccentTeal:        lipgloss.Color("#00d7d7"),
+	}
+}
+
+// NewMidnightTheme is a muted dark palette for long sessions.
+func NewMidnightTheme() ShadesOfPurple {
+	return ShadesOfPurple{
+		Background:        lipgloss.Color("#0f111a"),
+		Foreground:        lipgloss.Color("#c8d3f5"),
+		LightBlue:         lipgloss.Color("#82aaff"),
+		AccentBlue:        lipgloss.Color("#65bcff"),
+		AccentPurple:      lipgloss.Color("#c099ff"),
+		AccentCyan:        lipgloss.Color("#86e1fc"),
+		AccentGreen:       lipgloss.Color("#c3e88d"),
+		AccentYellow:      lipgloss.Color("#ffc777"),
+		AccentRed:         lipgloss.Color("#ff757f"),
+		Comment:           lipgloss.Color("#7a88cf"),
+		Gray:              lipgloss.Color("#636da6"),
+		GradientColor1:    lipgloss.Color("#3e68d7"),
+		GradientColor2:    lipgloss.Color("#82aaff"),
+		GradientColor3:    lipgloss.Color("#ff757f"),
+		AccentYellowAlt:   lipgloss.Color("#ffdb8e"),
+		AccentOrange:      lipgloss.Color("#ff966c"),
+		AccentPink:        lipgloss.Color("#fca7ea"),
+		AccentLightPurple: lipgloss.Color("#c3a6ff"),
+		AccentDarkPurple:  lipgloss.Color("#2f334d"),
+		AccentTeal:        lipgloss.Color("#4fd6be"),
+	}
+}
+
+// LoadTheme resolves a theme setting from config.json. The value is either a built-in
+// theme name or a path to a JSON file whose keys are ShadesOfPurple field names, e.g.
+// {"Background": "#ffffff", "AccentCyan": "#007acc"}. Fields missing from the file keep
+// their Shades of Purple value, so a custom theme only needs to list what it changes.
+func LoadTheme(setting string) (ShadesOfPurple, error) {
+	if setting == "" {
+		setting = DefaultThemeName
+	}
+	if newTheme, ok := builtinThemes[strings.ToLower(setting)]; ok {
+		return newTheme(), nil
+	}
+
+	data, err := os.ReadFile(setting)
+	if err != nil {
+		return NewShadesOfPurple(), fmt.Errorf("theme %q is not a built-in theme (%s) or a readable file: %w", setting, strings.Join(ThemeNames(), ", "), err)
+	}
+	theme := NewShadesOfPurple()
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return NewShadesOfPurple(), fmt.Errorf("invalid theme file %s: %w", setting, err)
+	}
+	return theme, nil
+}
+
+// NewDeuteranopiaTheme is a color-blind friendly palette based on the Okabe-Ito colors.
+// Good and bad values map to blue and vermillion instead of green and red.
+func NewDeuteranopiaTheme() ShadesOfPurple {
+	return ShadesOfPurple{
+		Background:        lipgloss.Color("#1c1c28"),
+		Foreground:        lipgloss.Color("#f0f0f0"),
+		LightBlue:         lipgloss.Color("#56b4e9"),
+		AccentBlue:        lipgloss.Color("#0072b2"),
+		AccentPurple:      lipgloss.Color("#cc79a7"),
+		AccentCyan:        lipgloss.Color("#56b4e9"),
+		AccentGreen:       lipgloss.Color("#0072b2"),
+		AccentYellow:      lipgloss.Color("#f0e442"),
+		AccentRed:         lipgloss.Color("#d55e00"),
+		Comment:           lipgloss.Color("#cc79a7"),
+		Gray:              lipgloss.Color("#8c8c99"),
+		GradientColor1:    lipgloss.Color("#0072b2"),
+		GradientColor2:    lipgloss.Color("#56b4e9"),
+		GradientColor3:    lipgloss.Color("#d55e00"),
+		AccentYellowAlt:   lipgloss.Color("#f0e442"),
+		AccentOrange:      lipgloss.Color("#e69f00"),
+		AccentPink:        lipgloss.Color("#cc79a7"),
+		AccentLightPurple: lipgloss.Color("#cc79a7"),
+		AccentDarkPurple:  lipgloss.Color("#33334d"),
+		AccentTeal:        lipgloss.Color("#009e73"),
+	}
+}
+
+// ColorRoles names colors by what they mean rather than what they look like.
+// Rendering code should use these so palettes (including color-blind ones) can
+// change the meaning-to-color mapping in one place.
+type ColorRoles struct {
+	Text              lipgloss.Color // Default foreground
+	TextOnAccent      lipgloss.Color // Text drawn on top of a filled accent background
+	Good              lipgloss.Color // Wins, values meeting a goal
+	Bad               lipgloss.Color // Losses, errors, values missing a goal badly
+	Warning           lipgloss.Color // Warnings, values slightly below a goal
+	Highlight         lipgloss.Color // Focused panels and selected items
+	Title             lipgloss.Color // Card and panel titles
+	Muted             lipgloss.Color // Unfocused borders and help text
+	RowStripe         lipgloss.Color // Background of alternating table rows
+	Commander         lipgloss.Color // Commander names in the run list
+	CommanderSelected lipgloss.Color // Commander name of the selected run
+
+	Professions map[string]lipgloss.Color // Class colors by core profession
+}
+
+// Roles maps the palette onto semantic color roles.
+func (t ShadesOfPurple) Roles() ColorRoles {
+	return ColorRoles{
+		Text:              t.Foreground,
+		TextOnAccent:      t.Background,
+		Good:              t.AccentGreen,
+		Bad:               t.AccentRed,
+		Warning:           t.AccentOrange,
+		Highlight:         t.AccentCyan,
+		Title:             t.AccentYellow,
+		Muted:             t.Gray,
+		RowStripe:         t.AccentDarkPurple,
+		Commander:         t.AccentOrange,
+		CommanderSelected: t.AccentYellowAlt,
+		Professions:       t.professions(),
+	}
+}
+
+// professions is the game's class colors with the palette's overrides applied.
+func (t ShadesOfPurple) professions() map[string]lipgloss.Color {
+	colors := make(map[string]lipgloss.Color, len(professionColors))
+	for name, c := range professionColors {
+		colors[name] = c
+	}
+	for name, c := range t.Professions {
+		colors[name] = c
+	}
+	return colors
+}
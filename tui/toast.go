@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/processor"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// defaultToastSeconds is how long a toast stays up when toast_seconds is not set.
+	defaultToastSeconds = 4
+	// maxToasts is how many toasts are stacked at once. Older ones make way.
+	maxToasts = 3
+)
+
+// toast is a short notice shown in the top right corner for a few seconds, e.g. that
+// a fight was archived. Unlike the status line it doesn't replace what was there.
+type toast struct {
+	id   int
+	text string
+}
+
+// ToastMsg shows a toast.
+type ToastMsg string
+
+// toastExpiredMsg takes a toast down.
+type toastExpiredMsg struct{ id int }
+
+// toast shows text for toast_seconds and keeps it in the event log.
+func (m *model) toast(text string) tea.Cmd {
+	m.events.add(eventInfo, text)
+	m.nextToastID++
+	t := toast{id: m.nextToastID, text: text}
+	m.toasts = append(m.toasts, t)
+	if len(m.toasts) > maxToasts {
+		m.toasts = m.toasts[len(m.toasts)-maxToasts:]
+	}
+	seconds := m.config.ToastSeconds
+	if seconds <= 0 {
+		seconds = defaultToastSeconds
+	}
+	return tea.Tick(time.Duration(seconds)*time.Second, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: t.id}
+	})
+}
+
+// toastArchived announces a fight added to the archive with its start time and map.
+func (m *model) toastArchived(msg LogfileArchivedMsg) tea.Cmd {
+	where := msg.Map
+	if where == "" {
+		where = analysis.MapName(msg.Log.FightName)
+	}
+	when := processor.DisplayName(msg.FullPath)
+	if start := analysis.Summarize(msg.Log).Start; !start.IsZero() {
+		when = m.locale.Time(start)
+	}
+	return m.toast(i18n.T("toast.archived", when, where))
+}
+
+func (m *model) handleToastExpired(msg toastExpiredMsg) {
+	for i, t := range m.toasts {
+		if t.id == msg.id {
+			m.toasts = append(m.toasts[:i], m.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// renderToasts draws the toasts, newest at the top, over the top right corner of
+// screen.
+func (m *model) renderToasts(screen string) string {
+	if len(m.toasts) == 0 {
+		return screen
+	}
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.colors.Highlight).
+		Foreground(m.colors.Text).
+		Padding(0, 1)
+	width := max(min(m.width/2, 48), 12)
+	right := lipgloss.Width(screen) - 1 // Inside the panel border
+	top := 1
+	for i := len(m.toasts) - 1; i >= 0; i-- {
+		box := style.Render(clip(m.toasts[i].text, width-style.GetHorizontalFrameSize()))
+		screen = placeOver(screen, box, top, max(right-lipgloss.Width(box), 0), nil)
+		top += lipgloss.Height(box)
+	}
+	return screen
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/parser"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// sparkBlocks are the bar heights of a sparkline, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline widths on the dashboard and in the expanded card. Longer runs are averaged
+// into this many buckets.
+const (
+	sparkWidth     = 16
+	sparkWidthFull = 40
+)
+
+// sparkline draws values scaled to their own maximum, averaging them into at most
+// width buckets. NaN marks a fight the player missed and is drawn as a gap.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if width > len(values) {
+		width = len(values)
+	}
+	buckets := make([]float64, width)
+	for b := range buckets {
+		from, to := b*len(values)/width, (b+1)*len(values)/width
+		sum, n := 0.0, 0
+		for _, v := range values[from:to] {
+			if !math.IsNaN(v) {
+				sum += v
+				n++
+			}
+		}
+		buckets[b] = math.NaN()
+		if n > 0 {
+			buckets[b] = sum / float64(n)
+		}
+	}
+	maxV := 0.0
+	for _, v := range buckets {
+		if v > maxV {
+			maxV = v
+		}
+	}
+	return drawSparkline(buckets, maxV)
+}
+
+// drawSparkline draws one block per value scaled to maxV, so several lines drawn with
+// the same maxV can be compared.
+func drawSparkline(buckets []float64, maxV float64) string {
+	var sb strings.Builder
+	for _, v := range buckets {
+		switch {
+		case math.IsNaN(v):
+			sb.WriteRune(' ')
+		case maxV == 0:
+			sb.WriteRune(sparkBlocks[0])
+		default:
+			sb.WriteRune(sparkBlocks[int(v/maxV*float64(len(sparkBlocks)-1)+0.5)])
+		}
+	}
+	return sb.String()
+}
+
+// playerTrend is one account's stats per fight of the run, NaN where they were absent.
+type playerTrend struct {
+	name                       string
+	damage, cleanses, deaths   []float64
+	totalDamage, totalCleanses int
+}
+
+// playerTrends collects per-fight stats for everyone in the current run, by damage.
+func (m *model) playerTrends() []*playerTrend {
+	fights := m.runSummaries()
+	byAccount := make(map[string]*playerTrend)
+	var out []*playerTrend
+	for i, s := range fights {
+		for _, p := range s.Players {
+			t := byAccount[p.Account]
+			if t == nil {
+				t = &playerTrend{
+					damage:   nanSlice(len(fights)),
+					cleanses: nanSlice(len(fights)),
+					deaths:   nanSlice(len(fights)),
+				}
+				byAccount[p.Account] = t
+				out = append(out, t)
+			}
+			t.name = p.Name
+			t.damage[i], t.cleanses[i], t.deaths[i] = float64(p.Damage), float64(p.Cleanses), float64(p.Deaths)
+			t.totalDamage += p.Damage
+			t.totalCleanses += p.Cleanses
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].totalDamage > out[j].totalDamage })
+	return out
+}
+
+func nanSlice(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
+
+// buildTrendsCard shows each player's damage, cleanses and deaths over the fights of
+// the run, so players fading late in the night stand out.
+func (m *model) buildTrendsCard(log *parser.ParsedLog) string {
+	trends := m.playerTrends()
+	width := sparkWidth
+	if m.showAllRows {
+		width = sparkWidthFull
+	}
+	width = min(width, len(m.runSummaries()))
+	dmgLabel, cleanseLabel := i18n.T("col.damage"), i18n.T("col.cleanses")
+	dmgCol := max(width, utf8.RuneCountInString(dmgLabel))
+	cleanseCol := max(width, utf8.RuneCountInString(cleanseLabel))
+
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-*s %-*s %s", m.nameWidth(), i18n.T("card.trends"),
+		dmgCol, dmgLabel, cleanseCol, cleanseLabel, i18n.T("col.deaths"))) + "\n")
+	for i, t := range trends {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		rowStr := fmt.Sprintf("%s %-*s %-*s %s", m.nameCell(t.name),
+			dmgCol, sparkline(t.damage, width), cleanseCol, sparkline(t.cleanses, width), sparkline(t.deaths, width))
+		m.writeRow(&sb, i, rowStr)
+	}
+	if len(trends) == 0 {
+		sb.WriteString(i18n.T("trends.none") + "\n")
+	}
+	return sb.String()
+}
+
+// buildRallybotCard ranks the run's squad by deaths per fight, with the enemy rallies
+// their deaths handed out. Like the trends card it covers the whole run.
+func (m *model) buildRallybotCard(log *parser.ParsedLog) string {
+	ranked := analysis.Rallybots(m.runSummaries())
+	var sb strings.Builder
+	sb.WriteString(m.styles.CardTitle.Render(fmt.Sprintf("%-*s %-8s %-8s %s", m.nameWidth(), i18n.T("card.rallybot"),
+		i18n.T("col.deaths_fight"), i18n.T("col.deaths"), i18n.T("col.rallies"))) + "\n")
+	if len(ranked) == 0 {
+		sb.WriteString(i18n.T("rallybot.none") + "\n")
+		return sb.String()
+	}
+	for i, r := range ranked {
+		if !m.showAllRows && i >= 5 {
+			break
+		}
+		rowStr := fmt.Sprintf("%s %-8.2f %-8s %d", m.nameCell(r.Name), r.DeathsPerFight(), fmt.Sprintf("%d/%d", r.Deaths, r.Fights), r.Rallies)
+		m.writeRow(&sb, i, rowStr)
+	}
+	return sb.String()
+}
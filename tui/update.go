@@ -2,11 +2,15 @@ package tui
 
 import (
 	"fmt"
+	"gw2-cmd-watch/analysis"
+	"gw2-cmd-watch/i18n"
+	"gw2-cmd-watch/logger"
 	"gw2-cmd-watch/parser"
+	"gw2-cmd-watch/positions"
 	"gw2-cmd-watch/processor"
-	"os"
+	"gw2-cmd-watch/server"
+	"net/url"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
@@ -14,155 +18,313 @@ import (
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	newModel, cmd := m.update(msg)
+	if nm, ok := newModel.(model); ok {
+		// Keep the left panel selection on screen whatever changed it
+		nm.ensureSelectionVisible()
+		// Switching between WvW and PvE logs can leave the card index past the layout
+		if n := len(nm.activeLayout().flat()); nm.selectedCard >= n {
+			nm.selectedCard = n - 1
+		}
+		return nm, cmd
+	}
+	return newModel, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
-	// Confirmation check takes priority
-	if m.confirming {
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			switch msg.String() {
-			case "y", "Y":
-				switch m.confirmationType {
-				case confirmDeleteRun:
-					cmds = append(cmds, deleteRun(m.itemToDelete))
-					m.status = fmt.Sprintf("Deleting run: %s", filepath.Base(m.itemToDelete))
-				case confirmDeleteLog:
-					fullPath := m.logFullPaths[m.itemToDelete]
-					cmds = append(cmds, deleteLogFiles(fullPath))
-					// Optimistically remove from UI
-					delete(m.logs, fullPath)
-					delete(m.logFullPaths, m.itemToDelete)
-					for i, name := range m.logList {
-						if name == m.itemToDelete {
-							m.logList = append(m.logList[:i], m.logList[i+1:]...)
-							break
-						}
-					}
-					if m.selectedIndex >= len(m.logList)+1 {
-						m.selectedIndex = len(m.logList)
-					}
-					m.status = fmt.Sprintf("Deleted log: %s", m.itemToDelete)
-				case confirmAppUpdate:
-					cmds = append(cmds, openFile(m.updateURL))
-					m.status = "Opening browser to download update..."
-				}
-				m.confirming = false
-				m.itemToDelete = ""
-				m.updateURL = ""
-			case "n", "N", "esc":
-				m.confirming = false
-				m.itemToDelete = ""
-				m.updateURL = ""
-				m.status = "Action cancelled."
-			}
+	// An open dialog takes every key
+	if m.dialog != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.handleDialogKeys(msg)
 		}
-		return m, tea.Batch(cmds...)
 	}
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.styles.RightPanel = m.styles.RightPanel.Width(m.width - m.styles.LeftPanel.GetWidth() - m.styles.LeftPanel.GetHorizontalFrameSize())
+		m.layoutPanels()
 		m.styles.RightPanel = m.styles.RightPanel.Height(m.height - 5)
 		return m, nil
 
 	case UpdateAvailableMsg:
-		m.confirming = true
-		m.confirmationType = confirmAppUpdate
-		m.updateURL = msg.URL
-		m.status = "A new version is available! Open download page? (y/N)"
+		m.confirmAppUpdate(msg.URL)
 		return m, nil
 
 	case RunsLoadedMsg:
 		m.runList = msg.Runs
-		m.status = fmt.Sprintf("Found %d archived runs.", len(m.runList))
+		m.runInfo = msg.info
+		m.runPreviews = nil
+		m.sortRuns()
+		if !m.restoringRun {
+			m.setStatus(i18n.T("status.found_runs", len(m.runList)))
+		}
+		return m, tea.Batch(m.countMissingKills(), m.previewSelectedRun())
+
+	case runPreviewMsg:
+		if _, ok := m.runPreviews[msg.Run]; ok {
+			m.runPreviews[msg.Run] = &msg.Preview
+		}
 		return m, nil
 
 	case SingleLogParsedMsg:
-		// Add the log to the model as it's parsed
-		m.logs[msg.FullPath] = msg.Log
-		displayName := strings.Replace(filepath.Base(msg.FullPath), "_detailed_wvw_kill.json", "", 1)
-		m.logList = append(m.logList, displayName)
-		m.logFullPaths[displayName] = msg.FullPath
-		m.status = fmt.Sprintf("Loading... %d logs parsed.", len(m.logList))
+		next := m.parseDone(msg.FullPath)
+		if filepath.Dir(msg.FullPath) != m.currentRunPath {
+			return m, next // The run was left meanwhile
+		}
+		m.rememberLog(msg.FullPath, msg.Log)
+		if _, ok := m.summaries[msg.FullPath]; ok {
+			return m, next // A fight loaded again on selection
+		}
+		// Fill in the fight's row as it's parsed
+		m.summaries[msg.FullPath] = analysis.Summarize(msg.Log)
+		m.indexSummary(msg.FullPath, m.summaries[msg.FullPath])
+		for _, w := range parser.CompatWarnings(msg.Log) {
+			m.warnOnce(w)
+		}
+		m.sortLogList()
+		if !m.runLoading {
+			return m, tea.Batch(next, m.saveIndex())
+		}
+		m.status = i18n.T("status.loading_logs", len(m.summaries), len(m.logList))
+		return m, next
+
+	case archiveChangedMsg:
+		return m, m.handleArchiveChanged(msg)
+
+	case runReconciledMsg:
+		return m, m.handleRunReconciled(msg)
+
+	case eiConfMsg:
+		m.handleEIConf(msg)
 		return m, nil
 
+	case eiConfFixedMsg:
+		m.handleEIConfFixed(msg)
+		return m, nil
+
+	case LogParseFailedMsg:
+		return m, m.handleLogParseFailed(msg)
+
+	case fightReparsedMsg:
+		return m, m.handleFightReparsed(msg)
+
 	case AllLogsParsedMsg:
-		// Now that all logs are loaded, sort the list
-		sort.Strings(m.logList)
-		m.status = fmt.Sprintf("Loaded %d logs from run.", len(m.logList))
-		if len(m.logList) > 0 {
-			m.selectedIndex = 1 // Select the first log
-		} else {
-			m.selectedIndex = 0 // Select ../
+		m.setStatus(i18n.T("status.loaded_logs", len(m.logList)))
+		if len(m.brokenLogs) > 0 {
+			m.setStatus(i18n.T("status.loaded_logs_broken", len(m.logList), len(m.brokenLogs), brokenGlyph))
 		}
-		return m, nil
+		if m.restoringRun {
+			m.restoringRun = false
+			m.setStatus(i18n.T("status.restored", processor.RunLabel(m.currentRunName)))
+		}
+		return m, tea.Batch(m.saveIndex(), m.loadSelectedLog())
+
+	case runFilesMsg:
+		return m, m.handleRunFiles(msg)
 
 	case TempLogProcessedMsg:
 		// This is the entry point for a new, live log.
 		// We parse it here to decide where it goes.
 		parsedLog, err := parser.ParseLog(msg.TempPath)
 		if err != nil {
-			m.err = err
+			m.setError(err)
 			return m, nil
 		}
+		for _, w := range parser.CompatWarnings(parsedLog) {
+			logger.Warn("%s: %s", filepath.Base(msg.TempPath), w)
+			m.warnOnce(w)
+		}
 
 		var finalRunPath string
-		isNewRun := m.viewMode == runsView || (m.viewMode == logsView && len(m.logList) >= 30)
+		var closed, meta, matchup, objectives tea.Cmd
+		isNewRun := m.newFightStartsRun()
+		m.newRunPending = false
 
 		if isNewRun {
 			m.viewMode = logsView
 			m.clearCurrentRun()
 
 			commander := "UnknownCommander"
-			for _, p := range parsedLog.Players {
-				if p.HasCommanderTag {
-					commander = p.Account
-					break
-				}
+			if c := findCommander(parsedLog, m.config.MyAccount); c != nil {
+				commander = parser.RealAccount(c.Account)
+			}
+			// Name the run after the first fight rather than when EI finished with it
+			started := analysis.Summarize(parsedLog).Start
+			if started.IsZero() {
+				started = time.Now()
 			}
-			timestamp := time.Now().Format("2006-01-02_15-04-05")
-			runName := fmt.Sprintf("%s_%s", commander, timestamp)
+			firstMap := m.mapAt(started)
+			if firstMap == "" {
+				firstMap = analysis.MapName(parsedLog.FightName)
+			}
+			runName := processor.RunName(commander, m.runTimestamp(started), firstMap, m.runNameTier())
 			finalRunPath = filepath.Join(processor.LogArchive, runName)
 			m.currentRunPath = finalRunPath
 			m.currentRunName = runName
-			m.status = "New run started."
+			m.setStatus(i18n.T("status.new_run"))
+			meta = m.updateRunMeta(finalRunPath, func(meta *processor.RunMeta) {
+				meta.Created = time.Now().UTC()
+				meta.Started = started.UTC()
+				meta.Commander = commander
+				meta.Map = firstMap
+			})
+			matchup = m.fetchMatchup(finalRunPath)
+			objectives = m.startObjectivePolling(finalRunPath)
 		} else {
 			// Add to the currently viewed run
 			finalRunPath = m.currentRunPath
 		}
-		return m, archiveLogFile(msg.TempPath, finalRunPath, parsedLog)
+		if m.liveRunPath != "" && m.liveRunPath != finalRunPath {
+			closed = m.closeRun(m.liveRunPath)
+		}
+		if !isNewRun && m.liveRunPath != finalRunPath {
+			// A fight added to an older run makes it live again
+			meta = m.updateRunMeta(finalRunPath, func(meta *processor.RunMeta) {
+				meta.Closed = time.Time{}
+			})
+		}
+		m.liveRunPath = finalRunPath
+		m.arriving[processor.DisplayName(msg.TempPath)] = finalRunPath
+		mapName := m.mapAt(analysis.Summarize(parsedLog).Start)
+		return m, tea.Batch(closed, meta, matchup, objectives, m.startSpinner(),
+			archiveLogFile(msg.TempPath, msg.LogPath, finalRunPath, parsedLog, mapName))
 
 	case LogfileArchivedMsg:
 		// This message confirms the file has been moved. Now we add it to the UI.
 		// We only perform the auto-selection if the archived log belongs to the run we are currently viewing.
 		archivedRunPath := filepath.Dir(msg.FullPath)
+		displayName := processor.DisplayName(msg.FullPath)
+		delete(m.arriving, displayName)
+		mapName := ""
+		var index, handoff tea.Cmd
 		if archivedRunPath == m.currentRunPath {
-			m.logs[msg.FullPath] = msg.Log
-			displayName := strings.Replace(filepath.Base(msg.FullPath), "_detailed_wvw_kill.json", "", 1)
+			m.rememberLog(msg.FullPath, msg.Log)
+			m.summaries[msg.FullPath] = analysis.Summarize(msg.Log)
+			m.indexSummary(msg.FullPath, m.summaries[msg.FullPath])
+			index = m.saveIndex()
 			if _, exists := m.logFullPaths[displayName]; !exists {
 				m.logList = append(m.logList, displayName)
 			}
 			m.logFullPaths[displayName] = msg.FullPath
-			sort.Strings(m.logList)
-			// Find the new index of the just-added log to select it
+			m.sortLogList()
+			// Find the new index of the just-added log to select it, or of the fight
+			// it was merged into as another point of view
+			shown := m.povPrimary(displayName)
 			for i, name := range m.logList {
-				if name == displayName {
+				if name == shown {
 					m.selectedIndex = i + 1 // +1 for ../
 					break
 				}
 			}
 			m.selectedCard = 0
-			m.status = fmt.Sprintf("New log processed: %s", displayName)
+			mapName = msg.Map
+			handoff = m.noteHandoff(displayName)
 		}
+		return m, tea.Batch(index, m.tagFight(archivedRunPath, displayName, mapName, msg.Stats), m.toastArchived(msg), handoff,
+			m.tallyFight(archivedRunPath, msg.Log))
+
+	case tallyTickMsg:
+		return m, m.handleTallyTick()
+
+	case fightArchiveFailedMsg:
+		m.handleFightArchiveFailed(msg)
 		return m, nil
 
+	case remoteArchivedMsg:
+		return m, m.handleRemoteArchived(msg)
+
+	case RunRenamedMsg:
+		m.applyRunRename(msg)
+		commander := processor.ParseRunName(filepath.Base(msg.NewPath)).Commander
+		return m, tea.Batch(m.nextParses(), m.updateRunMeta(msg.NewPath, func(meta *processor.RunMeta) {
+			meta.Commander = commander
+		}))
+
+	case RunMetaLoadedMsg:
+		if msg.RunPath == m.currentRunPath {
+			m.runMeta = msg.Meta
+		}
+		return m, nil
+
+	case MatchupFetchedMsg:
+		return m, m.applyMatchup(msg)
+
+	case objectivesPolledMsg:
+		return m, m.handleObjectivesPolled(msg)
+
+	case periodReportMsg:
+		m.handlePeriodReport(msg)
+		return m, nil
+
+	case objectivePollMsg:
+		return m, m.handleObjectivePoll(msg)
+
+	case MapStateMsg:
+		return m, m.handleMapState(msg)
+
+	case logoffCheckMsg:
+		return m, m.handleLogoffCheck()
+
+	case parseProgressMsg:
+		return m, m.handleParseProgress(msg)
+
+	case runKillsMsg:
+		return m, m.handleRunKills(msg)
+
+	case diagnosticsMsg:
+		m.handleDiagnostics(msg)
+		return m, nil
+
+	case spinnerTickMsg:
+		return m, m.handleSpinnerTick()
+
+	case ToastMsg:
+		return m, m.toast(string(msg))
+	case toastExpiredMsg:
+		m.handleToastExpired(msg)
 	case StatusMsg:
-		m.status = string(msg)
+		m.setStatus(string(msg))
 	case ErrMsg:
-		m.err = msg.Err
+		m.setError(msg.Err)
 	case tea.KeyMsg:
+		if m.showHelp {
+			return m.handleHelpKeys(msg)
+		}
+		if keyHelp.matches(msg) {
+			m.showHelp = true
+			return m, nil
+		}
+		if m.showEventLog {
+			return m.handleEventLogKeys(msg)
+		}
+		if m.showDiagnostics {
+			return m.handleDiagnosticsKeys(msg)
+		}
+		switch {
+		case keyEventLog.matches(msg):
+			m.showEventLog = true
+			return m, nil
+		case keyAnonymize.matches(msg):
+			return m, m.toggleAnonymize()
+		case keyNameDisplay.matches(msg):
+			return m, m.toggleNameDisplay()
+		case keyCancelParse.matches(msg):
+			m.cancelParse()
+			return m, nil
+		case keyDiagnostics.matches(msg):
+			return m, m.toggleDiagnostics()
+		case keyTroubleshoot.matches(msg):
+			if remedy(m.err) != "" {
+				return m, m.openHelp()
+			}
+		case keyFixEIConf.matches(msg):
+			if len(m.eiConfIssues) > 0 {
+				return m, m.fixEIConf()
+			}
+		}
 		switch m.focusedPanel {
 		case leftPanel:
 			return m.handleLeftPanelKeys(msg)
@@ -177,60 +339,216 @@ func (m model) handleLeftPanelKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	currentListSize := m.getCurrentListSize()
 
-	switch msg.String() {
-	case "q", "ctrl+c":
+	switch {
+	case keyQuit.matches(msg):
 		return m, tea.Quit
-	case "w", "up", "k":
+	case keyUp.matches(msg):
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "s", "down", "j":
+	case keyDown.matches(msg):
 		if m.selectedIndex < currentListSize-1 {
 			m.selectedIndex++
 		}
-	case "d", "right", "l":
+	case keyPageUp.matches(msg):
+		m.selectedIndex -= m.listPageSize()
+		if m.selectedIndex < 0 {
+			m.selectedIndex = 0
+		}
+	case keyPageDown.matches(msg):
+		m.selectedIndex += m.listPageSize()
+		if m.selectedIndex > currentListSize-1 {
+			m.selectedIndex = currentListSize - 1
+		}
+	case keyHome.matches(msg):
+		m.selectedIndex = 0
+	case keyEnd.matches(msg):
+		m.selectedIndex = currentListSize - 1
+	case keyFocusRight.matches(msg):
 		m.focusedPanel = rightPanel
-	case "ctrl+d":
-		if m.viewMode == runsView && m.selectedIndex > 0 {
-			runName := m.runList[m.selectedIndex-1]
-			m.confirming = true
-			m.confirmationType = confirmDeleteRun
-			m.itemToDelete = filepath.Join(processor.LogArchive, runName)
-			m.status = fmt.Sprintf("Delete run '%s'? (y/N)", runName)
-		} else if m.viewMode == logsView && m.selectedIndex > 0 {
-			logName := m.logList[m.selectedIndex-1]
-			m.confirming = true
-			m.confirmationType = confirmDeleteLog
-			m.itemToDelete = logName
-			m.status = fmt.Sprintf("Delete log '%s'? (y/N)", logName)
-		}
-	case "enter", " ":
+	case keySelect.matches(msg):
 		cmd = m.handleSelection()
+	case keyFolder.matches(msg):
+		cmd = m.openRunFolder()
+	case m.viewMode == runsView:
+		cmd = m.handleRunListKeys(msg)
+	default:
+		cmd = m.handleLogListKeys(msg)
 	}
-	return m, cmd
+	// Fights not kept in memory are parsed again when highlighted, runs are previewed
+	return m, tea.Batch(cmd, m.loadSelectedLog(), m.previewSelectedRun())
+}
+
+// handleRunListKeys handles the keys only the run list has.
+func (m *model) handleRunListKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case keyDeleteRun.matches(msg):
+		if runName := m.selectedRun(); runName != "" {
+			m.confirmDeleteRun(runName)
+		}
+	case keySort.matches(msg), keyReverseSort.matches(msg):
+		return m.cycleRunSort(keyReverseSort.matches(msg))
+	case keyGroupRuns.matches(msg):
+		m.cycleRunGroup()
+	case keyPeriod.matches(msg):
+		return m.togglePeriodReport()
+	}
+	return nil
+}
+
+// handleLogListKeys handles the keys only a run's log list has.
+func (m *model) handleLogListKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case keyDeleteLog.matches(msg):
+		if m.selectedIndex > 0 {
+			m.confirmDeleteLog(m.logList[m.selectedIndex-1])
+		}
+	case keySort.matches(msg), keyReverseSort.matches(msg):
+		m.cycleLogSort(keyReverseSort.matches(msg))
+	case keyGvG.matches(msg):
+		return m.toggleGvG()
+	case keyBenchmark.matches(msg):
+		return m.saveBenchmark()
+	case keyReparse.matches(msg):
+		return m.reparseSelectedFight()
+	case keyJSON.matches(msg):
+		return m.openSelectedJSON()
+	}
+	return nil
 }
 
 func (m model) handleRightPanelKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "q", "ctrl+c":
+	if m.arranging {
+		return m.handleArrangeKeys(msg)
+	}
+	if m.zoomed {
+		return m.handleZoomKeys(msg)
+	}
+	if m.replaying {
+		return m.handleReplayKeys(msg)
+	}
+	switch {
+	case keyQuit.matches(msg):
 		return m, tea.Quit
-	case "a", "left", "h":
+	case keyFocusLeft.matches(msg):
 		m.focusedPanel = leftPanel
-	case "w", "up", "k":
+	case keyCardUp.matches(msg):
 		if m.selectedCard > 0 {
 			m.selectedCard--
 		}
-	case "s", "down", "j":
-		if m.selectedCard < 8 {
+	case keyCardDown.matches(msg):
+		if m.selectedCard < len(m.activeLayout().flat())-1 {
 			m.selectedCard++
 		}
-	case "enter", " ":
+	case keyArrange.matches(msg):
+		m.arranging = true
+		m.setStatus(i18n.T("status.arrange"))
+	case keyZoom.matches(msg):
 		if m.viewMode == logsView && m.selectedIndex > 0 {
-			displayName := m.logList[m.selectedIndex-1]
-			jsonFullPath := m.logFullPaths[displayName]
-			htmlPath := strings.Replace(jsonFullPath, ".json", ".html", 1)
-			return m, openFile(htmlPath)
+			m.zoomed = true
+			m.zoomOffset = 0
 		}
+	case keyReport.matches(msg):
+		return m, m.openSelectedReport()
+	case keyFolder.matches(msg):
+		return m, m.openRunFolder()
+	case keyJSON.matches(msg):
+		return m, m.openSelectedJSON()
+	case keyPhase.matches(msg):
+		m.cyclePhase()
+	case keyCommander.matches(msg):
+		m.cycleCommander()
+	case keyNPCDamage.matches(msg):
+		return m, m.toggleNPCDamage()
+	case keyReplay.matches(msg):
+		m.openReplay()
+	case keyPositions.matches(msg):
+		return m, m.exportPositions()
+	case keyRenameRun.matches(msg):
+		return m, m.renameRunForCommander()
+	}
+	return m, nil
+}
+
+// exportPositions writes the selected fight's combat replay positions as CSV and
+// GeoJSON next to its JSON in the run folder.
+func (m *model) exportPositions() tea.Cmd {
+	log := m.selectedLog()
+	if log == nil {
+		return nil
+	}
+	jsonPath := m.logFullPaths[m.logList[m.selectedIndex-1]]
+	return func() tea.Msg {
+		csvPath, geoPath, err := positions.Export(jsonPath, log)
+		if err != nil {
+			return ErrMsg{Err: err}
+		}
+		return StatusMsg(i18n.T("status.positions_exported", filepath.Base(csvPath), filepath.Base(geoPath)))
+	}
+}
+
+// openSelectedReport opens the EI HTML report of the selected log in the browser.
+func (m *model) openSelectedReport() tea.Cmd {
+	if m.viewMode != logsView || m.selectedIndex == 0 {
+		return nil
+	}
+	displayName := m.logList[m.selectedIndex-1]
+	if m.config.ServeReports && m.config.HTTPEnabled {
+		port := m.config.HTTPPort
+		if port <= 0 {
+			port = server.DefaultPort
+		}
+		reportURL := fmt.Sprintf("http://localhost:%d/fights/%s/report", port, url.PathEscape(displayName))
+		return m.openInBrowser(reportURL, i18n.T("status.opening_report", reportURL))
+	}
+	jsonFullPath := m.logFullPaths[displayName]
+	htmlPath := strings.Replace(jsonFullPath, ".json", ".html", 1)
+	return m.openInBrowser(htmlPath, i18n.T("status.opening_report", htmlPath))
+}
+
+// openRunFolder opens the highlighted run, or the run being viewed, in the file explorer.
+func (m *model) openRunFolder() tea.Cmd {
+	var runPath string
+	switch {
+	case m.viewMode == runsView && m.selectedRun() != "":
+		runPath = filepath.Join(processor.LogArchive, m.selectedRun())
+	case m.viewMode == logsView:
+		runPath = m.currentRunPath
+	}
+	if runPath == "" {
+		return nil
+	}
+	return openFile(runPath, i18n.T("status.opening_folder", runPath))
+}
+
+// openSelectedJSON opens the EI JSON of the selected log, e.g. to check a stat by hand.
+func (m *model) openSelectedJSON() tea.Cmd {
+	if m.viewMode != logsView || m.selectedIndex == 0 {
+		return nil
+	}
+	jsonPath := m.logFullPaths[m.logList[m.selectedIndex-1]]
+	return openFile(jsonPath, i18n.T("status.opening_json", jsonPath))
+}
+
+func (m model) handleEventLogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case keyQuit.matches(msg):
+		return m, tea.Quit
+	case keyCloseEventLog.matches(msg):
+		m.showEventLog = false
+		m.events.offset = 0
+	case keyUp.matches(msg):
+		m.events.scroll(1)
+	case keyDown.matches(msg):
+		m.events.scroll(-1)
+	case keyPageUp.matches(msg):
+		m.events.scroll(10)
+	case keyPageDown.matches(msg):
+		m.events.scroll(-10)
+	case keyOldest.matches(msg):
+		m.events.scroll(len(m.events.entries))
+	case keyNewest.matches(msg):
+		m.events.offset = 0
 	}
 	return m, nil
 }
@@ -238,24 +556,28 @@ func (m model) handleRightPanelKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m *model) handleSelection() tea.Cmd {
 	if m.viewMode == runsView {
 		if m.selectedIndex == 0 { // "New Run"
-			timestamp := time.Now().Format("2006-01-02_15-04-05")
-			runName := fmt.Sprintf("UnknownCommander_%s", timestamp)
+			now := time.Now()
+			runName := fmt.Sprintf("UnknownCommander_%s", m.runTimestamp(now))
 			m.currentRunPath = filepath.Join(processor.LogArchive, runName)
 			m.currentRunName = runName
 			m.viewMode = logsView
 			m.clearCurrentRun()
-			m.status = "New run created. Waiting for logs."
-			return func() tea.Msg {
-				// Ensure the directory gets created on disk
-				return os.MkdirAll(m.currentRunPath, 0755)
-			}
+			m.setStatus(i18n.T("status.run_created"))
+			// Saving run.json also creates the directory on disk
+			return m.updateRunMeta(m.currentRunPath, func(meta *processor.RunMeta) {
+				meta.Created = now.UTC()
+				meta.Started = now.UTC()
+			})
+		} else if row := m.selectedRunRow(); row.run == "" { // A group header
+			m.toggleRunGroup(row.group)
+			return nil
 		} else { // A run from the list
-			runName := m.runList[m.selectedIndex-1]
+			runName := row.run
 			m.currentRunPath = filepath.Join(processor.LogArchive, runName)
 			m.currentRunName = runName
 			m.viewMode = logsView
 			m.clearCurrentRun()
-			m.status = fmt.Sprintf("Loading logs for run: %s", runName)
+			m.setStatus(i18n.T("status.loading_run", processor.RunLabel(runName)))
 			return loadLogsInRun(m.currentRunPath)
 		}
 	} else { // logsView
@@ -275,7 +597,7 @@ func (m *model) handleSelection() tea.Cmd {
 
 func (m *model) getCurrentListSize() int {
 	if m.viewMode == runsView {
-		return len(m.runList) + 1 // +1 for "New Run"
+		return len(m.runRows) + 1 // +1 for "New Run"
 	}
 	return len(m.logList) + 1 // +1 for "../"
 }
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"gw2-cmd-watch/parser"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// zoomViewportRows is the number of card lines visible in the zoomed view.
+func (m *model) zoomViewportRows() int {
+	rows := m.height - 5 - 2 // right panel height minus the card border
+	if rows < 3 {
+		rows = 3
+	}
+	return rows
+}
+
+// zoomedCardLines renders the selected card with every row and splits it into lines.
+func (m *model) zoomedCardLines(log *parser.ParsedLog) []string {
+	ids := m.activeLayout().flat()
+	if m.selectedCard >= len(ids) {
+		return nil
+	}
+	card, _ := findCard(ids[m.selectedCard])
+	full := *m
+	full.showAllRows = true
+	return strings.Split(strings.TrimRight(card.Build(&full, log), "\n"), "\n")
+}
+
+func (m *model) renderZoomedCard(log *parser.ParsedLog) string {
+	lines := m.zoomedCardLines(log)
+	rows := m.zoomViewportRows()
+	offset := m.zoomOffset
+	if offset > len(lines)-1 {
+		offset = len(lines) - 1
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// Keep the card title pinned while the rows scroll underneath it
+	var visible []string
+	if len(lines) > 0 {
+		visible = append(visible, lines[0])
+		body := lines[1:]
+		start := offset
+		if start > len(body) {
+			start = len(body)
+		}
+		end := start + rows - 1
+		if end > len(body) {
+			end = len(body)
+		}
+		visible = append(visible, body[start:end]...)
+	}
+
+	width := m.styles.RightPanel.GetWidth() - m.styles.SelectedCard.GetHorizontalFrameSize()
+	card := m.styles.SelectedCard.Width(width).Render(strings.Join(visible, "\n"))
+	return m.styles.RightPanel.Render(lipgloss.JoinVertical(lipgloss.Left, card))
+}
+
+func (m model) handleZoomKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.zoomViewportRows() - 1
+	switch {
+	case keyQuit.matches(msg):
+		return m, tea.Quit
+	case keyUnzoom.matches(msg):
+		m.zoomed = false
+		m.zoomOffset = 0
+	case keyUp.matches(msg):
+		if m.zoomOffset > 0 {
+			m.zoomOffset--
+		}
+	case keyDown.matches(msg):
+		m.zoomOffset++
+	case keyPageUp.matches(msg):
+		m.zoomOffset -= page
+		if m.zoomOffset < 0 {
+			m.zoomOffset = 0
+		}
+	case keyPageDown.matches(msg):
+		m.zoomOffset += page
+	case keyHome.matches(msg):
+		m.zoomOffset = 0
+	case keyEnd.matches(msg):
+		m.zoomOffset = 1 << 30 // Clamped to the last line when rendered
+	case keyReport.matches(msg):
+		return m, m.openSelectedReport()
+	case keyJSON.matches(msg):
+		return m, m.openSelectedJSON()
+	}
+	m.clampZoomOffset()
+	return m, nil
+}
+
+// clampZoomOffset keeps the scroll position within the zoomed card's rows.
+func (m *model) clampZoomOffset() {
+	if m.viewMode != logsView || m.selectedIndex == 0 || m.selectedIndex > len(m.logList) {
+		return
+	}
+	log := m.logs[m.logFullPaths[m.logList[m.selectedIndex-1]]]
+	if log == nil {
+		return
+	}
+	bodyLines := len(m.zoomedCardLines(log)) - 1
+	maxOffset := bodyLines - (m.zoomViewportRows() - 1)
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	if m.zoomOffset > maxOffset {
+		m.zoomOffset = maxOffset
+	}
+	if m.zoomOffset < 0 {
+		m.zoomOffset = 0
+	}
+}
@@ -3,6 +3,7 @@ package updater
 import (
 	"encoding/json"
 	"fmt"
+	"gw2-cmd-watch/logger"
 	"net/http"
 )
 
@@ -46,6 +47,8 @@ func CheckForUpdates() (*UpdateInfo, error) {
 		return nil, fmt.Errorf("failed to parse release info: %w", err)
 	}
 
+	logger.Debug("latest release %s (prerelease=%t), running %s", release.TagName, release.PreRelease, currentVersion)
+
 	// Simple version comparison (e.g., "v0.2.0" > "v0.1.0")
 	if !release.PreRelease && release.TagName > currentVersion {
 		return &UpdateInfo{URL: release.HTMLURL}, nil
@@ -0,0 +1,96 @@
+package watcher
+
+import (
+	"fmt"
+	"gw2-cmd-watch/logger"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchArchive reports changes made to the archive from outside the app, e.g. run
+// folders deleted or copied in with a file explorer. changed receives archivePath when
+// run folders were added, removed or renamed, and a run folder when fight JSONs in it
+// were. Files in run folders that isFight rejects, such as the app's own run.json and
+// summaries, are ignored. Events are gathered for a moment, so a burst is reported once
+// per folder. WatchArchive returns when the archive can no longer be watched.
+func WatchArchive(archivePath string, isFight func(name string) bool, changed chan<- string) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	root := filepath.Clean(archivePath)
+	if err := w.Add(root); err != nil {
+		return fmt.Errorf("could not watch %s: %w", root, err)
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			if err := w.Add(filepath.Join(root, e.Name())); err != nil {
+				return fmt.Errorf("could not watch %s: %w", e.Name(), err)
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	pending := make(map[string]bool) // Folders with a report on its way
+	report := func(dir string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if pending[dir] {
+			return
+		}
+		pending[dir] = true
+		time.AfterFunc(debounceDelay, func() {
+			mu.Lock()
+			delete(pending, dir)
+			mu.Unlock()
+			changed <- dir
+		})
+	}
+
+	for {
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return fmt.Errorf("stopped watching %s", root)
+			}
+			// Files being written don't change what is listed
+			if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			name := filepath.Clean(event.Name)
+			if name == root {
+				return fmt.Errorf("the archive %s was removed or renamed", root)
+			}
+			switch dir := filepath.Dir(name); {
+			case dir == root:
+				if event.Op&fsnotify.Create != 0 {
+					info, err := os.Stat(name)
+					if err != nil || !info.IsDir() {
+						continue
+					}
+					if err := w.Add(name); err != nil {
+						logger.Error("adding new run folder to archive watcher: %v", err)
+					}
+				}
+				report(root)
+			case filepath.Dir(dir) == root && isFight(filepath.Base(name)):
+				report(dir)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return fmt.Errorf("stopped watching %s", root)
+			}
+			logger.Error("archive watcher error: %v", err)
+		}
+	}
+}
@@ -1,20 +1,26 @@
 package watcher
 
 import (
-	"log"
+	"fmt"
+	"gw2-cmd-watch/apperr"
+	"gw2-cmd-watch/logger"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
 
-// Start initializes and runs the file system watcher.
-func Start(watchPath string, eventChan chan<- string) error {
+// Start initializes and runs the file system watcher. Directories under any of the
+// exclude paths are not watched, e.g. an archive inside a shared folder. It only
+// returns when logs can no longer be watched, with an apperr.WatcherDead error.
+func Start(watchPath string, exclude []string, eventChan chan<- string) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		return err
+		return apperr.New(apperr.WatcherDead, fmt.Errorf("could not start watching for logs: %w", err))
 	}
 	defer watcher.Close()
 
@@ -24,67 +30,259 @@ func Start(watchPath string, eventChan chan<- string) error {
 			return err
 		}
 		if info.IsDir() {
+			if isExcluded(path, exclude) {
+				return filepath.SkipDir
+			}
 			return watcher.Add(path)
 		}
 		return nil
 	})
 	if err != nil {
-		return err
+		return apperr.New(apperr.WatcherDead, fmt.Errorf("could not watch %s: %w", watchPath, err))
 	}
+	logger.Info("watching %s for new logs", watchPath)
+	files := newTracker(eventChan)
+	stopped := apperr.New(apperr.WatcherDead, fmt.Errorf("stopped watching %s for new logs", watchPath))
 
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					return
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return stopped
+			}
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && filepath.Clean(event.Name) == filepath.Clean(watchPath) {
+				return apperr.New(apperr.WatcherDead, fmt.Errorf("the watch folder %s was removed or renamed", watchPath))
+			}
+			// New and renamed-in files show up as Create, files still being written
+			// as Write. Both are debounced until the file stops changing.
+			if event.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+				continue
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				info, err := os.Stat(event.Name)
+				if err != nil {
+					// File might be gone again, ignore
+					continue
 				}
-				// We only care about new files being created.
-				if event.Op&fsnotify.Create == fsnotify.Create {
-					// Check if it's a directory or a file
-					info, err := os.Stat(event.Name)
-					if err != nil {
-						// File might be gone again, ignore
+				if info.IsDir() {
+					if isExcluded(event.Name, exclude) {
 						continue
 					}
-
-					if info.IsDir() {
-						// New directory created, add it to the watcher
-						if err := watcher.Add(event.Name); err != nil {
-							log.Printf("Error adding new directory to watcher: %v", err)
-						}
-						continue
-					}
-
-					// We are only interested in .zevtc files
-					if strings.HasSuffix(strings.ToLower(event.Name), ".zevtc") {
-						go func(filePath string) {
-							// Poll the file until it's no longer locked
-							for {
-								file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
-								if err == nil {
-									// Success, file is not locked
-									file.Close()
-									absPath, _ := filepath.Abs(filePath)
-									eventChan <- absPath
-									break
-								}
-								// Wait a bit before trying again
-								time.Sleep(250 * time.Millisecond)
-							}
-						}(event.Name)
+					// New directory created, add it to the watcher
+					if err := watcher.Add(event.Name); err != nil {
+						logger.Error("adding new directory to watcher: %v", err)
 					}
+					continue
 				}
-			case err, ok := <-watcher.Errors:
-				if !ok {
+			}
+
+			// We are only interested in .zevtc files
+			if strings.HasSuffix(strings.ToLower(event.Name), ".zevtc") {
+				files.touch(event.Name)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return stopped
+			}
+			logger.Error("watcher error: %v", err)
+		}
+	}
+}
+
+const (
+	// debounceDelay is how long a log must go without events before it is checked.
+	debounceDelay = 500 * time.Millisecond
+	// A log is complete once its size is unchanged for stableChecks polls in a row
+	// and it can be opened.
+	stablePoll   = 250 * time.Millisecond
+	stableChecks = 2
+)
+
+// tracker turns a burst of events for one log into a single report once the file is
+// complete. arcdps can fire several Create and Write events per log, and logs renamed
+// into place from a temp file must not be read half-written.
+type tracker struct {
+	out chan<- string
+
+	mu       sync.Mutex
+	timers   map[string]*time.Timer // Debounce timer per path
+	inFlight map[string]bool        // Paths waiting for their size to settle
+	sent     map[string]time.Time   // Modification time of each path already reported
+}
+
+func newTracker(out chan<- string) *tracker {
+	return &tracker{
+		out:      out,
+		timers:   make(map[string]*time.Timer),
+		inFlight: make(map[string]bool),
+		sent:     make(map[string]time.Time),
+	}
+}
+
+// touch records an event for path and (re)starts its debounce timer.
+func (t *tracker) touch(path string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inFlight[absPath] {
+		return // The stability check will see the new writes
+	}
+	if timer, ok := t.timers[absPath]; ok {
+		timer.Reset(debounceDelay)
+		return
+	}
+	t.timers[absPath] = time.AfterFunc(debounceDelay, func() {
+		t.mu.Lock()
+		delete(t.timers, absPath)
+		t.inFlight[absPath] = true
+		t.mu.Unlock()
+		t.waitStable(absPath)
+	})
+}
+
+// waitStable reports path once it has stopped growing and is no longer locked. Files
+// that disappear, e.g. a temp file renamed away, are dropped.
+func (t *tracker) waitStable(path string) {
+	defer func() {
+		t.mu.Lock()
+		delete(t.inFlight, path)
+		t.mu.Unlock()
+	}()
+	var lastSize int64 = -1
+	same := 0
+	for {
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Debug("log vanished before it was complete: %s", path)
+			return
+		}
+		if info.Size() > 0 && info.Size() == lastSize {
+			same++
+		} else {
+			same = 0
+		}
+		lastSize = info.Size()
+		if same >= stableChecks {
+			if file, err := os.OpenFile(path, os.O_RDONLY, 0644); err == nil {
+				file.Close()
+				t.mu.Lock()
+				done := t.sent[path].Equal(info.ModTime())
+				t.sent[path] = info.ModTime()
+				t.mu.Unlock()
+				if done {
+					logger.Debug("ignoring repeated events for %s", path)
 					return
 				}
-				log.Printf("Watcher error: %v", err)
+				logger.Info("new log detected: %s", path)
+				deliver(t.out, path)
+				return
 			}
 		}
-	}()
+		time.Sleep(stablePoll)
+	}
+}
+
+// pause holds detected logs back while watching is paused.
+var pause struct {
+	sync.Mutex
+	on   bool
+	held []string
+	out  chan<- string
+}
+
+// SetPaused pauses or resumes reporting new logs. Logs detected while paused are
+// reported, in order, when watching resumes.
+func SetPaused(on bool) {
+	pause.Lock()
+	pause.on = on
+	held, out := pause.held, pause.out
+	if !on {
+		pause.held = nil
+	}
+	pause.Unlock()
+	if !on && len(held) > 0 {
+		logger.Info("watching resumed, queueing %d held logs", len(held))
+		go func() {
+			for _, path := range held {
+				out <- path
+			}
+		}()
+	}
+}
+
+// Paused reports whether watching is paused.
+func Paused() bool {
+	pause.Lock()
+	defer pause.Unlock()
+	return pause.on
+}
+
+func deliver(out chan<- string, path string) {
+	pause.Lock()
+	if pause.on {
+		pause.held = append(pause.held, path)
+		pause.out = out
+		pause.Unlock()
+		logger.Info("watching paused, holding %s", path)
+		return
+	}
+	pause.Unlock()
+	out <- path
+}
+
+// isExcluded reports whether path is one of the excluded directories or inside one.
+func isExcluded(path string, exclude []string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, ex := range exclude {
+		exAbs, err := filepath.Abs(ex)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(exAbs, abs)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Block forever
-	<-make(chan struct{})
-	return nil
+// Scan returns the .zevtc files under watchPath modified after since, oldest first,
+// for logs recorded while the app was not running.
+func Scan(watchPath string, exclude []string, since time.Time) ([]string, error) {
+	type found struct {
+		path    string
+		modTime time.Time
+	}
+	var logs []found
+	err := filepath.Walk(watchPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if isExcluded(path, exclude) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(strings.ToLower(path), ".zevtc") && info.ModTime().After(since) {
+			abs, _ := filepath.Abs(path)
+			logs = append(logs, found{abs, info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].modTime.Before(logs[j].modTime) })
+	paths := make([]string, len(logs))
+	for i, l := range logs {
+		paths[i] = l.path
+	}
+	return paths, nil
 }